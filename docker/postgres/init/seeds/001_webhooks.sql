@@ -0,0 +1,42 @@
+-- Outbound webhooks: subscriptions, the delivery queue and its dead letters.
+
+create table if not exists webhook_subscriptions (
+  id          bigserial primary key,
+  url         text not null,
+  event_types jsonb not null default '[]',  -- e.g. ["auction.outbid"]; [] = all
+  secret      text not null,
+  active      boolean not null default true,
+  created_at  timestamptz not null default now(),
+  updated_at  timestamptz not null default now()
+);
+
+create table if not exists webhook_deliveries (
+  id              bigserial primary key,
+  subscription_id bigint not null references webhook_subscriptions(id) on delete cascade,
+  event_key       text not null,                -- "<auction_id>:<seq>", dedupes instances
+  event_type      text not null,
+  payload         jsonb not null,
+  status          text not null default 'pending', -- pending | delivered | dead
+  attempts        int  not null default 0,
+  next_attempt_at timestamptz not null default now(),
+  last_status     int,
+  last_error      text,
+  created_at      timestamptz not null default now(),
+  delivered_at    timestamptz,
+  unique (subscription_id, event_key)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx
+  ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+create table if not exists webhook_dead_letters (
+  id              bigserial primary key,
+  delivery_id     bigint not null references webhook_deliveries(id) on delete cascade,
+  subscription_id bigint not null references webhook_subscriptions(id) on delete cascade,
+  event_type      text not null,
+  payload         jsonb not null,
+  attempts        int  not null,
+  last_status     int,
+  last_error      text,
+  failed_at       timestamptz not null default now()
+);
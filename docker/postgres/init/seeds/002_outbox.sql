@@ -0,0 +1,19 @@
+-- Transactional outbox: side effects of a state change, written in the same
+-- transaction and carried out (at least once) by the relay.
+
+create table if not exists outbox (
+  id              bigserial primary key,
+  kind            text not null,          -- e.g. auction.finished
+  aggregate_id    text not null,          -- auction ID; rows of one aggregate run in order
+  payload         jsonb not null,
+  created_at      timestamptz not null default now(),
+  attempts        int not null default 0,
+  next_attempt_at timestamptz not null default now(),
+  last_error      text,
+  sent_at         timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS outbox_unsent_idx
+  ON outbox (next_attempt_at, id) WHERE sent_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_aggregate_unsent_idx
+  ON outbox (aggregate_id, id) WHERE sent_at IS NULL;
@@ -0,0 +1,16 @@
+-- Append‑only auction history: every lifecycle and bid event, as published,
+-- with the server (Redis TIME) timestamp it happened at.
+
+create table if not exists auction_events (
+  id          bigserial primary key,
+  auction_id  text not null,
+  seq         bigint not null,             -- per‑auction event sequence; 0 = created (Postgres only)
+  kind        text not null,               -- created | start | bid | extended | stop
+  actor       text not null default '',    -- seller, bidder, or 'system'
+  payload     jsonb not null,              -- the typed event body
+  occurred_at timestamptz not null,
+  recorded_at timestamptz not null default now(),
+  unique (auction_id, seq)
+);
+
+CREATE INDEX IF NOT EXISTS auction_events_time_idx ON auction_events (auction_id, occurred_at);
@@ -0,0 +1,2 @@
+-- per‑bidder history (GET /bidders/{id}/bids)
+CREATE INDEX IF NOT EXISTS bids_bidder_id_idx ON bids (bidder_id, placed_at);
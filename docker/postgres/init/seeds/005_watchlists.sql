@@ -0,0 +1,9 @@
+-- Auctions a user follows; drives the watchlist notifications.
+create table if not exists watchlists (
+  user_id    text not null,
+  auction_id text not null references auctions(id) on delete cascade,
+  created_at timestamptz not null default now(),
+  primary key (user_id, auction_id)
+);
+
+CREATE INDEX IF NOT EXISTS watchlists_auction_id_idx ON watchlists (auction_id);
@@ -0,0 +1,22 @@
+-- Every outgoing email notification, queued and retried by internal/mailer.
+create table if not exists notifications (
+  id              bigserial primary key,
+  dedupe_key      text not null unique,  -- one mail per event and recipient
+  user_id         text not null,
+  kind            text not null,         -- winner, seller_sold, outbid, …
+  auction_id      text not null,
+  recipient       text not null,
+  subject         text not null,
+  body_text       text not null,
+  body_html       text not null,
+  status          text not null default 'pending', -- pending | sent | failed
+  attempts        int  not null default 0,
+  last_error      text,
+  next_attempt_at timestamptz not null default now(),
+  created_at      timestamptz not null default now(),
+  sent_at         timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS notifications_due_idx
+  ON notifications (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS notifications_user_idx ON notifications (user_id, created_at);
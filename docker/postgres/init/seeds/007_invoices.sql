@@ -0,0 +1,31 @@
+-- Settlement: one buyer invoice and one seller statement per sold auction.
+create sequence if not exists invoice_number_seq;
+
+create table if not exists invoices (
+  id         bigserial primary key,
+  number     text not null unique,              -- INV-2026-000042
+  auction_id text not null,
+  party      text not null,                     -- buyer | seller
+  user_id    text not null,
+  subtotal   numeric not null,
+  tax        numeric not null,
+  total      numeric not null,                  -- due (buyer) / payout (seller)
+  status     text not null default 'issued',    -- issued | paid | void
+  issued_at  timestamptz not null default now(),
+  paid_at    timestamptz,
+  voided_at  timestamptz
+);
+
+-- settlement is idempotent: one live invoice per auction and party
+CREATE UNIQUE INDEX IF NOT EXISTS invoices_live_uq
+  ON invoices (auction_id, party) WHERE status <> 'void';
+CREATE INDEX IF NOT EXISTS invoices_user_idx ON invoices (user_id, issued_at);
+
+create table if not exists invoice_lines (
+  invoice_id  bigint not null references invoices(id) on delete cascade,
+  position    int    not null,
+  kind        text   not null,   -- hammer | buyers_premium | commission | tax
+  description text   not null,
+  amount      numeric not null,  -- signed: deductions are negative
+  primary key (invoice_id, position)
+);
@@ -0,0 +1,20 @@
+-- Payments of buyer invoices through a PaymentProvider (fakepay locally).
+alter table invoices add column if not exists due_at timestamptz; -- buyer invoices only
+
+create table if not exists payments (
+  id           bigserial primary key,
+  invoice_id   bigint  not null references invoices(id),
+  provider     text    not null,
+  intent_id    text    not null unique,
+  amount       numeric not null,
+  refunded     numeric not null default 0,
+  status       text    not null default 'pending', -- pending | authorized | captured | failed | refunded
+  checkout_url text    not null,
+  last_error   text,
+  created_at   timestamptz not null default now(),
+  updated_at   timestamptz not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS payments_invoice_idx ON payments (invoice_id);
+CREATE INDEX IF NOT EXISTS invoices_due_idx
+  ON invoices (due_at) WHERE party = 'buyer' AND status = 'issued';
@@ -0,0 +1,22 @@
+-- Bidder credit: a limit and/or pre‑authorised deposit per user; cached in
+-- Redis ("credit:<user>") and enforced by auction_place_bid.
+create table if not exists credit_accounts (
+  user_id      text primary key,
+  credit_limit numeric not null default 0 check (credit_limit >= 0),
+  deposit      numeric not null default 0 check (deposit >= 0),
+  updated_at   timestamptz not null default now()
+);
+
+-- A won auction's lead becomes a charge, open until the invoice is paid.
+create table if not exists credit_charges (
+  id         bigserial primary key,
+  user_id    text    not null,
+  auction_id text    not null unique references auctions(id) on delete cascade,
+  amount     numeric not null,
+  status     text    not null default 'open', -- open | settled
+  created_at timestamptz not null default now(),
+  settled_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS credit_charges_open_idx
+  ON credit_charges (user_id) WHERE status = 'open';
@@ -0,0 +1,32 @@
+-- Second‑chance offers: an UNPAID auction offered to a runner‑up at that
+-- bidder's last price, plus an append‑only audit trail of the flow.
+create table if not exists second_chance_offers (
+  id           bigserial primary key,
+  auction_id   text    not null references auctions(id) on delete cascade,
+  bidder_id    text    not null,
+  amount       numeric not null,
+  status       text    not null default 'open', -- open | accepted | declined | expired
+  expires_at   timestamptz not null,
+  created_at   timestamptz not null default now(),
+  responded_at timestamptz,
+  unique (auction_id, bidder_id)                -- each runner‑up is asked once
+);
+
+-- at most one open offer per auction
+CREATE UNIQUE INDEX IF NOT EXISTS second_chance_open_uq
+  ON second_chance_offers (auction_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS second_chance_expiry_idx
+  ON second_chance_offers (expires_at) WHERE status = 'open';
+
+create table if not exists second_chance_audit (
+  id         bigserial primary key,
+  auction_id text  not null references auctions(id) on delete cascade,
+  offer_id   bigint references second_chance_offers(id) on delete cascade,
+  action     text  not null, -- offered | accepted | declined | expired | resettled
+  actor      text  not null, -- user ID, or "system"
+  detail     jsonb not null default '{}',
+  at         timestamptz not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS second_chance_audit_auction_idx
+  ON second_chance_audit (auction_id, id);
@@ -0,0 +1,35 @@
+-- Make‑an‑offer: private offers on best‑offer listings and unsold auctions.
+alter table auctions add column if not exists best_offer         boolean not null default false;
+alter table auctions add column if not exists offer_auto_accept  numeric; -- offers >= this are accepted
+alter table auctions add column if not exists offer_auto_decline numeric; -- offers <  this are declined
+
+create table if not exists offers (
+  id         bigserial primary key,
+  auction_id text    not null references auctions(id) on delete cascade,
+  seller_id  text    not null,
+  buyer_id   text    not null,
+  amount     numeric not null,                  -- the amount currently on the table
+  status     text    not null default 'pending', -- pending | countered | accepted | declined | expired
+  expires_at timestamptz not null,
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now()
+);
+
+-- one live negotiation per buyer and auction
+CREATE UNIQUE INDEX IF NOT EXISTS offers_live_uq
+  ON offers (auction_id, buyer_id) WHERE status IN ('pending', 'countered');
+CREATE INDEX IF NOT EXISTS offers_buyer_idx ON offers (buyer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS offers_expiry_idx
+  ON offers (expires_at) WHERE status IN ('pending', 'countered');
+
+-- every transition of an offer, in order
+create table if not exists offer_events (
+  id       bigserial primary key,
+  offer_id bigint  not null references offers(id) on delete cascade,
+  action   text    not null, -- submit | counter | accept | decline | expire | auto_accept | auto_decline | superseded | relisted
+  actor    text    not null, -- user ID, or "system"
+  amount   numeric,
+  at       timestamptz not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS offer_events_offer_idx ON offer_events (offer_id, id);
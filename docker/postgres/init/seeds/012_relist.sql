@@ -0,0 +1,12 @@
+-- Automatic relisting of unsold auctions: every relist is a new auction
+-- linked to the one it replaces.
+alter table auctions add column if not exists start_price           numeric; -- opening bid, optional
+alter table auctions add column if not exists relisted_from         text references auctions(id) on delete set null;
+alter table auctions add column if not exists relist_cycle          integer not null default 0; -- 0 = original listing
+alter table auctions add column if not exists relist_remaining      integer not null default 0;
+alter table auctions add column if not exists relist_duration       integer;                    -- seconds
+alter table auctions add column if not exists relist_price_drop_pct numeric not null default 0;
+alter table auctions add column if not exists relist_min_price      numeric;                    -- floor of the drops
+
+-- one successor per auction (makes relisting idempotent)
+CREATE UNIQUE INDEX IF NOT EXISTS auctions_relisted_from_uq ON auctions (relisted_from);
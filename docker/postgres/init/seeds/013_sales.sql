@@ -0,0 +1,22 @@
+-- Sales: catalogs of auctions (lots) that open together and close one after
+-- another, lot_interval seconds apart.
+create table if not exists sales (
+  id                text primary key,
+  seller_id         text not null,
+  title             text not null,
+  status            text not null default 'DRAFT', -- DRAFT | PUBLISHED | CLOSED
+  closing_starts_at timestamptz not null,          -- lot 1 closes then
+  lot_interval      integer not null,              -- seconds between two lot closes
+  extend_window     integer not null default 0,    -- a bid this close to a lot's end …
+  extend_by         integer not null default 0,    -- … moves the end to bid time + extend_by
+  created_at        timestamptz not null default now(),
+  published_at      timestamptz,
+  closed_at         timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS sales_status_idx ON sales (status, closing_starts_at);
+
+alter table auctions add column if not exists sale_id text references sales(id) on delete set null;
+alter table auctions add column if not exists lot_no  integer; -- 1‑based position in the sale
+
+CREATE INDEX IF NOT EXISTS auctions_sale_lot_idx ON auctions (sale_id, lot_no);
@@ -0,0 +1,6 @@
+-- Live auctions: closed by an auctioneer ("sold" / "pass") instead of a timer.
+alter table auctions add column if not exists live boolean not null default false;
+
+-- Floor and phone bids are entered by a clerk on the bidder's behalf.
+alter table bids add column if not exists source     text not null default 'online'; -- online | floor | phone | absentee
+alter table bids add column if not exists entered_by text;                            -- clerk user ID
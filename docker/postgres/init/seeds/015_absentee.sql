@@ -0,0 +1,18 @@
+-- Absentee (commission) bids: maxima left on a PENDING auction, resolved in
+-- submission order when it starts. The resulting bids are stored with
+-- bids.source = 'absentee'.
+create table if not exists absentee_bids (
+  id           bigserial primary key,
+  auction_id   text    not null references auctions(id) on delete cascade,
+  bidder_id    text    not null,
+  max_amount   numeric not null,
+  status       text    not null default 'ACTIVE', -- ACTIVE | CANCELLED | PLACED
+  created_at   timestamptz not null default now(), -- submission order
+  cancelled_at timestamptz,
+  placed_at    timestamptz                         -- handed to the auction at start
+);
+
+-- one standing maximum per bidder and auction
+CREATE UNIQUE INDEX IF NOT EXISTS absentee_bids_active_uq
+  ON absentee_bids (auction_id, bidder_id) WHERE status = 'ACTIVE';
+CREATE INDEX IF NOT EXISTS absentee_bids_bidder_idx ON absentee_bids (bidder_id, created_at DESC);
@@ -0,0 +1,21 @@
+-- Visibility and bidder registration: who sees an auction and who may bid.
+alter table auctions add column if not exists visibility            text    not null default 'public'; -- public | unlisted | private
+alter table auctions add column if not exists registration_required boolean not null default false;    -- always true when private
+
+-- requests to bid; approved bidders get a paddle number
+create table if not exists auction_registrations (
+  auction_id text not null references auctions(id) on delete cascade,
+  bidder_id  text not null,
+  status     text not null default 'PENDING', -- PENDING | APPROVED | REJECTED
+  paddle     integer,                         -- 1, 2, … per auction, kept when revoked
+  note       text,                            -- from the bidder (company, reference, …)
+  created_at timestamptz not null default now(),
+  decided_at timestamptz,
+  primary key (auction_id, bidder_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS auction_registrations_paddle_uq
+  ON auction_registrations (auction_id, paddle);
+CREATE INDEX IF NOT EXISTS auction_registrations_bidder_idx
+  ON auction_registrations (bidder_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS auctions_visibility_idx ON auctions (visibility, ends_at DESC);
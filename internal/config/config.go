@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/caarlos0/env/v11"
 	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
@@ -17,9 +19,74 @@ type Config struct {
 	PostgresPassword string `env:"POSTGRES_PASSWORD" envDefault:"auction_password"`
 	PostgresDb       string `env:"POSTGRES_DB"       envDefault:"auction_db"`
 
-	BidMinIncrement float64 `env:"BID_MIN_INCREMENT" envDefault:"0" validate:"min=0"`
+	BidMinIncrement    float64 `env:"BID_MIN_INCREMENT"    envDefault:"0" validate:"min=0"`
+	CreditDefaultLimit float64 `env:"CREDIT_DEFAULT_LIMIT" envDefault:"-1"` // users without a credit account; -1 = unlimited
 
 	HttpServerPort uint16 `env:"HTTP_SERVER_PORT" envDefault:"8085" validate:"min=1000,max=65535"`
+
+	WsSendQueueSize      int           `env:"WS_SEND_QUEUE_SIZE"      envDefault:"64"          validate:"min=1,max=10000"`
+	WsSlowConsumerPolicy string        `env:"WS_SLOW_CONSUMER_POLICY" envDefault:"drop_oldest" validate:"oneof=drop_oldest coalesce disconnect"`
+	WsTimeSyncInterval   time.Duration `env:"WS_TIME_SYNC_INTERVAL" envDefault:"30s"` // 0 disables the push
+
+	ChatMaxLen          int           `env:"CHAT_MAX_LEN"          envDefault:"500" validate:"min=1,max=1000"`
+	ChatSlowMode        time.Duration `env:"CHAT_SLOW_MODE"        envDefault:"2s"`
+	ChatHistorySize     int           `env:"CHAT_HISTORY_SIZE"     envDefault:"50"  validate:"min=1,max=1000"`
+	ChatBlocklist       []string      `env:"CHAT_BLOCKLIST"        envSeparator:","`
+	ChatBlocklistReject bool          `env:"CHAT_BLOCKLIST_REJECT" envDefault:"false"`
+	ChatModeratorIDs    []string      `env:"CHAT_MODERATOR_IDS"    envSeparator:","`
+
+	// Staff tokens (internal/http/staffauth) prove moderator / auctioneer /
+	// clerk identity on the WS connect; empty = staff actions disabled
+	StaffTokenSecret string `env:"STAFF_TOKEN_SECRET" validate:"omitempty,min=16"`
+
+	AdminAPIToken string `env:"ADMIN_API_TOKEN"` // empty = admin routes disabled (503)
+
+	BidHistoryMaskBidders bool `env:"BID_HISTORY_MASK_BIDDERS" envDefault:"true"` // u***3 unless admin
+
+	WebhookMaxAttempts int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"   validate:"min=1,max=50"`
+	WebhookBackoffBase time.Duration `env:"WEBHOOK_BACKOFF_BASE" envDefault:"2s"`
+	WebhookBackoffMax  time.Duration `env:"WEBHOOK_BACKOFF_MAX"  envDefault:"10m"`
+	WebhookTimeout     time.Duration `env:"WEBHOOK_TIMEOUT"      envDefault:"5s"`
+
+	NotifyChannels     []string        `env:"NOTIFY_CHANNELS"      envSeparator:"," envDefault:"ws,email,webhook" validate:"dive,oneof=ws email webhook"`
+	NotifyEndingSoon   []time.Duration `env:"NOTIFY_ENDING_SOON"   envSeparator:"," envDefault:"1h,10m,1m"`
+	NotifyScanInterval time.Duration   `env:"NOTIFY_SCAN_INTERVAL" envDefault:"5s"`
+	NotifyEmailAddress string          `env:"NOTIFY_EMAIL_ADDRESS" envDefault:"%s@users.localhost" validate:"contains=%s"` // user ID → address
+
+	SmtpAddr        string        `env:"SMTP_ADDR"         envDefault:"localhost:1025"` // MailHog in docker-compose
+	SmtpUsername    string        `env:"SMTP_USERNAME"`                                 // empty = no AUTH
+	SmtpPassword    string        `env:"SMTP_PASSWORD"`
+	SmtpFrom        string        `env:"SMTP_FROM"         envDefault:"auctions@localhost"`
+	MailMaxAttempts int           `env:"MAIL_MAX_ATTEMPTS" envDefault:"6"   validate:"min=1,max=50"`
+	MailBackoffBase time.Duration `env:"MAIL_BACKOFF_BASE" envDefault:"5s"`
+	MailBackoffMax  time.Duration `env:"MAIL_BACKOFF_MAX"  envDefault:"30m"`
+
+	BuyersPremiumPct    float64 `env:"BUYERS_PREMIUM_PCT"    envDefault:"10" validate:"min=0,max=100"`
+	SellerCommissionPct float64 `env:"SELLER_COMMISSION_PCT" envDefault:"5"  validate:"min=0,max=100"`
+	TaxPct              float64 `env:"TAX_PCT"               envDefault:"0"  validate:"min=0,max=100"`
+	TaxOnHammer         bool    `env:"TAX_ON_HAMMER"         envDefault:"false"` // else only fees are taxed
+	InvoiceNumberPrefix string  `env:"INVOICE_NUMBER_PREFIX" envDefault:"INV"`
+
+	PaymentProvider      string        `env:"PAYMENT_PROVIDER"       envDefault:"fake" validate:"oneof=fake"`
+	PaymentDeadline      time.Duration `env:"PAYMENT_DEADLINE"       envDefault:"72h"` // after that the auction turns UNPAID
+	PaymentCurrency      string        `env:"PAYMENT_CURRENCY"       envDefault:"EUR"  validate:"len=3"`
+	FakepaySimulator     bool          `env:"FAKEPAY_SIMULATOR"      envDefault:"false"` // run the simulator in‑process (local only: anyone can pay on it)
+	FakepayAddr          string        `env:"FAKEPAY_ADDR"           envDefault:":8099"`
+	FakepayURL           string        `env:"FAKEPAY_URL"            envDefault:"http://localhost:8099" validate:"url"`
+	FakepayAPIKey        string        `env:"FAKEPAY_API_KEY"        envDefault:"sk_test_fakepay"`
+	FakepayWebhookSecret string        `env:"FAKEPAY_WEBHOOK_SECRET" validate:"required"` // no default: a known secret lets anyone forge a payment
+
+	SecondChanceTTL  time.Duration `env:"SECOND_CHANCE_TTL"  envDefault:"48h"`
+	SecondChanceAuto bool          `env:"SECOND_CHANCE_AUTO" envDefault:"false"` // offer runner‑ups without the seller
+
+	// Make an offer
+	OfferTTL time.Duration `env:"OFFER_TTL" envDefault:"48h"` // an unanswered offer or counter lapses
+
+	// Live auctions (WS live/*)
+	LiveAuctioneerIDs []string `env:"LIVE_AUCTIONEER_IDS" envSeparator:","` // open, ask, going once/twice, sold, pass
+	LiveClerkIDs      []string `env:"LIVE_CLERK_IDS"      envSeparator:","` // floor / phone bids, undo
+
+	PublicBaseURL string `env:"PUBLIC_BASE_URL" envDefault:"http://localhost:8085"` // links in mails, payment webhook URL
 }
 
 func LoadConfig() (*Config, error) {
@@ -0,0 +1,261 @@
+// Package events defines the versioned, typed auction event schemas. The
+// Redis Lua functions emit exactly these shapes; the WS and SSE paths decode
+// them here and encode the typed values, so every transport speaks one schema.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Version is the current schema version carried by every event.
+const Version = 2
+
+// Event names; on the wire they are prefixed with "auctions/".
+const (
+	NameSnapshot = "snapshot"
+	NameStart    = "start"
+	NameBid      = "bid"
+	NameStop     = "stop"
+	NameExtended = "extended"
+	NamePresence = "presence"
+	NameLive     = "live"
+
+	NameChat          = "chat"
+	NameChatRetracted = "chat_retracted"
+
+	NameTime = "time"
+)
+
+// Event is implemented by every schema type.
+type Event interface {
+	EventName() string
+	Sequence() int64
+}
+
+// Header is shared by all events.
+type Header struct {
+	Version int   `json:"version"`
+	Seq     int64 `json:"seq"` // per‑auction, gap‑free; 0 when unknown
+}
+
+func (h Header) Sequence() int64 { return h.Seq }
+
+// Snapshot is the full auction state, sent on join/resync.
+type Snapshot struct {
+	Header
+	SellerID   string  `json:"seller_id"`
+	Status     string  `json:"status"`    // PENDING | RUNNING | FINISHED (later PAID | UNPAID in PG)
+	StartsAt   int64   `json:"starts_at"` // unix seconds
+	EndsAt     int64   `json:"ends_at"`   // unix seconds
+	HighBid    float64 `json:"high_bid"`
+	HighBidder string  `json:"high_bidder"`
+
+	Live *LiveState `json:"live,omitempty"` // live auctions only
+
+	Chat []ChatMessage `json:"chat,omitempty"` // recent room chat, oldest first
+
+	ServerTime int64 `json:"server_time"` // Redis TIME when taken, unix ms
+}
+
+// LiveState is where the auctioneer of a live auction is.
+type LiveState struct {
+	Phase string  `json:"phase"` // WAITING | OPEN | ONCE | TWICE | SOLD | PASSED
+	Ask   float64 `json:"ask"`   // asking price; 0 = none
+}
+
+// Start is published when bidding opens.
+type Start struct {
+	Header
+	SellerID string `json:"seller_id"`
+	StartsAt int64  `json:"starts_at"`
+	EndsAt   int64  `json:"ends_at"`
+}
+
+// Bid is published for every accepted bid.
+type Bid struct {
+	Header
+	Bidder string  `json:"bidder"`
+	Amount float64 `json:"amount"`
+	At     int64   `json:"at"` // unix seconds
+
+	// The high bid this one replaced; empty on the opening bid.
+	PreviousBidder string  `json:"previous_bidder,omitempty"`
+	PreviousAmount float64 `json:"previous_amount,omitempty"`
+
+	Source string `json:"source,omitempty"` // floor | phone (entered by a clerk), absentee; empty = online
+}
+
+// Stop is published once, with the final state, when the auction closes.
+type Stop struct {
+	Header
+	SellerID   string  `json:"seller_id"`
+	Status     string  `json:"status"`
+	StartsAt   int64   `json:"starts_at"`
+	EndsAt     int64   `json:"ends_at"`
+	HighBid    float64 `json:"high_bid"`
+	HighBidder string  `json:"high_bidder"`
+}
+
+// Extended is published when the end time moves (e.g. anti‑sniping).
+type Extended struct {
+	Header
+	PreviousEndsAt int64 `json:"previous_ends_at"`
+	EndsAt         int64 `json:"ends_at"`
+}
+
+// Live actions.
+const (
+	LiveOpen       = "open"
+	LiveAsk        = "ask"
+	LiveGoingOnce  = "going_once"
+	LiveGoingTwice = "going_twice"
+	LiveSold       = "sold"
+	LivePass       = "pass"
+	LiveUndo       = "undo"
+)
+
+// Live is published for every auctioneer (or clerk) action on a live
+// auction, with the state after it. sold and pass are followed by stop.
+type Live struct {
+	Header
+	Action     string  `json:"action"` // one of the Live* actions
+	Phase      string  `json:"phase"`
+	Ask        float64 `json:"ask"`
+	HighBid    float64 `json:"high_bid"`
+	HighBidder string  `json:"high_bidder"`
+	By         string  `json:"by"` // auctioneer or clerk user ID
+
+	// undo: the bid taken back
+	RetractedBidder string  `json:"retracted_bidder,omitempty"`
+	RetractedAmount float64 `json:"retracted_amount,omitempty"`
+}
+
+// Presence carries cluster‑wide room counts. It is ephemeral: not
+// sequenced (seq 0) and not replayed.
+type Presence struct {
+	Header
+	Viewers int `json:"viewers"` // distinct users currently watching
+	Bidders int `json:"bidders"` // distinct users who placed a bid
+}
+
+// ChatMessage is one room chat line, as kept in the history.
+type ChatMessage struct {
+	ID     int64  `json:"id"` // per‑auction, increasing
+	UserID string `json:"user_id"`
+	Text   string `json:"text"`
+	At     int64  `json:"at"` // unix seconds
+}
+
+// Chat is published for every accepted chat message.
+type Chat struct {
+	Header
+	ChatMessage
+}
+
+// ChatRetracted tells clients to remove a message a moderator deleted.
+type ChatRetracted struct {
+	Header
+	ID int64  `json:"id"`
+	By string `json:"by"` // moderator user ID
+}
+
+// Time carries the server clock (Redis TIME) so clients can compute their
+// offset for countdowns. It is sent in reply to "auctions/time" (echoing
+// client_time, which gives the round trip) and pushed periodically.
+// Ephemeral: seq 0.
+type Time struct {
+	Header
+	ServerTime int64 `json:"server_time"`           // unix ms
+	ClientTime int64 `json:"client_time,omitempty"` // as sent by the client, unix ms
+}
+
+func (Snapshot) EventName() string      { return NameSnapshot }
+func (Start) EventName() string         { return NameStart }
+func (Bid) EventName() string           { return NameBid }
+func (Stop) EventName() string          { return NameStop }
+func (Extended) EventName() string      { return NameExtended }
+func (Presence) EventName() string      { return NamePresence }
+func (Live) EventName() string          { return NameLive }
+func (Chat) EventName() string          { return NameChat }
+func (ChatRetracted) EventName() string { return NameChatRetracted }
+func (Time) EventName() string          { return NameTime }
+
+// Decode parses a payload published by the Lua functions
+// ({"event":"bid","version":2,…}) into its typed event.
+func Decode(payload []byte) (Event, error) {
+	var head struct {
+		Event   string `json:"event"`
+		Version int    `json:"version"`
+	}
+	if err := json.Unmarshal(payload, &head); err != nil {
+		return nil, err
+	}
+	if head.Version != Version {
+		return nil, fmt.Errorf("event %q: unsupported version %d", head.Event, head.Version)
+	}
+
+	var ev Event
+	var err error
+	switch head.Event {
+	case NameSnapshot:
+		ev, err = decodeAs[Snapshot](payload)
+	case NameStart:
+		ev, err = decodeAs[Start](payload)
+	case NameBid:
+		ev, err = decodeAs[Bid](payload)
+	case NameStop:
+		ev, err = decodeAs[Stop](payload)
+	case NameExtended:
+		ev, err = decodeAs[Extended](payload)
+	case NamePresence:
+		ev, err = decodeAs[Presence](payload)
+	case NameLive:
+		ev, err = decodeAs[Live](payload)
+	case NameChat:
+		ev, err = decodeAs[Chat](payload)
+	case NameChatRetracted:
+		ev, err = decodeAs[ChatRetracted](payload)
+	default:
+		return nil, fmt.Errorf("unknown event %q", head.Event)
+	}
+	return ev, err
+}
+
+func decodeAs[T Event](payload []byte) (Event, error) {
+	var ev T
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// SnapshotFromHash builds a snapshot from the live "auc:<id>" Redis hash
+// (sid, sa, ea, st, hb, hbid; live, ph, ask for live auctions).
+func SnapshotFromHash(seq int64, h map[string]string) Snapshot {
+	var live *LiveState
+	if h["live"] == "1" {
+		live = &LiveState{Phase: h["ph"], Ask: atof(h["ask"])}
+	}
+	return Snapshot{
+		Header:     Header{Version: Version, Seq: seq},
+		SellerID:   h["sid"],
+		Status:     h["st"],
+		StartsAt:   atoi(h["sa"]),
+		EndsAt:     atoi(h["ea"]),
+		HighBid:    atof(h["hb"]),
+		HighBidder: h["hbid"],
+		Live:       live,
+	}
+}
+
+func atoi(s string) int64 {
+	i, _ := strconv.ParseInt(s, 10, 64)
+	return i
+}
+
+func atof(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Sale events are published on "sale:<id>:events"; on the wire they are
+// prefixed with "sales/". They are not sequenced (seq 0): a client that
+// (re)subscribes gets a fresh catalog snapshot.
+const (
+	NameLot        = "lot"
+	NameSaleStatus = "status"
+)
+
+// Lot transitions.
+const (
+	LotOpen     = "open"
+	LotExtended = "extended" // a late bid, or an earlier lot's extension shifted it
+	LotClosed   = "closed"
+)
+
+// Lot is published when a lot of a sale opens, moves its end or closes.
+type Lot struct {
+	Header
+	SaleID     string  `json:"sale_id"`
+	AuctionID  string  `json:"auction_id"`
+	LotNo      int     `json:"lot"`
+	Transition string  `json:"transition"` // open | extended | closed
+	EndsAt     int64   `json:"ends_at"`    // unix seconds
+	HighBid    float64 `json:"high_bid"`
+	HighBidder string  `json:"high_bidder"`
+}
+
+// SaleStatus is published when a sale is published or its last lot closed.
+type SaleStatus struct {
+	Header
+	SaleID string `json:"sale_id"`
+	Status string `json:"status"` // PUBLISHED | CLOSED
+}
+
+func (Lot) EventName() string        { return NameLot }
+func (SaleStatus) EventName() string { return NameSaleStatus }
+
+// DecodeSale parses a payload published on a sale channel.
+func DecodeSale(payload []byte) (Event, error) {
+	var head struct {
+		Event   string `json:"event"`
+		Version int    `json:"version"`
+	}
+	if err := json.Unmarshal(payload, &head); err != nil {
+		return nil, err
+	}
+	if head.Version != Version {
+		return nil, fmt.Errorf("sale event %q: unsupported version %d", head.Event, head.Version)
+	}
+	switch head.Event {
+	case NameLot:
+		return decodeAs[Lot](payload)
+	case NameSaleStatus:
+		return decodeAs[SaleStatus](payload)
+	}
+	return nil, fmt.Errorf("unknown sale event %q", head.Event)
+}
@@ -0,0 +1,120 @@
+package events
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schemaTypes lists every published event, keyed by event name; Lot and
+// SaleStatus go out on the sale channel (see frameName).
+var schemaTypes = map[string]Event{
+	NameSnapshot: Snapshot{},
+	NameStart:    Start{},
+	NameBid:      Bid{},
+	NameStop:     Stop{},
+	NameExtended: Extended{},
+	NamePresence: Presence{},
+	NameLive:     Live{},
+
+	NameChat:          Chat{},
+	NameChatRetracted: ChatRetracted{},
+
+	NameTime: Time{},
+
+	NameLot:        Lot{},
+	NameSaleStatus: SaleStatus{},
+}
+
+// frameName is the event's name on the wire: "sales/<name>" for sale
+// channel events, "auctions/<name>" for the rest.
+func frameName(ev Event) string {
+	switch ev.(type) {
+	case Lot, SaleStatus:
+		return "sales/" + ev.EventName()
+	}
+	return "auctions/" + ev.EventName()
+}
+
+// Names returns the event names that have a JSON Schema, sorted.
+func Names() []string {
+	names := make([]string, 0, len(schemaTypes))
+	for n := range schemaTypes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// JSONSchema returns the JSON Schema (draft 2020‑12) of the named event's
+// body, derived from the Go type so the two can't drift apart.
+func JSONSchema(name string) (map[string]any, bool) {
+	ev, ok := schemaTypes[name]
+	if !ok {
+		return nil, false
+	}
+	s := typeSchema(reflect.TypeOf(ev))
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	s["$id"] = "auctionbidgo/events/v" + strconv.Itoa(Version) + "/" + name + ".json"
+	s["title"] = frameName(ev)
+	return s, true
+}
+
+func typeSchema(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		props := map[string]any{}
+		var required []string
+		collectFields(t, props, &required)
+		sort.Strings(required)
+		return map[string]any{
+			"type":       "object",
+			"properties": props,
+			"required":   required,
+		}
+	}
+	return map[string]any{}
+}
+
+// collectFields walks exported fields, inlining embedded structs the way
+// encoding/json does.
+func collectFields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			collectFields(f.Type, props, required)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = typeSchema(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
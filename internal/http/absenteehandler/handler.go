@@ -0,0 +1,124 @@
+package absenteehandler
+
+import (
+	"auctionbidgo/internal/services/absentee"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc absentee.IAbsenteeService
+}
+
+func New(svc absentee.IAbsenteeService) *Handler { return &Handler{svc: svc} }
+
+func (h *Handler) Register(r gin.IRoutes) {
+	r.POST("/auctions/:id/absentee-bids", h.place)
+	r.GET("/users/:id/absentee-bids", h.list)
+	r.DELETE("/absentee-bids/:id", h.cancel)
+}
+
+func status(err error) int {
+	switch {
+	case errors.Is(err, absentee.ErrAuctionNotFound), errors.Is(err, absentee.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, absentee.ErrForbidden), errors.Is(err, absentee.ErrNotRegistered):
+		return http.StatusForbidden
+	case errors.Is(err, absentee.ErrBadAmount):
+		return http.StatusBadRequest
+	case errors.Is(err, absentee.ErrNotPending), errors.Is(err, absentee.ErrBelowStart):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+//	@Summary		Leave an absentee bid
+//	@Description	Stores the bidder's maximum on a PENDING auction. When the auction
+//	@Description	starts, absentee bids are resolved in submission order with the
+//	@Description	usual increment rules, and it opens at the resulting price.
+//	@Description	Placing again replaces the maximum and counts as a new submission.
+//	@Tags			Absentee bids
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string		true	"Auction ID"	default(auc123)
+//	@Param			body	body		PlaceBody	true	"Maximum"
+//	@Success		200		{object}	absentee.Bid
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse	"Not registered for this auction"
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse	"Auction started, or maximum below start price"
+//	@Router			/auctions/{id}/absentee-bids [post]
+func (h *Handler) place(c *gin.Context) {
+	var body PlaceBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	b, err := h.svc.Place(c.Request.Context(), c.Param("id"), body.BidderID, body.MaxAmount)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
+
+//	@Summary		List a bidder's absentee bids
+//	@Description	Newest first, including cancelled ones and those placed when their auction started.
+//	@Description	Only the bidder may list them: bidder_id must match the path.
+//	@Tags			Absentee bids
+//	@Produce		json
+//	@Param			id			path		string	true	"Bidder ID"	default(user123)
+//	@Param			bidder_id	query		string	true	"Bidder ID (must match the path)"
+//	@Param			auction_id	query		string	false	"Only this auction"
+//	@Success		200			{array}		absentee.Bid
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/users/{id}/absentee-bids [get]
+func (h *Handler) list(c *gin.Context) {
+	var q ListQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if q.BidderID != c.Param("id") {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: absentee.ErrForbidden.Error()})
+		return
+	}
+	out, err := h.svc.ForBidder(c.Request.Context(), c.Param("id"), q.AuctionID)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+//	@Summary		Cancel an absentee bid
+//	@Description	Only until the auction starts; cancelling twice is a no‑op.
+//	@Tags			Absentee bids
+//	@Param			id			path	int		true	"Absentee bid ID"
+//	@Param			bidder_id	query	string	true	"Bidder ID"
+//	@Success		204
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse	"Auction already started"
+//	@Router			/absentee-bids/{id} [delete]
+func (h *Handler) cancel(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid id"})
+		return
+	}
+	var q BidderQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.svc.Cancel(c.Request.Context(), id, q.BidderID); err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,19 @@
+package absenteehandler
+
+type PlaceBody struct {
+	BidderID  string  `json:"bidder_id"  binding:"required" example:"user123"`
+	MaxAmount float64 `json:"max_amount" binding:"required" example:"250"`
+} // @name PlaceAbsenteeBidRequest
+
+type BidderQuery struct {
+	BidderID string `form:"bidder_id" binding:"required"`
+}
+
+type ListQuery struct {
+	BidderID  string `form:"bidder_id" binding:"required"`
+	AuctionID string `form:"auction_id"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name AbsenteeErrorResponse
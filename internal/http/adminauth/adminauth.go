@@ -0,0 +1,36 @@
+package adminauth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Require guards admin routes with a static bearer token
+// ("Authorization: Bearer <token>"). An empty token disables the routes:
+// every request gets 503 rather than an open admin API.
+func Require(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "admin API disabled (ADMIN_API_TOKEN unset)"})
+			return
+		}
+		if !IsAdmin(c, token) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "admin token required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// IsAdmin reports whether the request carries the admin token, for public
+// routes that show more to admins. With no token configured nobody is admin.
+func IsAdmin(c *gin.Context, token string) bool {
+	if token == "" {
+		return false
+	}
+	got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
@@ -11,17 +11,21 @@ import (
 )
 
 type Handler struct {
-	svc auction.IAuctionService
+	svc  auction.IAuctionService
+	view gin.HandlerFunc // visibility check (viewauth)
 }
 
-func New(svc auction.IAuctionService) *Handler { return &Handler{svc: svc} }
+func New(svc auction.IAuctionService, view gin.HandlerFunc) *Handler {
+	return &Handler{svc: svc, view: view}
+}
 
 func (h *Handler) Register(r gin.IRoutes) {
 	r.POST("/auctions", h.create)
 	r.GET("/auctions", h.list)
-	r.GET("/auctions/:id", h.info)
+	r.GET("/auctions/:id", h.view, h.info)
 	r.POST("/auctions/:id/start", h.start)
 	r.POST("/auctions/:id/stop", h.stop)
+	r.POST("/auctions/:id/bid", h.bid)
 	r.DELETE("/auctions/:id", h.delete)
 }
 
@@ -51,6 +55,8 @@ func (h *Handler) create(c *gin.Context) {
 		strings.TrimSpace(body.ID),
 		body.SellerID,
 		body.Item,
+		body.StartPrice,
+		body.Live,
 		body.EndsAt.UTC(),
 	)
 	if err != nil {
@@ -65,11 +71,13 @@ func (h *Handler) create(c *gin.Context) {
 }
 
 //	@Summary		Get auction details
-//	@Description	Returns full information about a single auction.
+//	@Description	Returns full information about a single auction. A private auction
+//	@Description	is shown only to its seller and approved bidders (404 otherwise).
 //	@Tags			Auctions
-//	@Param			id	path		string	true	"Auction ID"	default(auc123)
-//	@Success		200	{object}	auction.AuctionDTO
-//	@Failure		404	{object}	ErrorResponse
+//	@Param			id			path		string	true	"Auction ID"	default(auc123)
+//	@Param			viewer_id	query		string	false	"Who is asking (required for private auctions)"
+//	@Success		200			{object}	auction.AuctionDTO
+//	@Failure		404			{object}	ErrorResponse
 //	@Router			/auctions/{id} [get]
 func (h *Handler) info(c *gin.Context) {
 	dto, err := h.svc.GetAuction(c, c.Param("id"))
@@ -82,8 +90,9 @@ func (h *Handler) info(c *gin.Context) {
 
 //	@Summary		List auctions
 //	@Description	Retrieves a paginated list of auctions, optionally filtered by status.
+//	@Description	Only public auctions are listed; unlisted and private ones are reachable by ID.
 //	@Tags			Auctions
-//	@Param			status	query		string	false	"Status filter"			Enums(RUNNING,FINISHED)
+//	@Param			status	query		string	false	"Status filter"			Enums(RUNNING,FINISHED,PAID,UNPAID)
 //	@Param			limit	query		int		false	"Max results (0‑100)"	minimum(0)	maximum(100)	default(10)
 //	@Param			offset	query		int		false	"Offset for pagination"	minimum(0)	default(0)
 //	@Success		200		{array}		auction.AuctionDTO
@@ -149,6 +158,48 @@ func (h *Handler) stop(ginCtx *gin.Context) {
 	ginCtx.Status(http.StatusAccepted)
 }
 
+//	@Summary		Place a bid
+//	@Description	REST counterpart of the WS `auctions/bid` event (used by SSE clients).
+//	@Description	On an auction that has not started yet the amount is kept as the bidder's
+//	@Description	absentee maximum (see `POST /auctions/{id}/absentee-bids`).
+//	@Tags			Auctions
+//	@Accept			json
+//	@Param			id		path	string			true	"Auction ID"	default(auc123)
+//	@Param			body	body	PlaceBidBody	true	"Bid payload"
+//	@Success		202
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse	"Registration required and the bidder is not approved"
+//	@Failure		409	{object}	ErrorResponse	"Auction closed, bid too low or over the bidder's credit limit"
+//	@Router			/auctions/{id}/bid [post]
+func (h *Handler) bid(ginCtx *gin.Context) {
+	var body PlaceBidBody
+	if err := ginCtx.ShouldBindJSON(&body); err != nil {
+		ginCtx.JSON(http.StatusBadRequest, &ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	err := h.svc.PlaceBid(ginCtx.Request.Context(), ginCtx.Param("id"), body.BidderID, body.Amount)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, auction.ErrAuctionClosed),
+			errors.Is(err, auction.ErrBidEqual),
+			errors.Is(err, auction.ErrBidBelowCurrent),
+			errors.Is(err, auction.ErrBidBelowIncrement),
+			errors.Is(err, auction.ErrBidBelowStart),
+			errors.Is(err, auction.ErrBidBelowAsk),
+			errors.Is(err, auction.ErrLotNotOpen),
+			errors.Is(err, auction.ErrInsufficientCredit):
+			status = http.StatusConflict
+		case errors.Is(err, auction.ErrNotRegistered):
+			status = http.StatusForbidden
+		}
+		ginCtx.JSON(status, &ErrorResponse{Error: err.Error()})
+		return
+	}
+	ginCtx.Status(http.StatusAccepted)
+}
+
 // ---------------------------------------------------------------------
 //	@Summary		Delete an auction
 //	@Description	Permanently removes an auction and its bids. Allowed
@@ -5,9 +5,11 @@ import "time"
 type CreateAuctionBody struct {
 	ID string `json:"id,omitempty" example:"auc123"`
 
-	SellerID string    `json:"seller_id"    binding:"required" example:"seller123"`
-	Item     string    `json:"item"         binding:"required" example:"MacBook Air M3"`
-	EndsAt   time.Time `json:"ends_at"      binding:"required" example:"2025-07-27T16:10:00Z"`
+	SellerID   string    `json:"seller_id"             binding:"required" example:"seller123"`
+	Item       string    `json:"item"                  binding:"required" example:"MacBook Air M3"`
+	StartPrice float64   `json:"start_price,omitempty" binding:"gte=0"    example:"100"` // lowest opening bid
+	Live       bool      `json:"live,omitempty"                           example:"false"` // closed by an auctioneer over WS (live/*)
+	EndsAt     time.Time `json:"ends_at"               binding:"required" example:"2025-07-27T16:10:00Z"`
 } // @name CreateAuctionRequest
 
 type StartAuctionBody struct {
@@ -25,7 +27,7 @@ type ErrorResponse struct {
 } // @name ErrorResponse
 
 type ListAuctionsQuery struct {
-	Status string `form:"status"  binding:"omitempty,oneof=RUNNING FINISHED"`
+	Status string `form:"status"  binding:"omitempty,oneof=RUNNING FINISHED PAID UNPAID"`
 	Limit  int    `form:"limit,default=10"  binding:"gte=0,lte=100"`
 	Offset int    `form:"offset,default=0"  binding:"gte=0"`
 } // @name ListAuctionsQuery
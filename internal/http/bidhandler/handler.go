@@ -0,0 +1,104 @@
+package bidhandler
+
+import (
+	"auctionbidgo/internal/http/adminauth"
+	"auctionbidgo/internal/services/bidhistory"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc        bidhistory.IBidHistoryService
+	mask       bool
+	adminToken string
+	view       gin.HandlerFunc // visibility check (viewauth)
+}
+
+// New builds the handler; with mask set, bidder IDs are masked unless the
+// request carries the admin token. There is no per‑viewer exception: a
+// viewer_id would be self‑asserted, and would unmask whoever it names.
+func New(svc bidhistory.IBidHistoryService, mask bool, adminToken string, view gin.HandlerFunc) *Handler {
+	return &Handler{svc: svc, mask: mask, adminToken: adminToken, view: view}
+}
+
+func (h *Handler) Register(r gin.IRoutes) {
+	r.GET("/auctions/:id/bids", h.view, h.auctionBids)
+	r.GET("/bidders/:id/bids", h.bidderBids)
+}
+
+//	@Summary		Bid history of an auction
+//	@Description	Every bid with its status (`winning`, `outbid`, or `winner` once the
+//	@Description	auction finished). Bids of a running auction that are not persisted
+//	@Description	yet are merged in with `pending=true`. Page with `cursor` = the
+//	@Description	previous page's `next_cursor`. Bidder IDs are masked (`u***3`)
+//	@Description	unless the admin token is sent. A private auction's bids are shown
+//	@Description	only to its seller and approved bidders (404 otherwise).
+//	@Tags			Auctions
+//	@Produce		json
+//	@Param			id			path		string	true	"Auction ID"	default(auc123)
+//	@Param			viewer_id	query		string	false	"Who is asking (required for private auctions)"
+//	@Param			from		query		string	false	"Placed at or after (RFC 3339)"
+//	@Param			to			query		string	false	"Placed before (RFC 3339)"
+//	@Param			sort		query		string	false	"Order"	Enums(placed_at_desc, placed_at_asc, amount_desc, amount_asc)	default(placed_at_desc)
+//	@Param			cursor		query		string	false	"Cursor from the previous page"
+//	@Param			limit		query		int		false	"Page size (1‑500)"	minimum(1)	maximum(500)	default(50)
+//	@Success		200			{object}	bidhistory.Page
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/auctions/{id}/bids [get]
+func (h *Handler) auctionBids(c *gin.Context) {
+	h.list(c, h.svc.ForAuction)
+}
+
+//	@Summary		Bid history of a bidder
+//	@Description	The bidder's bids across auctions with their status, filtered,
+//	@Description	sorted and paged like `/auctions/{id}/bids`.
+//	@Tags			Auctions
+//	@Produce		json
+//	@Param			id			path		string	true	"Bidder ID"	default(user123)
+//	@Param			from		query		string	false	"Placed at or after (RFC 3339)"
+//	@Param			to			query		string	false	"Placed before (RFC 3339)"
+//	@Param			sort		query		string	false	"Order"	Enums(placed_at_desc, placed_at_asc, amount_desc, amount_asc)	default(placed_at_desc)
+//	@Param			cursor		query		string	false	"Cursor from the previous page"
+//	@Param			limit		query		int		false	"Page size (1‑500)"	minimum(1)	maximum(500)	default(50)
+//	@Success		200			{object}	bidhistory.Page
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/bidders/{id}/bids [get]
+func (h *Handler) bidderBids(c *gin.Context) {
+	h.list(c, h.svc.ForBidder)
+}
+
+func (h *Handler) list(c *gin.Context, fetch func(context.Context, string, bidhistory.Query) (*bidhistory.Page, error)) {
+	var q ListBidsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	page, err := fetch(c.Request.Context(), c.Param("id"), bidhistory.Query{
+		From:   q.From,
+		To:     q.To,
+		Sort:   q.Sort,
+		Cursor: q.Cursor,
+		Limit:  q.Limit,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, bidhistory.ErrBadCursor) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if h.mask && !adminauth.IsAdmin(c, h.adminToken) {
+		for i := range page.Bids {
+			page.Bids[i].BidderID = bidhistory.Mask(page.Bids[i].BidderID)
+		}
+	}
+	c.JSON(http.StatusOK, page)
+}
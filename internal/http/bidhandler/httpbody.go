@@ -0,0 +1,15 @@
+package bidhandler
+
+import "time"
+
+type ListBidsQuery struct {
+	From   *time.Time `form:"from"      time_format:"2006-01-02T15:04:05Z07:00"`
+	To     *time.Time `form:"to"        time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort   string     `form:"sort,default=placed_at_desc" binding:"oneof=placed_at_desc placed_at_asc amount_desc amount_asc"`
+	Cursor string     `form:"cursor"`
+	Limit  int        `form:"limit,default=50" binding:"gte=1,lte=500"`
+} // @name ListBidsQuery
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name BidErrorResponse
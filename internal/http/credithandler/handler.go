@@ -0,0 +1,71 @@
+package credithandler
+
+import (
+	"auctionbidgo/internal/services/credit"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc credit.ICreditService
+}
+
+func New(svc credit.ICreditService) *Handler { return &Handler{svc: svc} }
+
+func (h *Handler) Register(r gin.IRoutes) {
+	r.GET("/users/:id/credit", h.get)
+}
+
+// RegisterAdmin mounts the admin actions; r is expected to be behind admin auth.
+func (h *Handler) RegisterAdmin(r gin.IRoutes) {
+	r.PUT("/admin/users/:id/credit", h.set)
+}
+
+//	@Summary		Get a user's credit
+//	@Description	Limit (credit limit + deposit), exposure (auctions the user
+//	@Description	currently leads), open charges (won, unpaid) and what is left.
+//	@Description	A bid that would take exposure + charges over the limit is
+//	@Description	rejected with "insufficient credit".
+//	@Tags			Auctions
+//	@Produce		json
+//	@Param			id	path		string	true	"User ID"	default(user123)
+//	@Success		200	{object}	credit.Account
+//	@Router			/users/{id}/credit [get]
+func (h *Handler) get(c *gin.Context) {
+	a, err := h.svc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
+
+//	@Summary		Set a user's credit limit and deposit
+//	@Tags			Auctions
+//	@Accept			json
+//	@Produce		json
+//	@Security		AdminToken
+//	@Param			id		path		string			true	"User ID"	default(user123)
+//	@Param			body	body		SetCreditBody	true	"Limit and deposit"
+//	@Success		200		{object}	credit.Account
+//	@Failure		400		{object}	ErrorResponse
+//	@Router			/admin/users/{id}/credit [put]
+func (h *Handler) set(c *gin.Context) {
+	var body SetCreditBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	a, err := h.svc.Set(c.Request.Context(), c.Param("id"), body.CreditLimit, body.Deposit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, credit.ErrNegative) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
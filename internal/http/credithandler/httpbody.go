@@ -0,0 +1,10 @@
+package credithandler
+
+type SetCreditBody struct {
+	CreditLimit float64 `json:"credit_limit" binding:"gte=0" example:"5000"`
+	Deposit     float64 `json:"deposit"      binding:"gte=0" example:"1000"` // pre‑authorised
+} // @name SetCreditRequest
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name CreditErrorResponse
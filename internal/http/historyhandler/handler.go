@@ -0,0 +1,83 @@
+package historyhandler
+
+import (
+	"auctionbidgo/internal/services/history"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc  history.IHistoryService
+	view gin.HandlerFunc // visibility check (viewauth)
+}
+
+func New(svc history.IHistoryService, view gin.HandlerFunc) *Handler {
+	return &Handler{svc: svc, view: view}
+}
+
+// Register adds the routes; a private auction's history is shown only to
+// its seller and approved bidders.
+func (h *Handler) Register(r gin.IRoutes) {
+	r.GET("/auctions/:id/timeline", h.view, h.timeline)
+	r.GET("/auctions/:id/state", h.view, h.state)
+}
+
+//	@Summary		Auction timeline
+//	@Description	Append‑only history of the auction – creation, start, every bid,
+//	@Description	extensions and the close – with actor and server timestamp, oldest
+//	@Description	first. Page with `after_seq` = the previous page's `next_after_seq`.
+//	@Tags			Auctions
+//	@Produce		json
+//	@Param			id			path		string	true	"Auction ID"	default(auc123)
+//	@Param			viewer_id	query		string	false	"Who is asking (required for private auctions)"
+//	@Param			after_seq	query		int		false	"Return events with seq > after_seq"	default(-1)
+//	@Param			limit		query		int		false	"Page size (1‑500)"	minimum(1)	maximum(500)	default(50)
+//	@Success		200			{object}	history.Page
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/auctions/{id}/timeline [get]
+func (h *Handler) timeline(c *gin.Context) {
+	var q TimelineQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	page, err := h.svc.Timeline(c.Request.Context(), c.Param("id"), q.AfterSeq, q.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+//	@Summary		Auction state at a point in time
+//	@Description	Rebuilds the auction as it was at `at` by folding its history.
+//	@Tags			Auctions
+//	@Produce		json
+//	@Param			id			path		string	true	"Auction ID"	default(auc123)
+//	@Param			at			query		string	true	"RFC 3339 instant"	default(2025-07-27T14:03:00Z)
+//	@Param			viewer_id	query		string	false	"Who is asking (required for private auctions)"
+//	@Success		200			{object}	auction.AuctionDTO
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Router			/auctions/{id}/state [get]
+func (h *Handler) state(c *gin.Context) {
+	var q StateQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	dto, err := h.svc.StateAt(c.Request.Context(), c.Param("id"), q.At)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, history.ErrNoHistory) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto)
+}
@@ -0,0 +1,16 @@
+package historyhandler
+
+import "time"
+
+type TimelineQuery struct {
+	AfterSeq int64 `form:"after_seq,default=-1" binding:"gte=-1"`
+	Limit    int   `form:"limit,default=50"     binding:"gte=1,lte=500"`
+} // @name TimelineQuery
+
+type StateQuery struct {
+	At time.Time `form:"at" binding:"required" time_format:"2006-01-02T15:04:05Z07:00"`
+} // @name AuctionStateQuery
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name HistoryErrorResponse
@@ -1,11 +1,43 @@
 package http_server
 
 import (
+	"auctionbidgo/internal/http/absenteehandler"
+	"auctionbidgo/internal/http/adminauth"
 	"auctionbidgo/internal/http/auctionhandler"
+	"auctionbidgo/internal/http/bidhandler"
+	"auctionbidgo/internal/http/credithandler"
+	"auctionbidgo/internal/http/historyhandler"
+	"auctionbidgo/internal/http/invoicehandler"
+	"auctionbidgo/internal/http/offerhandler"
+	"auctionbidgo/internal/http/paymenthandler"
+	"auctionbidgo/internal/http/registrationhandler"
+	"auctionbidgo/internal/http/relisthandler"
+	"auctionbidgo/internal/http/salehandler"
+	"auctionbidgo/internal/http/schemahandler"
+	"auctionbidgo/internal/http/secondchancehandler"
+	"auctionbidgo/internal/http/staffauth"
+	"auctionbidgo/internal/http/staffhandler"
+	"auctionbidgo/internal/http/viewauth"
+	"auctionbidgo/internal/http/watchlisthandler"
+	"auctionbidgo/internal/http/webhookhandler"
+	"auctionbidgo/internal/services/absentee"
 	"auctionbidgo/internal/services/auction"
+	"auctionbidgo/internal/services/bidhistory"
+	"auctionbidgo/internal/services/credit"
+	"auctionbidgo/internal/services/history"
+	"auctionbidgo/internal/services/offer"
+	"auctionbidgo/internal/services/payment"
+	"auctionbidgo/internal/services/registration"
+	"auctionbidgo/internal/services/relist"
+	"auctionbidgo/internal/services/sale"
+	"auctionbidgo/internal/services/secondchance"
+	"auctionbidgo/internal/services/settlement"
+	"auctionbidgo/internal/services/watchlist"
+	"auctionbidgo/internal/services/webhook"
 	"auctionbidgo/internal/ws"
 	"context"
 	"errors"
+	"expvar"
 	"fmt"
 	"log"
 	"net"
@@ -19,21 +51,42 @@ import (
 	"github.com/abrar71/swaggerfilesv2" // swagger embed files
 )
 
+// Deps are the services and settings the HTTP server routes to.
+type Deps struct {
+	WsServer     *ws.WsServer
+	Auctions     auction.IAuctionService
+	Webhooks     webhook.IWebhookService
+	History      history.IHistoryService
+	Bids         bidhistory.IBidHistoryService
+	Watchlist    watchlist.IWatchlistService
+	Settlement   settlement.ISettlementService
+	Payments     payment.IPaymentService
+	Credit       credit.ICreditService
+	SecondChance secondchance.ISecondChanceService
+	Offers       offer.IOfferService
+	Relist       relist.IRelistService
+	Sales        sale.ISaleService
+	Absentee     absentee.IAbsenteeService
+	Registration registration.IRegistrationService
+
+	MaskBidders bool              // bid history shows masked bidder IDs
+	AdminToken  string            // guards the admin routes; empty disables them
+	Staff       *staffauth.Signer // issues staff tokens (POST /admin/staff-tokens)
+}
+
 type httpServer struct {
-	listenPort     uint16
-	srv            http.Server
-	ln             net.Listener
-	auctionService auction.IAuctionService
-	wsSrv          *ws.WsServer
-	ctx            context.Context
+	listenPort uint16
+	srv        http.Server
+	ln         net.Listener
+	deps       Deps
+	ctx        context.Context
 }
 
-func NewHttpServer(ctx context.Context, listenPort uint16, wsSrv *ws.WsServer, auctionService auction.IAuctionService) *httpServer {
+func NewHttpServer(ctx context.Context, listenPort uint16, deps Deps) *httpServer {
 	return &httpServer{
-		listenPort:     listenPort,
-		wsSrv:          wsSrv,
-		auctionService: auctionService,
-		ctx:            ctx,
+		listenPort: listenPort,
+		deps:       deps,
+		ctx:        ctx,
 	}
 }
 
@@ -45,6 +98,7 @@ func (h *httpServer) Start() error {
 		return err
 	}
 
+	d := h.deps
 	routerEngine := gin.New()
 
 	// Swagger UI and API specs
@@ -58,12 +112,41 @@ func (h *httpServer) Start() error {
 	// routerEngine.Use(ginzap.Ginzap(zap.L(), time.RFC3339, true))
 	routerEngine.Use(ginzap.RecoveryWithZap(zap.L(), true))
 
-	// websocket endpoint
-	routerEngine.GET("/ws", h.wsSrv.Handle)
+	// websocket endpoint (+ SSE alternative for WS‑hostile proxies)
+	routerEngine.GET("/ws", d.WsServer.Handle)
+	routerEngine.GET("/auctions/:id/events", d.WsServer.HandleSSE)
 
 	// REST API
-	ah := auctionhandler.New(h.auctionService)
+	view := viewauth.Require(d.Registration, d.AdminToken)
+	ah := auctionhandler.New(d.Auctions, view)
 	ah.Register(routerEngine)
+	schemahandler.New().Register(routerEngine)
+	historyhandler.New(d.History, view).Register(routerEngine)
+	bidhandler.New(d.Bids, d.MaskBidders, d.AdminToken, view).Register(routerEngine)
+	watchlisthandler.New(d.Watchlist).Register(routerEngine)
+	ih := invoicehandler.New(d.Settlement, d.AdminToken)
+	ih.Register(routerEngine)
+	ph := paymenthandler.New(d.Payments, d.Settlement, d.AdminToken)
+	ph.Register(routerEngine)
+	ch := credithandler.New(d.Credit)
+	ch.Register(routerEngine)
+	secondchancehandler.New(d.SecondChance).Register(routerEngine)
+	offerhandler.New(d.Offers).Register(routerEngine)
+	relisthandler.New(d.Relist).Register(routerEngine)
+	salehandler.New(d.Sales).Register(routerEngine)
+	absenteehandler.New(d.Absentee).Register(routerEngine)
+	registrationhandler.New(d.Registration).Register(routerEngine)
+
+	// Admin API
+	admin := routerEngine.Group("", adminauth.Require(d.AdminToken))
+	webhookhandler.New(d.Webhooks).Register(admin)
+	// expvar counters (WS dropped messages, slow consumers, …; also cmdline
+	// and memstats)
+	admin.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+	ih.RegisterAdmin(admin)
+	ph.RegisterAdmin(admin)
+	ch.RegisterAdmin(admin)
+	staffhandler.New(d.Staff).RegisterAdmin(admin)
 
 	h.srv = http.Server{
 		Handler: routerEngine,
@@ -0,0 +1,197 @@
+package invoicehandler
+
+import (
+	"auctionbidgo/internal/http/adminauth"
+	"auctionbidgo/internal/services/settlement"
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc        settlement.ISettlementService
+	adminToken string
+}
+
+// New builds the handler; invoices are shown to their party (viewer_id) or
+// to requests carrying the admin token.
+func New(svc settlement.ISettlementService, adminToken string) *Handler {
+	return &Handler{svc: svc, adminToken: adminToken}
+}
+
+func (h *Handler) Register(r gin.IRoutes) {
+	r.GET("/invoices/:id", h.get)
+	r.GET("/invoices/:id/document", h.document)
+	r.GET("/users/:id/invoices", h.listForUser)
+}
+
+// RegisterAdmin mounts the admin actions; r is expected to be behind admin auth.
+func (h *Handler) RegisterAdmin(r gin.IRoutes) {
+	r.POST("/admin/invoices/:id/void", h.void)
+}
+
+func status(err error) int {
+	switch {
+	case errors.Is(err, settlement.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, settlement.ErrInvalidStatus):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// allowed reports whether the caller may see userID's invoices: the admin
+// token, or viewer_id naming that user. When not, the response is written.
+func (h *Handler) allowed(c *gin.Context, userID string) bool {
+	if adminauth.IsAdmin(c, h.adminToken) {
+		return true
+	}
+	var q ViewerQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return false
+	}
+	if q.ViewerID != userID {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "not your invoice"})
+		return false
+	}
+	return true
+}
+
+func idParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid id"})
+		return 0, false
+	}
+	return id, true
+}
+
+//	@Summary		Get an invoice
+//	@Description	Buyer invoice (hammer, buyer's premium, tax) or seller statement
+//	@Description	(hammer less commission and its tax), issued when the auction finishes.
+//	@Description	Only its party (`viewer_id`) or an admin may read it.
+//	@Tags			Auctions
+//	@Produce		json
+//	@Param			id			path		int		true	"Invoice ID"
+//	@Param			viewer_id	query		string	true	"The invoice's buyer or seller"
+//	@Success		200			{object}	settlement.Invoice
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Router			/invoices/{id} [get]
+func (h *Handler) get(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	inv, err := h.svc.Get(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !h.allowed(c, inv.UserID) {
+		return
+	}
+	c.JSON(http.StatusOK, inv)
+}
+
+//	@Summary		Download an invoice
+//	@Description	Renders the invoice as a PDF (default) or HTML document.
+//	@Tags			Auctions
+//	@Produce		application/pdf,text/html
+//	@Param			id			path	int		true	"Invoice ID"
+//	@Param			format		query	string	false	"Document format"	Enums(pdf, html)	default(pdf)
+//	@Param			viewer_id	query	string	true	"The invoice's buyer or seller"
+//	@Success		200			{file}	file
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Router			/invoices/{id}/document [get]
+func (h *Handler) document(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	var q DocumentQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	inv, err := h.svc.Get(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !h.allowed(c, inv.UserID) {
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "application/pdf"
+	if q.Format == "html" {
+		contentType = "text/html; charset=utf-8"
+		err = settlement.RenderHTML(&buf, inv)
+	} else {
+		err = settlement.RenderPDF(&buf, inv)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+inv.Number+"."+q.Format+`"`)
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+//	@Summary		List a user's invoices
+//	@Description	Invoices where the user is the buyer or the seller, newest first.
+//	@Tags			Auctions
+//	@Produce		json
+//	@Param			id			path		string	true	"User ID"	default(user123)
+//	@Param			status		query		string	false	"Filter by status"	Enums(issued, paid, void)
+//	@Param			viewer_id	query		string	true	"Must be the user (or send the admin token)"
+//	@Success		200			{array}		settlement.Invoice
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Router			/users/{id}/invoices [get]
+func (h *Handler) listForUser(c *gin.Context) {
+	var q ListInvoicesQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !h.allowed(c, c.Param("id")) {
+		return
+	}
+	out, err := h.svc.ListForUser(c.Request.Context(), c.Param("id"), q.Status)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+//	@Summary		Void an invoice
+//	@Description	Only issued invoices can be voided; a new settlement may then be issued.
+//	@Tags			Auctions
+//	@Produce		json
+//	@Security		AdminToken
+//	@Param			id	path		int	true	"Invoice ID"
+//	@Success		200	{object}	settlement.Invoice
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Router			/admin/invoices/{id}/void [post]
+func (h *Handler) void(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	inv, err := h.svc.Void(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, inv)
+}
@@ -0,0 +1,17 @@
+package invoicehandler
+
+type DocumentQuery struct {
+	Format string `form:"format,default=pdf" binding:"oneof=pdf html"`
+} // @name InvoiceDocumentQuery
+
+type ListInvoicesQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=issued paid void"`
+} // @name ListInvoicesQuery
+
+type ViewerQuery struct {
+	ViewerID string `form:"viewer_id" binding:"required"` // the invoice's buyer or seller
+} // @name InvoiceViewerQuery
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name InvoiceErrorResponse
@@ -0,0 +1,268 @@
+package offerhandler
+
+import (
+	"auctionbidgo/internal/services/offer"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc offer.IOfferService
+}
+
+func New(svc offer.IOfferService) *Handler { return &Handler{svc: svc} }
+
+func (h *Handler) Register(r gin.IRoutes) {
+	r.PUT("/auctions/:id/offer-settings", h.configure)
+	r.POST("/auctions/:id/offers", h.submit)
+	r.GET("/auctions/:id/offers", h.listForAuction)
+	r.GET("/users/:id/offers", h.listForBuyer)
+	r.GET("/offers/:id", h.get)
+	r.POST("/offers/:id/counter", h.counter)
+	r.POST("/offers/:id/accept", h.accept)
+	r.POST("/offers/:id/decline", h.decline)
+}
+
+func status(err error) int {
+	switch {
+	case errors.Is(err, offer.ErrAuctionNotFound), errors.Is(err, offer.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, offer.ErrForbidden), errors.Is(err, offer.ErrOwnAuction):
+		return http.StatusForbidden
+	case errors.Is(err, offer.ErrBadAmount), errors.Is(err, offer.ErrBadThresholds):
+		return http.StatusBadRequest
+	case errors.Is(err, offer.ErrNotOpen), errors.Is(err, offer.ErrOfferExists),
+		errors.Is(err, offer.ErrNotYourTurn), errors.Is(err, offer.ErrClosed):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+func idParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid id"})
+		return 0, false
+	}
+	return id, true
+}
+
+//	@Summary		Configure offers on an auction
+//	@Description	Seller only. best_offer lets buyers make offers before the auction
+//	@Description	starts (auctions that finish unsold always take offers). Buyer
+//	@Description	amounts >= auto_accept are accepted, amounts < auto_decline declined.
+//	@Tags			Offers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"Auction ID"	default(auc123)
+//	@Param			body	body		SettingsBody	true	"Offer settings"
+//	@Success		200		{object}	offer.Settings
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Router			/auctions/{id}/offer-settings [put]
+func (h *Handler) configure(c *gin.Context) {
+	var body SettingsBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	s, err := h.svc.Configure(c.Request.Context(), body.SellerID, offer.Settings{
+		AuctionID:   c.Param("id"),
+		BestOffer:   body.BestOffer,
+		AutoAccept:  body.AutoAccept,
+		AutoDecline: body.AutoDecline,
+	})
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+//	@Summary		Make an offer
+//	@Description	Private offer to the seller; the seller (or their thresholds)
+//	@Description	accepts, declines or counters. Both sides get `offer` notifications.
+//	@Tags			Offers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string		true	"Auction ID"	default(auc123)
+//	@Param			body	body		SubmitBody	true	"Buyer and amount"
+//	@Success		201		{object}	offer.Offer
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse	"Auction takes no offers, or an offer is already open"
+//	@Router			/auctions/{id}/offers [post]
+func (h *Handler) submit(c *gin.Context) {
+	var body SubmitBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	o, err := h.svc.Submit(c.Request.Context(), c.Param("id"), body.BuyerID, body.Amount)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, o)
+}
+
+//	@Summary		List offers on an auction
+//	@Description	Seller only, newest first.
+//	@Tags			Offers
+//	@Produce		json
+//	@Param			id			path		string	true	"Auction ID"	default(auc123)
+//	@Param			seller_id	query		string	true	"Seller ID"		default(seller123)
+//	@Success		200			{array}		offer.Offer
+//	@Failure		403			{object}	ErrorResponse
+//	@Router			/auctions/{id}/offers [get]
+func (h *Handler) listForAuction(c *gin.Context) {
+	var q SellerQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	out, err := h.svc.ListForAuction(c.Request.Context(), c.Param("id"), q.SellerID)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+//	@Summary		List a buyer's offers
+//	@Description	Only the buyer may list them: `viewer_id` must be the user.
+//	@Tags			Offers
+//	@Produce		json
+//	@Param			id			path		string	true	"User ID"	default(user123)
+//	@Param			viewer_id	query		string	true	"Must be the user"
+//	@Success		200			{array}		offer.Offer
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Router			/users/{id}/offers [get]
+func (h *Handler) listForBuyer(c *gin.Context) {
+	var q ViewerQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if q.ViewerID != c.Param("id") {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: offer.ErrForbidden.Error()})
+		return
+	}
+	out, err := h.svc.ListForBuyer(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+//	@Summary		Get an offer with its history
+//	@Tags			Offers
+//	@Produce		json
+//	@Param			id			path		int		true	"Offer ID"
+//	@Param			viewer_id	query		string	true	"Buyer or seller ID"
+//	@Success		200			{object}	offer.Offer
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Router			/offers/{id} [get]
+func (h *Handler) get(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	var q ViewerQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	o, err := h.svc.Get(c.Request.Context(), id, q.ViewerID)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, o)
+}
+
+//	@Summary		Counter an offer
+//	@Description	The seller counters a pending offer, the buyer a countered one.
+//	@Tags			Offers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int			true	"Offer ID"
+//	@Param			body	body		CounterBody	true	"Who counters, and the new amount"
+//	@Success		200		{object}	offer.Offer
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Router			/offers/{id}/counter [post]
+func (h *Handler) counter(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	var body CounterBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	o, err := h.svc.Counter(c.Request.Context(), id, body.ActorID, body.Amount)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, o)
+}
+
+//	@Summary		Accept an offer
+//	@Description	Sells the item at the amount on the table: the auction finishes
+//	@Description	with the buyer as winner and is settled like a won auction.
+//	@Tags			Offers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int			true	"Offer ID"
+//	@Param			body	body		RespondBody	true	"Who accepts"
+//	@Success		200		{object}	offer.Offer
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Router			/offers/{id}/accept [post]
+func (h *Handler) accept(c *gin.Context) {
+	h.respond(c, false)
+}
+
+//	@Summary		Decline an offer
+//	@Tags			Offers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int			true	"Offer ID"
+//	@Param			body	body		RespondBody	true	"Who declines"
+//	@Success		200		{object}	offer.Offer
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Router			/offers/{id}/decline [post]
+func (h *Handler) decline(c *gin.Context) {
+	h.respond(c, true)
+}
+
+func (h *Handler) respond(c *gin.Context, decline bool) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	var body RespondBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	fn := h.svc.Accept
+	if decline {
+		fn = h.svc.Decline
+	}
+	o, err := fn(c.Request.Context(), id, body.ActorID)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, o)
+}
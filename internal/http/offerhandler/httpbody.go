@@ -0,0 +1,34 @@
+package offerhandler
+
+type SettingsBody struct {
+	SellerID    string   `json:"seller_id"              binding:"required" example:"seller123"`
+	BestOffer   bool     `json:"best_offer"             example:"true"`
+	AutoAccept  *float64 `json:"auto_accept,omitempty"  binding:"omitempty,gt=0" example:"900"`
+	AutoDecline *float64 `json:"auto_decline,omitempty" binding:"omitempty,gt=0" example:"500"`
+} // @name OfferSettingsRequest
+
+type SubmitBody struct {
+	BuyerID string  `json:"buyer_id" binding:"required" example:"user123"`
+	Amount  float64 `json:"amount"   binding:"required,gt=0" example:"750"`
+} // @name SubmitOfferRequest
+
+type CounterBody struct {
+	ActorID string  `json:"actor_id" binding:"required" example:"seller123"` // buyer or seller, whoever's turn it is
+	Amount  float64 `json:"amount"   binding:"required,gt=0" example:"850"`
+} // @name CounterOfferRequest
+
+type RespondBody struct {
+	ActorID string `json:"actor_id" binding:"required" example:"seller123"`
+} // @name RespondOfferRequest
+
+type ViewerQuery struct {
+	ViewerID string `form:"viewer_id" binding:"required"` // buyer or seller
+} // @name GetOfferQuery
+
+type SellerQuery struct {
+	SellerID string `form:"seller_id" binding:"required"`
+} // @name ListAuctionOffersQuery
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name OfferErrorResponse
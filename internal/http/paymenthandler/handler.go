@@ -0,0 +1,186 @@
+package paymenthandler
+
+import (
+	"auctionbidgo/internal/http/adminauth"
+	"auctionbidgo/internal/payments"
+	"auctionbidgo/internal/services/payment"
+	"auctionbidgo/internal/services/settlement"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc        payment.IPaymentService
+	invoices   settlement.ISettlementService
+	adminToken string
+}
+
+// New builds the handler; like invoices, payments are shown to (and opened
+// by) the invoice's buyer (viewer_id) or an admin.
+func New(svc payment.IPaymentService, invoices settlement.ISettlementService, adminToken string) *Handler {
+	return &Handler{svc: svc, invoices: invoices, adminToken: adminToken}
+}
+
+func (h *Handler) Register(r gin.IRoutes) {
+	r.POST("/invoices/:id/pay", h.pay)
+	r.GET("/payments/:id", h.get)
+	r.POST("/payments/webhook", h.webhook)
+}
+
+// RegisterAdmin mounts the admin actions; r is expected to be behind admin auth.
+func (h *Handler) RegisterAdmin(r gin.IRoutes) {
+	r.POST("/admin/payments/:id/refund", h.refund)
+}
+
+func status(err error) int {
+	switch {
+	case errors.Is(err, payment.ErrNotFound), errors.Is(err, settlement.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, payment.ErrNotPayable), errors.Is(err, payment.ErrNotRefundable),
+		errors.Is(err, payments.ErrDeclined):
+		return http.StatusConflict
+	case errors.Is(err, payments.ErrBadSignature):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// allowed reports whether the caller may act on the invoice: the admin
+// token, or viewer_id naming its party. When not, the response is written.
+func (h *Handler) allowed(c *gin.Context, invoiceID int64) bool {
+	if adminauth.IsAdmin(c, h.adminToken) {
+		return true
+	}
+	var q ViewerQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return false
+	}
+	inv, err := h.invoices.Get(c.Request.Context(), invoiceID)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return false
+	}
+	if q.ViewerID != inv.UserID {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "not your invoice"})
+		return false
+	}
+	return true
+}
+
+func idParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid id"})
+		return 0, false
+	}
+	return id, true
+}
+
+//	@Summary		Pay an invoice
+//	@Description	Opens a payment with the configured provider and returns it;
+//	@Description	send the buyer to checkout_url. Calling it again returns the
+//	@Description	open payment. The auction turns PAID once the payment is captured.
+//	@Tags			Payments
+//	@Produce		json
+//	@Param			id			path		int		true	"Invoice ID"
+//	@Param			viewer_id	query		string	true	"The invoice's buyer"
+//	@Success		200			{object}	payment.Payment
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Router			/invoices/{id}/pay [post]
+func (h *Handler) pay(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok || !h.allowed(c, id) {
+		return
+	}
+	p, err := h.svc.Pay(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+//	@Summary		Get a payment
+//	@Tags			Payments
+//	@Produce		json
+//	@Param			id			path		int		true	"Payment ID"
+//	@Param			viewer_id	query		string	true	"The invoice's buyer"
+//	@Success		200			{object}	payment.Payment
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Router			/payments/{id} [get]
+func (h *Handler) get(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	p, err := h.svc.Get(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !h.allowed(c, p.InvoiceID) {
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+//	@Summary		Payment provider webhook
+//	@Description	Signed notifications from the payment provider (authorised,
+//	@Description	captured, failed, refunded). Requests with a bad signature get 400.
+//	@Tags			Payments
+//	@Accept			json
+//	@Success		204
+//	@Failure		400	{object}	ErrorResponse
+//	@Router			/payments/webhook [post]
+func (h *Handler) webhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.svc.HandleWebhook(c.Request.Context(), c.Request.Header, body); err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+//	@Summary		Refund a payment
+//	@Description	Refunds a captured payment, fully or partially.
+//	@Tags			Payments
+//	@Accept			json
+//	@Produce		json
+//	@Security		AdminToken
+//	@Param			id		path		int			true	"Payment ID"
+//	@Param			body	body		RefundBody	false	"Amount (0 = full)"
+//	@Success		200		{object}	payment.Payment
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Router			/admin/payments/{id}/refund [post]
+func (h *Handler) refund(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	var body RefundBody
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+	p, err := h.svc.Refund(c.Request.Context(), id, body.Amount)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
@@ -0,0 +1,13 @@
+package paymenthandler
+
+type RefundBody struct {
+	Amount float64 `json:"amount,omitempty" binding:"gte=0" example:"0"` // 0 = everything not yet refunded
+} // @name RefundPaymentRequest
+
+type ViewerQuery struct {
+	ViewerID string `form:"viewer_id" binding:"required"` // the invoice's buyer
+} // @name PaymentViewerQuery
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name PaymentErrorResponse
@@ -0,0 +1,193 @@
+package registrationhandler
+
+import (
+	"auctionbidgo/internal/services/registration"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc registration.IRegistrationService
+}
+
+func New(svc registration.IRegistrationService) *Handler { return &Handler{svc: svc} }
+
+func (h *Handler) Register(r gin.IRoutes) {
+	r.PUT("/auctions/:id/access-settings", h.configure)
+	r.POST("/auctions/:id/registrations", h.request)
+	r.GET("/auctions/:id/registrations", h.listForAuction)
+	r.POST("/auctions/:id/registrations/:bidder_id/approve", h.approve)
+	r.POST("/auctions/:id/registrations/:bidder_id/reject", h.reject)
+	r.GET("/users/:id/registrations", h.listForBidder)
+}
+
+func status(err error) int {
+	switch {
+	case errors.Is(err, registration.ErrAuctionNotFound), errors.Is(err, registration.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, registration.ErrForbidden), errors.Is(err, registration.ErrOwnAuction):
+		return http.StatusForbidden
+	case errors.Is(err, registration.ErrBadVisibility):
+		return http.StatusBadRequest
+	case errors.Is(err, registration.ErrStarted), errors.Is(err, registration.ErrClosed),
+		errors.Is(err, registration.ErrNotRequired):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+//	@Summary		Configure visibility and registration
+//	@Description	Seller only. public auctions are listed, unlisted ones are reachable
+//	@Description	by ID only, private ones are neither listed nor viewable (WS, SSE)
+//	@Description	except by the seller and approved bidders. With registration
+//	@Description	required (always for private) only approved bidders may bid;
+//	@Description	it can only be changed before the auction starts.
+//	@Tags			Registration
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"Auction ID"	default(auc123)
+//	@Param			body	body		SettingsBody	true	"Access settings"
+//	@Success		200		{object}	registration.Settings
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse	"Auction started or finished"
+//	@Router			/auctions/{id}/access-settings [put]
+func (h *Handler) configure(c *gin.Context) {
+	var body SettingsBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	s, err := h.svc.Configure(c.Request.Context(), body.SellerID, registration.Settings{
+		AuctionID:            c.Param("id"),
+		Visibility:           body.Visibility,
+		RegistrationRequired: body.RegistrationRequired,
+	})
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+//	@Summary		Request to bid
+//	@Description	Asks the seller to approve the bidder; asking again returns the
+//	@Description	existing registration.
+//	@Tags			Registration
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string		true	"Auction ID"	default(auc123)
+//	@Param			body	body		RequestBody	true	"Bidder"
+//	@Success		200		{object}	registration.Registration
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse	"No registration required, or auction finished"
+//	@Router			/auctions/{id}/registrations [post]
+func (h *Handler) request(c *gin.Context) {
+	var body RequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	r, err := h.svc.Request(c.Request.Context(), c.Param("id"), body.BidderID, body.Note)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
+
+//	@Summary		List an auction's registrations
+//	@Description	Seller only, oldest first.
+//	@Tags			Registration
+//	@Produce		json
+//	@Param			id			path		string	true	"Auction ID"	default(auc123)
+//	@Param			seller_id	query		string	true	"Seller ID"
+//	@Param			status		query		string	false	"PENDING | APPROVED | REJECTED"
+//	@Success		200			{array}		registration.Registration
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Router			/auctions/{id}/registrations [get]
+func (h *Handler) listForAuction(c *gin.Context) {
+	var q ListQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	out, err := h.svc.ForAuction(c.Request.Context(), c.Param("id"), q.SellerID, q.Status)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+//	@Summary		Approve a registration
+//	@Description	Seller only. Assigns the bidder's paddle number (kept if they were
+//	@Description	approved before) and lets them bid, also while the auction runs.
+//	@Tags			Registration
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string		true	"Auction ID"	default(auc123)
+//	@Param			bidder_id	path		string		true	"Bidder ID"
+//	@Param			body		body		DecideBody	true	"Seller"
+//	@Success		200			{object}	registration.Registration
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse	"Not applied to bidding yet; retry"
+//	@Router			/auctions/{id}/registrations/{bidder_id}/approve [post]
+func (h *Handler) approve(c *gin.Context) {
+	h.decide(c, true)
+}
+
+//	@Summary		Reject a registration
+//	@Description	Seller only. Rejecting an approved bidder stops their further bids.
+//	@Tags			Registration
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string		true	"Auction ID"	default(auc123)
+//	@Param			bidder_id	path		string		true	"Bidder ID"
+//	@Param			body		body		DecideBody	true	"Seller"
+//	@Success		200			{object}	registration.Registration
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse	"Not applied to bidding yet; retry"
+//	@Router			/auctions/{id}/registrations/{bidder_id}/reject [post]
+func (h *Handler) reject(c *gin.Context) {
+	h.decide(c, false)
+}
+
+func (h *Handler) decide(c *gin.Context, approve bool) {
+	var body DecideBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	r, err := h.svc.Decide(c.Request.Context(), c.Param("id"), body.SellerID, c.Param("bidder_id"), approve)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
+
+//	@Summary		List a bidder's registrations
+//	@Description	Newest first, with status and paddle numbers.
+//	@Tags			Registration
+//	@Produce		json
+//	@Param			id	path		string	true	"Bidder ID"	default(user123)
+//	@Success		200	{array}		registration.Registration
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/users/{id}/registrations [get]
+func (h *Handler) listForBidder(c *gin.Context) {
+	out, err := h.svc.ForBidder(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
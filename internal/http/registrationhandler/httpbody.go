@@ -0,0 +1,25 @@
+package registrationhandler
+
+type SettingsBody struct {
+	SellerID             string `json:"seller_id"             binding:"required" example:"seller123"`
+	Visibility           string `json:"visibility"            binding:"required,oneof=public unlisted private" example:"private"`
+	RegistrationRequired bool   `json:"registration_required" example:"true"` // implied by private
+} // @name AccessSettingsRequest
+
+type RequestBody struct {
+	BidderID string `json:"bidder_id" binding:"required" example:"user123"`
+	Note     string `json:"note"      binding:"max=500" example:"Acme Ltd, buyer account 4711"`
+} // @name RegistrationRequest
+
+type DecideBody struct {
+	SellerID string `json:"seller_id" binding:"required" example:"seller123"`
+} // @name DecideRegistrationRequest
+
+type ListQuery struct {
+	SellerID string `form:"seller_id" binding:"required"`
+	Status   string `form:"status"    binding:"omitempty,oneof=PENDING APPROVED REJECTED"`
+} // @name ListRegistrationsQuery
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name RegistrationErrorResponse
@@ -0,0 +1,86 @@
+package relisthandler
+
+import (
+	"auctionbidgo/internal/services/relist"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc relist.IRelistService
+}
+
+func New(svc relist.IRelistService) *Handler { return &Handler{svc: svc} }
+
+func (h *Handler) Register(r gin.IRoutes) {
+	r.GET("/auctions/:id/relist-rules", h.get)
+	r.PUT("/auctions/:id/relist-rules", h.set)
+}
+
+func status(err error) int {
+	switch {
+	case errors.Is(err, relist.ErrAuctionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, relist.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, relist.ErrEnded):
+		return http.StatusConflict
+	case errors.Is(err, relist.ErrNoDuration):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+//	@Summary		Get the relist rules of an auction
+//	@Description	Also shows where the auction sits in its relist chain.
+//	@Tags			Relisting
+//	@Produce		json
+//	@Param			id	path		string	true	"Auction ID"	default(auc123)
+//	@Success		200	{object}	relist.Rules
+//	@Failure		404	{object}	ErrorResponse
+//	@Router			/auctions/{id}/relist-rules [get]
+func (h *Handler) get(c *gin.Context) {
+	r, err := h.svc.GetRules(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
+
+//	@Summary		Set the relist rules of an auction
+//	@Description	Seller only, before the auction ends. When it finishes without a
+//	@Description	winner a linked successor auction of duration_seconds is created and
+//	@Description	started, its start price cut by price_drop_pct (not below min_price),
+//	@Description	with one relist less. See `relist` on GET /auctions/{id}.
+//	@Tags			Relisting
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string		true	"Auction ID"	default(auc123)
+//	@Param			body	body		RulesBody	true	"Relist rules"
+//	@Success		200		{object}	relist.Rules
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse	"Auction already ended"
+//	@Router			/auctions/{id}/relist-rules [put]
+func (h *Handler) set(c *gin.Context) {
+	var body RulesBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	r, err := h.svc.SetRules(c.Request.Context(), body.SellerID, relist.Rules{
+		AuctionID:       c.Param("id"),
+		Relists:         body.Relists,
+		DurationSeconds: body.DurationSeconds,
+		PriceDropPct:    body.PriceDropPct,
+		MinPrice:        body.MinPrice,
+	})
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
@@ -0,0 +1,13 @@
+package relisthandler
+
+type RulesBody struct {
+	SellerID        string   `json:"seller_id"           binding:"required" example:"seller123"`
+	Relists         int      `json:"relists"             binding:"gte=0,lte=52" example:"3"` // 0 turns relisting off
+	DurationSeconds int      `json:"duration_seconds"    binding:"omitempty,gte=60" example:"86400"`
+	PriceDropPct    float64  `json:"price_drop_pct"      binding:"gte=0,lt=100" example:"10"`
+	MinPrice        *float64 `json:"min_price,omitempty" binding:"omitempty,gte=0" example:"50"`
+} // @name RelistRulesRequest
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name RelistErrorResponse
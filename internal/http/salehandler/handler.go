@@ -0,0 +1,258 @@
+package salehandler
+
+import (
+	"auctionbidgo/internal/services/auction"
+	"auctionbidgo/internal/services/sale"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc sale.ISaleService
+}
+
+func New(svc sale.ISaleService) *Handler { return &Handler{svc: svc} }
+
+func (h *Handler) Register(r gin.IRoutes) {
+	r.POST("/sales", h.create)
+	r.GET("/sales", h.list)
+	r.GET("/sales/:id", h.get)
+	r.PUT("/sales/:id", h.update)
+	r.POST("/sales/:id/lots", h.addLot)
+	r.PUT("/sales/:id/lots/order", h.reorder)
+	r.DELETE("/sales/:id/lots/:auction_id", h.removeLot)
+	r.POST("/sales/:id/publish", h.publish)
+}
+
+func status(err error) int {
+	switch {
+	case errors.Is(err, sale.ErrNotFound), errors.Is(err, sale.ErrLotNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, sale.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, sale.ErrBadSchedule), errors.Is(err, sale.ErrBadOrder):
+		return http.StatusBadRequest
+	case errors.Is(err, sale.ErrExists), errors.Is(err, sale.ErrNotDraft), errors.Is(err, sale.ErrClosed),
+		errors.Is(err, sale.ErrEmpty), errors.Is(err, sale.ErrLotUnavailable),
+		errors.Is(err, auction.ErrAuctionClosed), errors.Is(err, auction.ErrAuctionFinished):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+func (b SaleBody) sale(id string) sale.Sale {
+	return sale.Sale{
+		ID:              id,
+		SellerID:        b.SellerID,
+		Title:           b.Title,
+		ClosingStartsAt: b.ClosingStartsAt.UTC(),
+		LotInterval:     b.LotInterval,
+		ExtendWindow:    b.ExtendWindow,
+		ExtendBy:        b.ExtendBy,
+	}
+}
+
+//	@Summary		Create a sale (draft)
+//	@Description	A catalog of lots closing one after another: lot N closes at
+//	@Description	closing_starts_at + (N‑1)·lot_interval. A bid within extend_window
+//	@Description	seconds of a lot's end moves it to bid time + extend_by, and shifts
+//	@Description	every later lot by as much.
+//	@Tags			Sales
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		SaleBody	true	"Sale"
+//	@Success		201		{object}	sale.Sale
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Router			/sales [post]
+func (h *Handler) create(c *gin.Context) {
+	var body SaleBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	s, err := h.svc.Create(c.Request.Context(), body.sale(strings.TrimSpace(body.ID)))
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, s)
+}
+
+//	@Summary		List sales
+//	@Tags			Sales
+//	@Produce		json
+//	@Param			query	query	ListSalesQuery	false	"Filters and paging"
+//	@Success		200		{array}	sale.Sale
+//	@Router			/sales [get]
+func (h *Handler) list(c *gin.Context) {
+	var q ListSalesQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	out, err := h.svc.List(c.Request.Context(), q.Status, q.Limit, q.Offset)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+//	@Summary		Get a sale's catalog
+//	@Description	The sale with its lots in order; running lots show live values.
+//	@Description	Private lots are listed only for their seller and approved bidders.
+//	@Tags			Sales
+//	@Produce		json
+//	@Param			id			path		string	true	"Sale ID"
+//	@Param			viewer_id	query		string	false	"Who is asking (shows the private lots they may see)"
+//	@Success		200			{object}	sale.Catalog
+//	@Failure		404			{object}	ErrorResponse
+//	@Router			/sales/{id} [get]
+func (h *Handler) get(c *gin.Context) {
+	var q ViewerQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	cat, err := h.svc.Get(c.Request.Context(), c.Param("id"), q.ViewerID)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cat)
+}
+
+//	@Summary		Update a draft sale
+//	@Tags			Sales
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string		true	"Sale ID"
+//	@Param			body	body		SaleBody	true	"Sale (id is ignored)"
+//	@Success		200		{object}	sale.Sale
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse	"Already published"
+//	@Router			/sales/{id} [put]
+func (h *Handler) update(c *gin.Context) {
+	var body SaleBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	s, err := h.svc.Update(c.Request.Context(), body.SellerID, body.sale(c.Param("id")))
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+//	@Summary		Add a lot
+//	@Description	Appends an existing PENDING auction of the seller, or a new one
+//	@Description	for item, as the next lot of a draft sale.
+//	@Tags			Sales
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string		true	"Sale ID"
+//	@Param			body	body		AddLotBody	true	"Auction or item"
+//	@Success		201		{object}	sale.Catalog
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Router			/sales/{id}/lots [post]
+func (h *Handler) addLot(c *gin.Context) {
+	var body AddLotBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	cat, err := h.svc.AddLot(c.Request.Context(), c.Param("id"), body.SellerID, sale.NewLot{
+		AuctionID:  strings.TrimSpace(body.AuctionID),
+		Item:       body.Item,
+		StartPrice: body.StartPrice,
+	})
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, cat)
+}
+
+//	@Summary		Reorder the lots
+//	@Tags			Sales
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string		true	"Sale ID"
+//	@Param			body	body		ReorderBody	true	"Every lot's auction ID, in order"
+//	@Success		200		{object}	sale.Catalog
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Router			/sales/{id}/lots/order [put]
+func (h *Handler) reorder(c *gin.Context) {
+	var body ReorderBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	cat, err := h.svc.Reorder(c.Request.Context(), c.Param("id"), body.SellerID, body.AuctionIDs)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cat)
+}
+
+//	@Summary		Remove a lot
+//	@Description	The auction stays, as a plain PENDING auction; later lots move up.
+//	@Tags			Sales
+//	@Produce		json
+//	@Param			id			path		string	true	"Sale ID"
+//	@Param			auction_id	path		string	true	"Auction ID of the lot"
+//	@Param			seller_id	query		string	true	"Seller ID"
+//	@Success		200			{object}	sale.Catalog
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Router			/sales/{id}/lots/{auction_id} [delete]
+func (h *Handler) removeLot(c *gin.Context) {
+	var q SellerQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	cat, err := h.svc.RemoveLot(c.Request.Context(), c.Param("id"), q.SellerID, c.Param("auction_id"))
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cat)
+}
+
+//	@Summary		Publish a sale
+//	@Description	Fixes every lot's end time and opens bidding on all lots. Safe to
+//	@Description	repeat: lots that failed to start are started again. Subscribe to
+//	@Description	the sale over WS (`sales/subscribe`) for lot transitions.
+//	@Tags			Sales
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string		true	"Sale ID"
+//	@Param			body	body		SellerBody	true	"Seller"
+//	@Success		200		{object}	sale.Catalog
+//	@Failure		400		{object}	ErrorResponse	"Closing start too close or past"
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Router			/sales/{id}/publish [post]
+func (h *Handler) publish(c *gin.Context) {
+	var body SellerBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	cat, err := h.svc.Publish(c.Request.Context(), c.Param("id"), body.SellerID)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cat)
+}
@@ -0,0 +1,48 @@
+package salehandler
+
+import "time"
+
+type SaleBody struct {
+	ID string `json:"id,omitempty" example:"estate-2025-09"` // create only; generated when empty
+
+	SellerID        string    `json:"seller_id"         binding:"required" example:"seller123"`
+	Title           string    `json:"title"             binding:"required" example:"Estate sale – September"`
+	ClosingStartsAt time.Time `json:"closing_starts_at" binding:"required" example:"2025-09-20T18:00:00Z"` // lot 1 closes then
+	LotInterval     int       `json:"lot_interval"      binding:"required,gte=1,lte=86400" example:"60"` // seconds
+	ExtendWindow    int       `json:"extend_window"     binding:"gte=0,lte=3600" example:"120"`          // seconds; 0 = no soft close
+	ExtendBy        int       `json:"extend_by"         binding:"required_with=ExtendWindow,gte=0,lte=3600" example:"120"`
+} // @name SaleRequest
+
+type AddLotBody struct {
+	SellerID   string  `json:"seller_id"             binding:"required" example:"seller123"`
+	AuctionID  string  `json:"auction_id,omitempty"  example:"auc123"` // an existing PENDING auction, or:
+	Item       string  `json:"item,omitempty"        binding:"required_without=AuctionID" example:"Oak writing desk"`
+	StartPrice float64 `json:"start_price,omitempty" binding:"gte=0" example:"100"`
+} // @name AddLotRequest
+
+type ReorderBody struct {
+	SellerID   string   `json:"seller_id"   binding:"required" example:"seller123"`
+	AuctionIDs []string `json:"auction_ids" binding:"required,min=1"` // every lot, in the new order
+} // @name ReorderLotsRequest
+
+type SellerBody struct {
+	SellerID string `json:"seller_id" binding:"required" example:"seller123"`
+} // @name PublishSaleRequest
+
+type SellerQuery struct {
+	SellerID string `form:"seller_id" binding:"required"`
+} // @name RemoveLotQuery
+
+type ViewerQuery struct {
+	ViewerID string `form:"viewer_id"`
+} // @name SaleViewerQuery
+
+type ListSalesQuery struct {
+	Status string `form:"status"  binding:"omitempty,oneof=DRAFT PUBLISHED CLOSED"`
+	Limit  int    `form:"limit,default=10"  binding:"gte=0,lte=100"`
+	Offset int    `form:"offset,default=0"  binding:"gte=0"`
+} // @name ListSalesQuery
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name SaleErrorResponse
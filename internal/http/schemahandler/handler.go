@@ -0,0 +1,50 @@
+package schemahandler
+
+import (
+	"auctionbidgo/internal/events"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler publishes the JSON Schemas of the auction event bodies so client
+// teams can generate their types.
+type Handler struct{}
+
+func New() *Handler { return &Handler{} }
+
+func (h *Handler) Register(r gin.IRoutes) {
+	r.GET("/schemas/events", h.list)
+	r.GET("/schemas/events/:name", h.get)
+}
+
+//	@Summary		List event schemas
+//	@Description	Names of the `auctions/*` and `sales/*` events that have a published JSON Schema.
+//	@Tags			Auctions
+//	@Produce		json
+//	@Success		200	{object}	map[string]any	"version + names"
+//	@Router			/schemas/events [get]
+func (h *Handler) list(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"version": events.Version,
+		"events":  events.Names(),
+	})
+}
+
+//	@Summary		Get an event schema
+//	@Description	JSON Schema (draft 2020‑12) of one `auctions/<name>` or `sales/<name>`
+//	@Description	event body (`lot` and `status` are the sale channel's).
+//	@Tags			Auctions
+//	@Produce		json
+//	@Param			name	path		string	true	"Event name"	Enums(snapshot,start,bid,stop,extended,presence,live,chat,chat_retracted,time,lot,status)
+//	@Success		200		{object}	map[string]any
+//	@Failure		404		{object}	map[string]string
+//	@Router			/schemas/events/{name} [get]
+func (h *Handler) get(c *gin.Context) {
+	s, ok := events.JSONSchema(c.Param("name"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown event"})
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
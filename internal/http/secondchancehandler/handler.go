@@ -0,0 +1,165 @@
+package secondchancehandler
+
+import (
+	"auctionbidgo/internal/services/secondchance"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc secondchance.ISecondChanceService
+}
+
+func New(svc secondchance.ISecondChanceService) *Handler { return &Handler{svc: svc} }
+
+func (h *Handler) Register(r gin.IRoutes) {
+	r.GET("/auctions/:id/second-chance", h.list)
+	r.POST("/auctions/:id/second-chance", h.offer)
+	r.GET("/auctions/:id/second-chance/candidates", h.candidates)
+	r.GET("/auctions/:id/second-chance/audit", h.audit)
+	r.POST("/second-chance/:id/accept", h.accept)
+	r.POST("/second-chance/:id/decline", h.decline)
+}
+
+func status(err error) int {
+	switch {
+	case errors.Is(err, secondchance.ErrAuctionNotFound), errors.Is(err, secondchance.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, secondchance.ErrNotSeller), errors.Is(err, secondchance.ErrNotOfferee):
+		return http.StatusForbidden
+	case errors.Is(err, secondchance.ErrNotUnpaid), errors.Is(err, secondchance.ErrOfferOpen),
+		errors.Is(err, secondchance.ErrNoCandidate), errors.Is(err, secondchance.ErrNotEligible),
+		errors.Is(err, secondchance.ErrOfferClosed):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+//	@Summary		List eligible runner-ups
+//	@Description	Bidders who can still get a second-chance offer, best bid first:
+//	@Description	everyone but the winner(s) so far and those already asked.
+//	@Tags			Auctions
+//	@Produce		json
+//	@Param			id	path		string	true	"Auction ID"	default(auc123)
+//	@Success		200	{array}		secondchance.Candidate
+//	@Failure		404	{object}	ErrorResponse
+//	@Router			/auctions/{id}/second-chance/candidates [get]
+func (h *Handler) candidates(c *gin.Context) {
+	out, err := h.svc.Candidates(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+//	@Summary		Make a second-chance offer
+//	@Description	Offers an UNPAID auction's item to a runner-up at their own last
+//	@Description	bid, for a limited time. Only one offer can be open at a time.
+//	@Tags			Auctions
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"Auction ID"	default(auc123)
+//	@Param			body	body		CreateOfferBody	true	"Seller and (optional) bidder"
+//	@Success		201		{object}	secondchance.Offer
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Router			/auctions/{id}/second-chance [post]
+func (h *Handler) offer(c *gin.Context) {
+	var body CreateOfferBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	o, err := h.svc.Offer(c.Request.Context(), c.Param("id"), body.SellerID, body.BidderID)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, o)
+}
+
+//	@Summary		List an auction's second-chance offers
+//	@Tags			Auctions
+//	@Produce		json
+//	@Param			id	path	string	true	"Auction ID"	default(auc123)
+//	@Success		200	{array}	secondchance.Offer
+//	@Router			/auctions/{id}/second-chance [get]
+func (h *Handler) list(c *gin.Context) {
+	out, err := h.svc.ListForAuction(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+//	@Summary		Second-chance audit trail
+//	@Description	Every offer, answer, expiry and re-settlement of the auction, in order.
+//	@Tags			Auctions
+//	@Produce		json
+//	@Param			id	path	string	true	"Auction ID"	default(auc123)
+//	@Success		200	{array}	secondchance.AuditEntry
+//	@Router			/auctions/{id}/second-chance/audit [get]
+func (h *Handler) audit(c *gin.Context) {
+	out, err := h.svc.Audit(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+//	@Summary		Accept a second-chance offer
+//	@Description	The auction is re-settled to the bidder: the unpaid invoices are
+//	@Description	voided and new ones issued at the offered amount.
+//	@Tags			Auctions
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int			true	"Offer ID"
+//	@Param			body	body		RespondBody	true	"The offered bidder"
+//	@Success		200		{object}	secondchance.Offer
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Router			/second-chance/{id}/accept [post]
+func (h *Handler) accept(c *gin.Context) {
+	h.respond(c, h.svc.Accept)
+}
+
+//	@Summary		Decline a second-chance offer
+//	@Tags			Auctions
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int			true	"Offer ID"
+//	@Param			body	body		RespondBody	true	"The offered bidder"
+//	@Success		200		{object}	secondchance.Offer
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Router			/second-chance/{id}/decline [post]
+func (h *Handler) decline(c *gin.Context) {
+	h.respond(c, h.svc.Decline)
+}
+
+func (h *Handler) respond(c *gin.Context,
+	fn func(ctx context.Context, offerID int64, bidderID string) (*secondchance.Offer, error)) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid id"})
+		return
+	}
+	var body RespondBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	o, err := fn(c.Request.Context(), id, body.BidderID)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, o)
+}
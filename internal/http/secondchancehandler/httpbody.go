@@ -0,0 +1,14 @@
+package secondchancehandler
+
+type CreateOfferBody struct {
+	SellerID string `json:"seller_id"           binding:"required" example:"seller123"`
+	BidderID string `json:"bidder_id,omitempty" example:""` // empty = best runner‑up
+} // @name CreateSecondChanceOfferRequest
+
+type RespondBody struct {
+	BidderID string `json:"bidder_id" binding:"required" example:"user123"`
+} // @name RespondSecondChanceOfferRequest
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name SecondChanceErrorResponse
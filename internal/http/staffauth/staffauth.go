@@ -0,0 +1,83 @@
+// Package staffauth issues and checks staff tokens: short‑lived, HMAC‑signed
+// credentials binding a user ID, handed out by an admin
+// (POST /admin/staff-tokens) and presented on the WS connect
+// (?staff_token=…). Staff‑only actions (chat/delete, live/*) trust the
+// token's user ID, never the self‑asserted ?user_id=; the moderator,
+// auctioneer and clerk lists then pick the roles among verified staff.
+//
+//	token = base64url(user_id) "." expiry (unix seconds) "." base64url(hmac)
+package staffauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrDisabled = errors.New("staff tokens are disabled (STAFF_TOKEN_SECRET unset)")
+	ErrInvalid  = errors.New("invalid staff token")
+	ErrExpired  = errors.New("staff token expired")
+)
+
+// Signer signs and verifies staff tokens. A nil *Signer (no secret
+// configured) rejects every token, so staff actions fail closed.
+type Signer struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// New returns a Signer for secret, or nil when secret is empty.
+func New(secret string) *Signer {
+	if secret == "" {
+		return nil
+	}
+	return &Signer{secret: []byte(secret), now: time.Now}
+}
+
+// Issue returns a token for userID valid for ttl, and its expiry.
+func (s *Signer) Issue(userID string, ttl time.Duration) (string, time.Time, error) {
+	if s == nil {
+		return "", time.Time{}, ErrDisabled
+	}
+	exp := s.now().Add(ttl).Truncate(time.Second)
+	id := base64.RawURLEncoding.EncodeToString([]byte(userID))
+	ts := strconv.FormatInt(exp.Unix(), 10)
+	return id + "." + ts + "." + s.sign(id, ts), exp, nil
+}
+
+// Verify checks the signature and expiry of token and returns its user ID.
+func (s *Signer) Verify(token string) (string, error) {
+	if s == nil {
+		return "", ErrDisabled
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalid
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(s.sign(parts[0], parts[1]))) {
+		return "", ErrInvalid
+	}
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	if !s.now().Before(time.Unix(exp, 0)) {
+		return "", ErrExpired
+	}
+	id, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(id) == 0 {
+		return "", ErrInvalid
+	}
+	return string(id), nil
+}
+
+func (s *Signer) sign(id, ts string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("staff." + id + "." + ts))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
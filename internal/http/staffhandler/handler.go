@@ -0,0 +1,67 @@
+package staffhandler
+
+import (
+	"auctionbidgo/internal/http/staffauth"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultTTL = 12 * time.Hour
+	maxTTL     = 7 * 24 * time.Hour
+)
+
+type Handler struct {
+	signer *staffauth.Signer
+}
+
+func New(signer *staffauth.Signer) *Handler { return &Handler{signer: signer} }
+
+// RegisterAdmin mounts the admin actions; r is expected to be behind admin auth.
+func (h *Handler) RegisterAdmin(r gin.IRoutes) {
+	r.POST("/admin/staff-tokens", h.issue)
+}
+
+//	@Summary		Issue a staff token
+//	@Description	Signs a short‑lived token for a moderator, auctioneer or clerk.
+//	@Description	It is passed on the WS connect as `?staff_token=…` together
+//	@Description	with the same `user_id`; chat/delete and live/* only act for
+//	@Description	verified staff. 503 when STAFF_TOKEN_SECRET is unset.
+//	@Tags			Auctions
+//	@Accept			json
+//	@Produce		json
+//	@Security		AdminToken
+//	@Param			body	body		IssueTokenBody	true	"Staff member"
+//	@Success		201		{object}	TokenResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		503		{object}	ErrorResponse
+//	@Router			/admin/staff-tokens [post]
+func (h *Handler) issue(c *gin.Context) {
+	var body IssueTokenBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	ttl := defaultTTL
+	if body.TTL != "" {
+		d, err := time.ParseDuration(body.TTL)
+		if err != nil || d <= 0 || d > maxTTL {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "ttl must be a duration between 0 and 168h"})
+			return
+		}
+		ttl = d
+	}
+	token, exp, err := h.signer.Issue(body.UserID, ttl)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, staffauth.ErrDisabled) {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, TokenResponse{UserID: body.UserID, Token: token, ExpiresAt: exp})
+}
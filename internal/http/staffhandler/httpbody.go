@@ -0,0 +1,18 @@
+package staffhandler
+
+import "time"
+
+type IssueTokenBody struct {
+	UserID string `json:"user_id" binding:"required" example:"mod1"`
+	TTL    string `json:"ttl"     example:"12h"` // Go duration; default 12h, max 7 days
+} // @name IssueStaffTokenRequest
+
+type TokenResponse struct {
+	UserID    string    `json:"user_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+} // @name StaffTokenResponse
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name StaffErrorResponse
@@ -13,5 +13,10 @@ package swaggerapis
 //	@Accept						json
 //	@Produce					json
 //
+//	@securityDefinitions.apikey	AdminToken
+//	@in							header
+//	@name						Authorization
+//	@description				"Bearer <ADMIN_API_TOKEN>"
+//
 //	@externalDocs.description	OpenAPI
 //	@externalDocs.url			https://videocast.io/resources/open-api/all
@@ -0,0 +1,35 @@
+// Package viewauth applies an auction's visibility (see registration) to the
+// REST routes that show it, as the WS join and SSE do: a private auction
+// answers 404 unless ?viewer_id= names its seller or an approved bidder, or
+// the request carries the admin token.
+package viewauth
+
+import (
+	"auctionbidgo/internal/http/adminauth"
+	"auctionbidgo/internal/services/registration"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Require guards routes whose :id is an auction ID.
+func Require(svc registration.IRegistrationService, adminToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if adminauth.IsAdmin(c, adminToken) {
+			c.Next()
+			return
+		}
+		ok, err := svc.CanView(c.Request.Context(), c.Param("id"), c.Query("viewer_id"))
+		if err != nil {
+			zap.L().Warn("viewauth.can_view", zap.String("auction", c.Param("id")), zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "auction not found"})
+			return
+		}
+		c.Next()
+	}
+}
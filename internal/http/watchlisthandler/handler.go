@@ -0,0 +1,82 @@
+package watchlisthandler
+
+import (
+	"auctionbidgo/internal/services/watchlist"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc watchlist.IWatchlistService
+}
+
+func New(svc watchlist.IWatchlistService) *Handler { return &Handler{svc: svc} }
+
+func (h *Handler) Register(r gin.IRoutes) {
+	r.GET("/users/:id/watchlist", h.list)
+	r.POST("/users/:id/watchlist", h.add)
+	r.DELETE("/users/:id/watchlist/:auction_id", h.remove)
+}
+
+//	@Summary		List a user's watchlist
+//	@Description	Watched auctions with their last persisted state, most recently added first.
+//	@Tags			Auctions
+//	@Produce		json
+//	@Param			id	path		string	true	"User ID"	default(user123)
+//	@Success		200	{array}		watchlist.Item
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/users/{id}/watchlist [get]
+func (h *Handler) list(c *gin.Context) {
+	out, err := h.svc.List(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+//	@Summary		Watch an auction
+//	@Description	The user is notified when it starts, when it is about to end
+//	@Description	(`NOTIFY_ENDING_SOON` thresholds) and whenever they are outbid.
+//	@Description	Adding an auction twice is a no‑op.
+//	@Tags			Auctions
+//	@Accept			json
+//	@Param			id		path	string			true	"User ID"	default(user123)
+//	@Param			body	body	AddWatchBody	true	"Auction to watch"
+//	@Success		204
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Router			/users/{id}/watchlist [post]
+func (h *Handler) add(c *gin.Context) {
+	var body AddWatchBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.svc.Add(c.Request.Context(), c.Param("id"), body.AuctionID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, watchlist.ErrAuctionNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+//	@Summary		Stop watching an auction
+//	@Tags			Auctions
+//	@Param			id			path	string	true	"User ID"		default(user123)
+//	@Param			auction_id	path	string	true	"Auction ID"	default(auc123)
+//	@Success		204
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/users/{id}/watchlist/{auction_id} [delete]
+func (h *Handler) remove(c *gin.Context) {
+	if err := h.svc.Remove(c.Request.Context(), c.Param("id"), c.Param("auction_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
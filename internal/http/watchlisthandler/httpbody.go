@@ -0,0 +1,9 @@
+package watchlisthandler
+
+type AddWatchBody struct {
+	AuctionID string `json:"auction_id" binding:"required" example:"auc123"`
+} // @name AddWatchBody
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name WatchlistErrorResponse
@@ -0,0 +1,210 @@
+package webhookhandler
+
+import (
+	"auctionbidgo/internal/services/webhook"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc webhook.IWebhookService
+}
+
+func New(svc webhook.IWebhookService) *Handler { return &Handler{svc: svc} }
+
+// Register mounts the admin API; r is expected to be behind admin auth.
+func (h *Handler) Register(r gin.IRoutes) {
+	r.POST("/admin/webhooks", h.create)
+	r.GET("/admin/webhooks", h.list)
+	r.GET("/admin/webhooks/:id", h.get)
+	r.PATCH("/admin/webhooks/:id", h.update)
+	r.DELETE("/admin/webhooks/:id", h.delete)
+	r.GET("/admin/webhooks/dead-letters", h.deadLetters)
+	r.POST("/admin/webhooks/dead-letters/:id/redeliver", h.redeliver)
+}
+
+func status(err error) int {
+	switch {
+	case errors.Is(err, webhook.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, webhook.ErrInvalidURL), errors.Is(err, webhook.ErrUnknownEventType):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func idParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid id"})
+		return 0, false
+	}
+	return id, true
+}
+
+// ───────────────────────────────────────────────────────────────────────────────
+//	@Summary		Create a webhook subscription
+//	@Description	Registers an endpoint for auction lifecycle events. Deliveries are
+//	@Description	signed with HMAC‑SHA256 in `X-Auction-Signature` (`t=<unix>,v1=<hex>`
+//	@Description	over `<t>.<body>`). The secret is generated when omitted and is only
+//	@Description	returned by this call.
+//	@Tags			Webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		AdminToken
+//	@Param			body	body		CreateWebhookBody	true	"Subscription"
+//	@Success		201		{object}	webhook.Subscription
+//	@Failure		400		{object}	ErrorResponse
+//	@Router			/admin/webhooks [post]
+func (h *Handler) create(c *gin.Context) {
+	var body CreateWebhookBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	s, err := h.svc.Create(c.Request.Context(), body.URL, body.EventTypes, body.Secret)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, s)
+}
+
+//	@Summary		List webhook subscriptions
+//	@Tags			Webhooks
+//	@Produce		json
+//	@Security		AdminToken
+//	@Success		200	{array}		webhook.Subscription
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/admin/webhooks [get]
+func (h *Handler) list(c *gin.Context) {
+	out, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+//	@Summary		Get a webhook subscription
+//	@Tags			Webhooks
+//	@Produce		json
+//	@Security		AdminToken
+//	@Param			id	path		int	true	"Subscription ID"
+//	@Success		200	{object}	webhook.Subscription
+//	@Failure		404	{object}	ErrorResponse
+//	@Router			/admin/webhooks/{id} [get]
+func (h *Handler) get(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	s, err := h.svc.Get(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+//	@Summary		Update a webhook subscription
+//	@Description	Only the fields present are changed; `active=false` pauses deliveries.
+//	@Tags			Webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		AdminToken
+//	@Param			id		path		int					true	"Subscription ID"
+//	@Param			body	body		UpdateWebhookBody	true	"Changes"
+//	@Success		200		{object}	webhook.Subscription
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Router			/admin/webhooks/{id} [patch]
+func (h *Handler) update(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	var body UpdateWebhookBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	s, err := h.svc.Update(c.Request.Context(), id, webhook.SubscriptionPatch{
+		URL:        body.URL,
+		EventTypes: body.EventTypes,
+		Secret:     body.Secret,
+		Active:     body.Active,
+	})
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, s)
+}
+
+//	@Summary		Delete a webhook subscription
+//	@Description	Also drops its pending deliveries and dead letters.
+//	@Tags			Webhooks
+//	@Security		AdminToken
+//	@Param			id	path	int	true	"Subscription ID"
+//	@Success		204
+//	@Failure		404	{object}	ErrorResponse
+//	@Router			/admin/webhooks/{id} [delete]
+func (h *Handler) delete(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+//	@Summary		List dead‑lettered deliveries
+//	@Description	Deliveries that exhausted their retries, newest first.
+//	@Tags			Webhooks
+//	@Produce		json
+//	@Security		AdminToken
+//	@Param			limit	query		int	false	"Max results (0‑500)"	minimum(0)	maximum(500)	default(50)
+//	@Param			offset	query		int	false	"Offset for pagination"	minimum(0)	default(0)
+//	@Success		200		{array}		webhook.DeadLetter
+//	@Failure		400		{object}	ErrorResponse
+//	@Router			/admin/webhooks/dead-letters [get]
+func (h *Handler) deadLetters(c *gin.Context) {
+	var q ListDeadLettersQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	out, err := h.svc.ListDeadLetters(c.Request.Context(), q.Limit, q.Offset)
+	if err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+//	@Summary		Redeliver a dead letter
+//	@Description	Puts the delivery back on the queue with a fresh retry budget.
+//	@Tags			Webhooks
+//	@Security		AdminToken
+//	@Param			id	path	int	true	"Dead letter ID"
+//	@Success		202
+//	@Failure		404	{object}	ErrorResponse
+//	@Router			/admin/webhooks/dead-letters/{id}/redeliver [post]
+func (h *Handler) redeliver(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	if err := h.svc.Redeliver(c.Request.Context(), id); err != nil {
+		c.JSON(status(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
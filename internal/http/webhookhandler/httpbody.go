@@ -0,0 +1,23 @@
+package webhookhandler
+
+type CreateWebhookBody struct {
+	URL        string   `json:"url"                   binding:"required,url" example:"http://localhost:9292/hooks"`
+	EventTypes []string `json:"event_types,omitempty" example:"auction.outbid,auction.finished"`
+	Secret     string   `json:"secret,omitempty"      example:""` // generated when empty
+} // @name CreateWebhookRequest
+
+type UpdateWebhookBody struct {
+	URL        *string   `json:"url,omitempty"         binding:"omitempty,url"`
+	EventTypes *[]string `json:"event_types,omitempty"`
+	Secret     *string   `json:"secret,omitempty"`
+	Active     *bool     `json:"active,omitempty"`
+} // @name UpdateWebhookRequest
+
+type ListDeadLettersQuery struct {
+	Limit  int `form:"limit,default=50" binding:"gte=0,lte=500"`
+	Offset int `form:"offset,default=0" binding:"gte=0"`
+} // @name ListDeadLettersQuery
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+} // @name WebhookErrorResponse
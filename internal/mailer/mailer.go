@@ -0,0 +1,205 @@
+// Package mailer renders notification emails from templates, queues them in
+// the notifications table and sends them over SMTP with retries.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Mail kinds; each has a templates/<kind>.txt and templates/<kind>.html.
+const (
+	KindWinner          = "winner"
+	KindSellerSold      = "seller_sold"
+	KindSellerUnsold    = "seller_unsold"
+	KindOutbid          = "outbid"
+	KindAuctionStarting = "auction_starting"
+	KindEndingSoon      = "ending_soon"
+	KindSecondChance    = "second_chance"
+)
+
+var kinds = []string{
+	KindWinner, KindSellerSold, KindSellerUnsold,
+	KindOutbid, KindAuctionStarting, KindEndingSoon,
+	KindSecondChance,
+}
+
+var ErrUnknownKind = errors.New("unknown mail kind")
+
+//go:embed templates
+var templateFS embed.FS
+
+var funcs = map[string]any{
+	"money": func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) },
+	// dur prints a remaining time the way people say it: "10m", "1h5m", "45s".
+	"dur": func(d time.Duration) string {
+		if d >= time.Minute {
+			s := strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+			if strings.HasSuffix(s, "h0m") {
+				s = strings.TrimSuffix(s, "0m")
+			}
+			return s
+		}
+		return d.Round(time.Second).String()
+	},
+}
+
+// Config configures the SMTP server and the retry policy.
+type Config struct {
+	Addr          string // host:port
+	Username      string // empty = no AUTH (MailHog)
+	Password      string
+	From          string
+	AddressFormat string // fmt verb for the user ID, e.g. "%s@users.localhost"
+	BaseURL       string // for links back to the auction
+
+	MaxAttempts  int           // after this many failures a mail is marked failed
+	BackoffBase  time.Duration // delay after the first failure; doubles per attempt
+	BackoffMax   time.Duration
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+func (c *Config) defaults() {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 6
+	}
+	if c.BackoffBase <= 0 {
+		c.BackoffBase = 5 * time.Second
+	}
+	if c.BackoffMax <= 0 {
+		c.BackoffMax = 30 * time.Minute
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = 2 * time.Second
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 20
+	}
+}
+
+// Backoff returns the delay before retry number attempt (1‑based).
+func (c Config) Backoff(attempt int) time.Duration {
+	d := c.BackoffBase
+	for i := 1; i < attempt && d < c.BackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, c.BackoffMax)
+}
+
+// Data is what the templates see. Item and EndsAt are looked up from the
+// auction when left empty.
+type Data struct {
+	UserID    string
+	AuctionID string
+	Item      string
+	Amount    float64 // winning / new high bid
+	Bidder    string  // winner, for the seller
+	EndsAt    time.Time
+	Left      time.Duration // ending_soon: time remaining
+	OfferID   int64         // second_chance
+	ExpiresAt time.Time     // second_chance: offer deadline
+	BaseURL   string
+}
+
+// Mail is one message to queue.
+type Mail struct {
+	Key  string // dedupe key: queuing the same key again is a no‑op
+	Kind string
+	Data Data // Data.UserID is the recipient
+}
+
+type templates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+type Mailer struct {
+	db   *sql.DB
+	cfg  Config
+	tmpl map[string]templates
+}
+
+// New parses the templates; it fails only on a broken template.
+func New(db *sql.DB, cfg Config) (*Mailer, error) {
+	cfg.defaults()
+	m := &Mailer{db: db, cfg: cfg, tmpl: make(map[string]templates, len(kinds))}
+	for _, k := range kinds {
+		txt, err := texttemplate.New(k+".txt").Funcs(funcs).ParseFS(templateFS, "templates/"+k+".txt")
+		if err != nil {
+			return nil, err
+		}
+		html, err := htmltemplate.New("layout").Funcs(funcs).ParseFS(templateFS, "templates/layout.html", "templates/"+k+".html")
+		if err != nil {
+			return nil, err
+		}
+		m.tmpl[k] = templates{text: txt, html: html}
+	}
+	return m, nil
+}
+
+// Render produces the subject and both bodies of a mail.
+func (m *Mailer) Render(kind string, d Data) (subject, text, html string, err error) {
+	t, ok := m.tmpl[kind]
+	if !ok {
+		return "", "", "", ErrUnknownKind
+	}
+	var buf bytes.Buffer
+	if err = t.text.ExecuteTemplate(&buf, "subject", d); err != nil {
+		return
+	}
+	subject = strings.Join(strings.Fields(buf.String()), " ") // one header line
+	buf.Reset()
+	if err = t.text.Execute(&buf, d); err != nil {
+		return
+	}
+	text = buf.String()
+	buf.Reset()
+	if err = t.html.ExecuteTemplate(&buf, "layout", d); err != nil {
+		return
+	}
+	return subject, text, buf.String(), nil
+}
+
+// Enqueue renders the mail and queues it for sending.
+func (m *Mailer) Enqueue(ctx context.Context, mail Mail) error {
+	d := mail.Data
+	d.BaseURL = m.cfg.BaseURL
+	if d.Item == "" || d.EndsAt.IsZero() {
+		var item string
+		var endsAt time.Time
+		err := m.db.QueryRowContext(ctx,
+			`SELECT item, ends_at FROM auctions WHERE id = $1`, d.AuctionID).Scan(&item, &endsAt)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if d.Item == "" {
+			d.Item = item
+		}
+		if d.EndsAt.IsZero() {
+			d.EndsAt = endsAt.UTC()
+		}
+	}
+
+	subject, text, html, err := m.Render(mail.Kind, d)
+	if err != nil {
+		return err
+	}
+	const q = `
+	  INSERT INTO notifications (dedupe_key, user_id, kind, auction_id, recipient,
+	                             subject, body_text, body_html)
+	       VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	  ON CONFLICT (dedupe_key) DO NOTHING`
+	_, err = m.db.ExecContext(ctx, q, mail.Key, d.UserID, mail.Kind, d.AuctionID,
+		fmt.Sprintf(m.cfg.AddressFormat, d.UserID), subject, text, html)
+	return err
+}
@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/outbox"
+	"context"
+	"encoding/json"
+)
+
+// RegisterOutboxHandlers makes the relay queue the result mails of a
+// finished auction: the winner's and the seller's (sold or unsold).
+func RegisterOutboxHandlers(r *outbox.Relay, m *Mailer) {
+	r.Handle(outbox.KindAuctionFinished, func(ctx context.Context, msg outbox.Message) error {
+		var stop events.Stop
+		if err := json.Unmarshal(msg.Payload, &stop); err != nil {
+			return err
+		}
+		id := msg.AggregateID
+		if stop.HighBidder == "" || stop.HighBid <= 0 {
+			return m.Enqueue(ctx, Mail{
+				Key:  id + ":" + KindSellerUnsold,
+				Kind: KindSellerUnsold,
+				Data: Data{UserID: stop.SellerID, AuctionID: id},
+			})
+		}
+		if err := m.Enqueue(ctx, Mail{
+			Key:  id + ":" + KindWinner,
+			Kind: KindWinner,
+			Data: Data{UserID: stop.HighBidder, AuctionID: id, Amount: stop.HighBid},
+		}); err != nil {
+			return err
+		}
+		return m.Enqueue(ctx, Mail{
+			Key:  id + ":" + KindSellerSold,
+			Kind: KindSellerSold,
+			Data: Data{UserID: stop.SellerID, AuctionID: id, Amount: stop.HighBid, Bidder: stop.HighBidder},
+		})
+	})
+}
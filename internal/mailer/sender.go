@@ -0,0 +1,209 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// claimLease keeps a claimed mail invisible to other senders while it is
+// being sent; a sender that dies mid‑send releases it this way.
+const claimLease = time.Minute
+
+// sendTimeout bounds one SMTP conversation, dial to QUIT, so a hanging
+// server cannot hold a mail past its lease and get it sent twice.
+const sendTimeout = 20 * time.Second
+
+type queued struct {
+	id        int64
+	recipient string
+	subject   string
+	text      string
+	html      string
+	attempts  int
+}
+
+// Run sends due mails until ctx is done. Several instances may run it: rows
+// are claimed with SKIP LOCKED.
+func (m *Mailer) Run(ctx context.Context) {
+	tk := time.NewTicker(m.cfg.PollInterval)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+		}
+
+		leaseEnd := time.Now().Add(claimLease)
+		batch, err := m.claim(ctx)
+		if err != nil {
+			zap.L().Warn("mailer.claim", zap.Error(err))
+			continue
+		}
+		for _, q := range batch {
+			if time.Until(leaseEnd) < sendTimeout {
+				break // the rest is claimed again once the lease runs out
+			}
+			m.record(ctx, q, m.send(q))
+		}
+	}
+}
+
+func (m *Mailer) claim(ctx context.Context) ([]queued, error) {
+	const q = `
+	  UPDATE notifications
+	     SET next_attempt_at = now() + make_interval(secs => $2)
+	   WHERE id IN (SELECT id FROM notifications
+	                 WHERE status = 'pending' AND next_attempt_at <= now()
+	              ORDER BY next_attempt_at, id
+	                 LIMIT $1
+	                   FOR UPDATE SKIP LOCKED)
+	RETURNING id, recipient, subject, body_text, body_html, attempts`
+	rows, err := m.db.QueryContext(ctx, q, m.cfg.BatchSize, claimLease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []queued
+	for rows.Next() {
+		var q queued
+		if err := rows.Scan(&q.id, &q.recipient, &q.subject, &q.text, &q.html, &q.attempts); err != nil {
+			return nil, err
+		}
+		out = append(out, q)
+	}
+	return out, rows.Err()
+}
+
+func (m *Mailer) send(q queued) error {
+	msg, err := buildMessage(m.cfg.From, q)
+	if err != nil {
+		return err
+	}
+	host, _, _ := net.SplitHostPort(m.cfg.Addr)
+
+	// smtp.SendMail has no timeout; this is the same conversation on a
+	// connection with a deadline
+	conn, err := net.DialTimeout("tcp", m.cfg.Addr, sendTimeout)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(sendTimeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if m.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(m.cfg.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(q.recipient); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// buildMessage assembles a multipart/alternative (text + HTML) message.
+func buildMessage(from string, q queued) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct{ typ, content string }{
+		{"text/plain; charset=utf-8", q.text},
+		{"text/html; charset=utf-8", q.html},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.typ},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", q.recipient)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", q.subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <notification-%d@auctionbidgo>\r\n", q.id)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// record stores the outcome: sent, retry later, or failed for good.
+func (m *Mailer) record(ctx context.Context, q queued, sendErr error) {
+	attempts := q.attempts + 1
+	var err error
+	switch {
+	case sendErr == nil:
+		_, err = m.db.ExecContext(ctx, `
+		  UPDATE notifications
+		     SET status = 'sent', attempts = $2, last_error = NULL, sent_at = now()
+		   WHERE id = $1`, q.id, attempts)
+	case attempts < m.cfg.MaxAttempts:
+		_, err = m.db.ExecContext(ctx, `
+		  UPDATE notifications
+		     SET attempts = $2, last_error = $3,
+		         next_attempt_at = now() + make_interval(secs => $4)
+		   WHERE id = $1`, q.id, attempts, sendErr.Error(), m.cfg.Backoff(attempts).Seconds())
+	default:
+		zap.L().Warn("mailer.failed",
+			zap.Int64("notification", q.id), zap.String("to", q.recipient), zap.Error(sendErr))
+		_, err = m.db.ExecContext(ctx, `
+		  UPDATE notifications
+		     SET status = 'failed', attempts = $2, last_error = $3
+		   WHERE id = $1`, q.id, attempts, sendErr.Error())
+	}
+	if err != nil {
+		zap.L().Warn("mailer.record", zap.Int64("notification", q.id), zap.Error(err))
+	}
+}
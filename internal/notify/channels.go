@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/mailer"
+	"auctionbidgo/internal/services/webhook"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PushChannel is the Redis Pub/Sub channel the WS servers listen on; every
+// instance pushes the notifications of the users connected to it.
+const PushChannel = "user_notifications"
+
+// ── in‑app (WS) ─────────────────────────────────────────────────────────────
+
+type wsChannel struct{ rdc *redis.Client }
+
+// NewWSChannel pushes to the user's open WS connections on any instance.
+func NewWSChannel(rdc *redis.Client) Channel { return wsChannel{rdc: rdc} }
+
+func (wsChannel) Name() string { return "ws" }
+
+func (c wsChannel) Send(ctx context.Context, n Notification) error {
+	b, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	return c.rdc.Publish(ctx, PushChannel, b).Err()
+}
+
+// ── email ───────────────────────────────────────────────────────────────────
+
+type emailChannel struct{ m *mailer.Mailer }
+
+// NewEmailChannel queues a templated mail per notification; the mailer
+// sends and retries it.
+func NewEmailChannel(m *mailer.Mailer) Channel { return emailChannel{m: m} }
+
+func (emailChannel) Name() string { return "email" }
+
+func (c emailChannel) Send(ctx context.Context, n Notification) error {
+	if n.Kind == KindOffer || n.Kind == KindRegistration {
+		return nil // negotiation and registration are in‑app only (ws, webhooks)
+	}
+	d := mailer.Data{UserID: n.UserID, AuctionID: n.AuctionID}
+	var kind string
+	switch v := n.Data.(type) {
+	case events.Start:
+		kind, d.EndsAt = mailer.KindAuctionStarting, time.Unix(v.EndsAt, 0).UTC()
+	case events.Bid:
+		kind, d.Amount = mailer.KindOutbid, v.Amount
+	case EndingSoon:
+		kind, d.EndsAt, d.Left = mailer.KindEndingSoon, v.EndsAt, v.EndsAt.Sub(n.CreatedAt)
+	case SecondChance:
+		kind, d.Amount, d.OfferID, d.ExpiresAt = mailer.KindSecondChance, v.Amount, v.OfferID, v.ExpiresAt
+	default:
+		return fmt.Errorf("no mail template for %s", n.Kind)
+	}
+	return c.m.Enqueue(ctx, mailer.Mail{Key: n.Key, Kind: kind, Data: d})
+}
+
+// ── webhooks ────────────────────────────────────────────────────────────────
+
+type webhookChannel struct{ svc webhook.IWebhookService }
+
+// NewWebhookChannel queues a signed "user.notification" delivery for the
+// subscriptions that want it.
+func NewWebhookChannel(svc webhook.IWebhookService) Channel { return webhookChannel{svc: svc} }
+
+func (webhookChannel) Name() string { return "webhook" }
+
+func (c webhookChannel) Send(ctx context.Context, n Notification) error {
+	return c.svc.Enqueue(ctx, webhook.Event{
+		Key:        "notif:" + n.Key,
+		Type:       webhook.EventUserNotification,
+		AuctionID:  n.AuctionID,
+		OccurredAt: n.CreatedAt,
+		Data:       n,
+	})
+}
@@ -0,0 +1,201 @@
+package notify
+
+import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/services/watchlist"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	channelPattern = "auc:*:events"
+	activeSet      = "aucs:active"
+	// endingKeyPrefix marks an (auction, threshold) as handled so the scan
+	// looks up the watchers only once across all instances.
+	endingKeyPrefix = "notif_ending:"
+)
+
+// Config tunes the engine.
+type Config struct {
+	EndingSoon   []time.Duration // thresholds, e.g. 1h, 10m, 1m
+	ScanInterval time.Duration   // how often running auctions are checked
+}
+
+// Engine watches auction events and the clock and produces notifications.
+type Engine struct {
+	rdc       *redis.Client
+	watchlist watchlist.IWatchlistService
+	out       *Dispatcher
+	cfg       Config
+}
+
+func NewEngine(rdc *redis.Client, wl watchlist.IWatchlistService, out *Dispatcher, cfg Config) *Engine {
+	if cfg.ScanInterval <= 0 {
+		cfg.ScanInterval = 5 * time.Second
+	}
+	cfg.EndingSoon = slices.Clone(cfg.EndingSoon)
+	slices.Sort(cfg.EndingSoon)
+	return &Engine{rdc: rdc, watchlist: wl, out: out, cfg: cfg}
+}
+
+// Run tails the auction event channels and scans for ending‑soon auctions
+// until ctx is done. Every instance may run it; see Dispatcher.
+func (e *Engine) Run(ctx context.Context) {
+	go e.scanEndingSoon(ctx)
+
+	ps := e.rdc.PSubscribe(ctx, channelPattern)
+	defer ps.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-ps.Channel():
+			if !ok {
+				return
+			}
+			auctionID := strings.TrimSuffix(strings.TrimPrefix(m.Channel, "auc:"), ":events")
+			ev, err := events.Decode([]byte(m.Payload))
+			if err != nil {
+				continue
+			}
+			e.onEvent(ctx, auctionID, ev)
+		}
+	}
+}
+
+func (e *Engine) onEvent(ctx context.Context, auctionID string, ev events.Event) {
+	key := auctionID + ":" + strconv.FormatInt(ev.Sequence(), 10)
+
+	switch v := ev.(type) {
+	case events.Start:
+		e.toWatchers(ctx, auctionID, func(user string) Notification {
+			return Notification{
+				Key:       key + ":" + user,
+				UserID:    user,
+				Kind:      KindAuctionStarting,
+				AuctionID: auctionID,
+				Message:   fmt.Sprintf("Auction %s has started; it ends at %s.", auctionID, time.Unix(v.EndsAt, 0).UTC().Format(time.RFC1123)),
+				Data:      v,
+				CreatedAt: time.Unix(v.StartsAt, 0).UTC(),
+			}
+		})
+
+	case events.Bid:
+		if v.PreviousBidder == "" || v.PreviousBidder == v.Bidder {
+			return
+		}
+		e.out.Dispatch(ctx, Notification{
+			Key:       key + ":outbid",
+			UserID:    v.PreviousBidder,
+			Kind:      KindOutbid,
+			AuctionID: auctionID,
+			Message:   fmt.Sprintf("You were outbid on auction %s: the high bid is now %s.", auctionID, strconv.FormatFloat(v.Amount, 'f', -1, 64)),
+			Data:      v,
+			CreatedAt: time.Unix(v.At, 0).UTC(),
+		})
+	}
+}
+
+// toWatchers dispatches one notification per watcher of the auction.
+func (e *Engine) toWatchers(ctx context.Context, auctionID string, mk func(user string) Notification) {
+	users, err := e.watchlist.Watchers(ctx, auctionID)
+	if err != nil {
+		zap.L().Warn("notify.watchers", zap.String("auction", auctionID), zap.Error(err))
+		return
+	}
+	for _, u := range users {
+		e.out.Dispatch(ctx, mk(u))
+	}
+}
+
+// scanEndingSoon checks the running auctions' remaining time against the
+// thresholds. Only the smallest threshold crossed fires, so an auction that
+// is started with 5 minutes left sends "10m" once and later "1m", not "1h".
+func (e *Engine) scanEndingSoon(ctx context.Context) {
+	if len(e.cfg.EndingSoon) == 0 {
+		return
+	}
+	tk := time.NewTicker(e.cfg.ScanInterval)
+	defer tk.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+		}
+		if err := e.scanOnce(ctx); err != nil {
+			zap.L().Warn("notify.scan", zap.Error(err))
+		}
+	}
+}
+
+func (e *Engine) scanOnce(ctx context.Context) error {
+	keys, err := e.rdc.SMembers(ctx, activeSet).Result()
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+	now, err := e.rdc.Time(ctx).Result() // same clock the auctions close on
+	if err != nil {
+		return err
+	}
+
+	pipe := e.rdc.Pipeline()
+	cmds := make([]*redis.SliceCmd, len(keys))
+	for i, k := range keys {
+		cmds[i] = pipe.HMGet(ctx, k, "ea", "live")
+	}
+	_, _ = pipe.Exec(ctx) // missing hashes just yield nil fields
+
+	for i, k := range keys {
+		var h struct {
+			EndsAt int64 `redis:"ea"`
+			Live   bool  `redis:"live"` // closed by the auctioneer, not at ea
+		}
+		if err := cmds[i].Scan(&h); err != nil || h.EndsAt == 0 || h.Live {
+			continue
+		}
+		ea := h.EndsAt
+		left := time.Unix(ea, 0).Sub(now)
+		if left <= 0 {
+			continue
+		}
+		idx := slices.IndexFunc(e.cfg.EndingSoon, func(t time.Duration) bool { return left <= t })
+		if idx < 0 {
+			continue
+		}
+		threshold := e.cfg.EndingSoon[idx]
+		auctionID := strings.TrimPrefix(k, "auc:")
+
+		first, err := e.rdc.SetNX(ctx, endingKeyPrefix+auctionID+":"+threshold.String(), 1, sentTTL).Result()
+		if err != nil || !first {
+			continue
+		}
+		endsAt := time.Unix(ea, 0).UTC()
+		e.toWatchers(ctx, auctionID, func(user string) Notification {
+			return Notification{
+				Key:       auctionID + ":ending:" + threshold.String() + ":" + user,
+				UserID:    user,
+				Kind:      KindEndingSoon,
+				AuctionID: auctionID,
+				Message:   fmt.Sprintf("Auction %s ends in %s.", auctionID, left.Round(time.Second)),
+				Data:      EndingSoon{Threshold: threshold.String(), EndsAt: endsAt},
+				CreatedAt: now.UTC(),
+			}
+		})
+	}
+	return nil
+}
+
+// EndingSoon is the Data of an ending_soon notification.
+type EndingSoon struct {
+	Threshold string    `json:"threshold"` // e.g. "10m0s"
+	EndsAt    time.Time `json:"ends_at"`
+}
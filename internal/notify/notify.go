@@ -0,0 +1,91 @@
+// Package notify turns auction events into per‑user notifications (watched
+// auction started or ending soon, outbid) and hands them to pluggable
+// delivery channels: WS push, email and webhooks.
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Notification kinds.
+const (
+	KindAuctionStarting = "auction_starting" // a watched auction started
+	KindOutbid          = "outbid"           // the user's high bid was beaten
+	KindEndingSoon      = "ending_soon"      // a watched auction crossed a threshold
+	KindSecondChance    = "second_chance"    // the unpaid item is offered to the user
+	KindOffer           = "offer"            // a make‑an‑offer negotiation moved
+	KindRegistration    = "registration"     // a request to bid was made or decided
+)
+
+const (
+	sentKeyPrefix = "notif_sent:" // SETNX guard, one send per notification key
+	sentTTL       = 24 * time.Hour
+)
+
+// Notification is one message for one user.
+type Notification struct {
+	Key       string    `json:"id"` // stable; the same key is never sent twice
+	UserID    string    `json:"user_id"`
+	Kind      string    `json:"kind" enums:"auction_starting,outbid,ending_soon,second_chance,offer,registration"`
+	AuctionID string    `json:"auction_id"`
+	Message   string    `json:"message"`        // human‑readable one‑liner
+	Data      any       `json:"data,omitempty"` // kind‑specific details
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Channel delivers notifications one way (WS, email, …). Send should return
+// quickly; channels that need retries queue the message themselves.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, n Notification) error
+}
+
+// Dispatcher sends each notification once through every channel. Several
+// instances may see the same auction event; a Redis SETNX on the key makes
+// exactly one of them send it. The key is a claim until a channel accepts
+// the notification: when every channel fails it is deleted again, so the
+// next time the event is seen it is retried instead of lost.
+type Dispatcher struct {
+	rdc      *redis.Client
+	channels []Channel
+}
+
+func NewDispatcher(rdc *redis.Client, channels ...Channel) *Dispatcher {
+	return &Dispatcher{rdc: rdc, channels: channels}
+}
+
+func (d *Dispatcher) Dispatch(ctx context.Context, n Notification) {
+	first, err := d.rdc.SetNX(ctx, sentKeyPrefix+n.Key, 1, sentTTL).Result()
+	if err != nil {
+		zap.L().Warn("notify.dedupe", zap.String("key", n.Key), zap.Error(err))
+		return
+	}
+	if !first {
+		return
+	}
+	sent := false
+	for _, ch := range d.channels {
+		if err := ch.Send(ctx, n); err != nil {
+			zap.L().Warn("notify.send",
+				zap.String("channel", ch.Name()), zap.String("key", n.Key), zap.Error(err))
+			continue
+		}
+		sent = true
+	}
+	if !sent && len(d.channels) > 0 {
+		if err := d.rdc.Del(context.WithoutCancel(ctx), sentKeyPrefix+n.Key).Err(); err != nil {
+			zap.L().Warn("notify.release", zap.String("key", n.Key), zap.Error(err))
+		}
+	}
+}
+
+// SecondChance is the Data of a second_chance notification.
+type SecondChance struct {
+	OfferID   int64     `json:"offer_id"`
+	Amount    float64   `json:"amount"` // the user's own last bid
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,206 @@
+// Package outbox implements the transactional outbox: services record the
+// side effects of a state change (Redis publish, webhooks, …) as rows written
+// in the same Postgres transaction, and the Relay carries them out at least
+// once, even if the process dies right after the commit.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Kinds written by the auction, payment and second‑chance services.
+const (
+	KindAuctionCreated   = "auction.created"
+	KindAuctionFinished  = "auction.finished"
+	KindAuctionDeleted   = "auction.deleted"
+	KindAuctionPaid      = "auction.paid"
+	KindAuctionUnpaid    = "auction.unpaid"    // payment deadline missed
+	KindAuctionResettled = "auction.resettled" // sold to a runner‑up instead
+)
+
+const (
+	pollInterval = time.Second
+	batchSize    = 50
+	backoffBase  = time.Second
+	backoffMax   = 5 * time.Minute
+	retention    = 7 * 24 * time.Hour // sent rows kept for inspection
+)
+
+// Message is one outbox row handed to the handlers.
+type Message struct {
+	ID          int64
+	Kind        string
+	AggregateID string
+	Payload     json.RawMessage
+	CreatedAt   time.Time
+}
+
+// Handler performs one side effect. It must be idempotent: a message is
+// retried until every handler of its kind succeeded in the same pass.
+type Handler func(ctx context.Context, m Message) error
+
+// Write records a message inside the caller's transaction.
+func Write(ctx context.Context, tx *sql.Tx, kind, aggregateID string, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO outbox (kind, aggregate_id, payload) VALUES ($1, $2, $3)`,
+		kind, aggregateID, string(b))
+	return err
+}
+
+// Relay polls unsent rows and runs the handlers registered for their kind.
+// Several instances may run it; rows are claimed with SKIP LOCKED and the
+// rows of one aggregate are processed strictly in order.
+type Relay struct {
+	db       *sql.DB
+	handlers map[string][]Handler
+	wake     chan struct{}
+}
+
+func NewRelay(db *sql.DB) *Relay {
+	return &Relay{
+		db:       db,
+		handlers: make(map[string][]Handler),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Handle adds a handler for kind. Register before Run.
+func (r *Relay) Handle(kind string, h Handler) {
+	r.handlers[kind] = append(r.handlers[kind], h)
+}
+
+// Wake asks the relay to poll now instead of on the next tick; writers call
+// it after their commit to keep the latency low. Safe on a nil Relay.
+func (r *Relay) Wake() {
+	if r == nil {
+		return
+	}
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run relays messages until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	tk := time.NewTicker(pollInterval)
+	defer tk.Stop()
+	purge := time.NewTicker(time.Hour)
+	defer purge.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-purge.C:
+			_, _ = r.db.ExecContext(ctx,
+				`DELETE FROM outbox WHERE sent_at < now() - make_interval(secs => $1)`,
+				retention.Seconds())
+			continue
+		case <-tk.C:
+		case <-r.wake:
+		}
+
+		for {
+			n, err := r.relayBatch(ctx)
+			if err != nil {
+				zap.L().Warn("outbox.relay", zap.Error(err))
+			}
+			if err != nil || n < batchSize {
+				break
+			}
+		}
+	}
+}
+
+// relayBatch claims up to batchSize due rows, runs their handlers and
+// records the outcome, all in one transaction. It returns the rows handled.
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	const q = `
+	  SELECT id, kind, aggregate_id, payload, created_at, attempts
+	    FROM outbox o
+	   WHERE sent_at IS NULL
+	     AND next_attempt_at <= now()
+	     AND NOT EXISTS (SELECT 1 FROM outbox p
+	                      WHERE p.aggregate_id = o.aggregate_id
+	                        AND p.sent_at IS NULL
+	                        AND p.id < o.id)
+	ORDER BY id
+	   LIMIT $1
+	     FOR UPDATE SKIP LOCKED`
+	rows, err := tx.QueryContext(ctx, q, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	type claimed struct {
+		Message
+		attempts int
+	}
+	var batch []claimed
+	for rows.Next() {
+		var c claimed
+		var payload []byte
+		if err := rows.Scan(&c.ID, &c.Kind, &c.AggregateID, &payload, &c.CreatedAt, &c.attempts); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		c.Payload = payload
+		batch = append(batch, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, c := range batch {
+		if herr := r.dispatch(ctx, c.Message); herr != nil {
+			zap.L().Warn("outbox.handler",
+				zap.Int64("id", c.ID), zap.String("kind", c.Kind), zap.Error(herr))
+			_, err = tx.ExecContext(ctx, `
+			  UPDATE outbox
+			     SET attempts = attempts + 1, last_error = $2,
+			         next_attempt_at = now() + make_interval(secs => $3)
+			   WHERE id = $1`,
+				c.ID, herr.Error(), backoff(c.attempts+1).Seconds())
+		} else {
+			_, err = tx.ExecContext(ctx,
+				`UPDATE outbox SET sent_at = now(), last_error = NULL WHERE id = $1`, c.ID)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(batch), tx.Commit()
+}
+
+func (r *Relay) dispatch(ctx context.Context, m Message) error {
+	for _, h := range r.handlers[m.Kind] {
+		if err := h(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func backoff(attempt int) time.Duration {
+	d := backoffBase
+	for i := 1; i < attempt && d < backoffMax; i++ {
+		d *= 2
+	}
+	return min(d, backoffMax)
+}
@@ -0,0 +1,105 @@
+package fakepay
+
+import (
+	"auctionbidgo/internal/payments"
+	"auctionbidgo/internal/services/webhook"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Provider is the payments.PaymentProvider for a Simulator.
+type Provider struct {
+	baseURL       string
+	apiKey        string
+	webhookSecret string
+	client        *http.Client
+}
+
+var _ payments.PaymentProvider = (*Provider)(nil)
+
+func NewProvider(baseURL, apiKey, webhookSecret string) *Provider {
+	return &Provider{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		apiKey:        apiKey,
+		webhookSecret: webhookSecret,
+		client:        &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (p *Provider) Name() string { return "fakepay" }
+
+func (p *Provider) CreateIntent(ctx context.Context, req payments.IntentRequest) (*payments.Intent, error) {
+	var in payments.Intent
+	err := p.call(ctx, http.MethodPost, "/v1/intents", map[string]any{
+		"reference": req.Reference,
+		"amount":    req.Amount,
+		"currency":  req.Currency,
+	}, &in)
+	return &in, err
+}
+
+func (p *Provider) Capture(ctx context.Context, intentID string) (*payments.Intent, error) {
+	var in payments.Intent
+	err := p.call(ctx, http.MethodPost, "/v1/intents/"+intentID+"/capture", nil, &in)
+	return &in, err
+}
+
+func (p *Provider) Refund(ctx context.Context, intentID string, amount float64) (*payments.Refund, error) {
+	var rf payments.Refund
+	err := p.call(ctx, http.MethodPost, "/v1/intents/"+intentID+"/refunds",
+		map[string]any{"amount": amount}, &rf)
+	return &rf, err
+}
+
+// VerifyWebhook checks the Fakepay-Signature header (5 minute tolerance).
+// Without a secret nothing verifies: an empty key would be forgeable.
+func (p *Provider) VerifyWebhook(header http.Header, body []byte) (*payments.WebhookEvent, error) {
+	if p.webhookSecret == "" {
+		return nil, payments.ErrBadSignature
+	}
+	if err := webhook.Verify(p.webhookSecret, header.Get(HeaderSignature), body, 5*time.Minute); err != nil {
+		return nil, payments.ErrBadSignature
+	}
+	var ev payments.WebhookEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+func (p *Provider) call(ctx context.Context, method, path string, in, out any) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var e apiError
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		if resp.StatusCode == http.StatusConflict {
+			return fmt.Errorf("%w: %s", payments.ErrDeclined, e.Error)
+		}
+		return fmt.Errorf("fakepay %s %s: %d %s", method, path, resp.StatusCode, e.Error)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,64 @@
+package fakepay
+
+import (
+	"auctionbidgo/internal/payments"
+	"auctionbidgo/internal/services/webhook"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func signedHeader(secret string, at time.Time, body []byte) http.Header {
+	h := http.Header{}
+	h.Set(HeaderSignature, webhook.Sign(secret, at, body))
+	return h
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	body, _ := json.Marshal(payments.WebhookEvent{
+		ID:   "evt_1",
+		Type: payments.EventIntentSucceeded,
+		Intent: payments.Intent{
+			ID: "pi_1", Reference: "INV-1", Amount: 110, Currency: "EUR", Status: payments.IntentSucceeded,
+		},
+	})
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		secret string // the provider's
+		header http.Header
+		body   []byte
+		ok     bool
+	}{
+		{"valid", "whsec_test", signedHeader("whsec_test", now, body), body, true},
+		{"wrong secret", "whsec_test", signedHeader("whsec_other", now, body), body, false},
+		{"tampered body", "whsec_test", signedHeader("whsec_test", now, body),
+			[]byte(`{"id":"evt_1","type":"intent.succeeded","intent":{"id":"pi_2"}}`), false},
+		{"stale timestamp", "whsec_test", signedHeader("whsec_test", now.Add(-6*time.Minute), body), body, false},
+		{"future timestamp", "whsec_test", signedHeader("whsec_test", now.Add(6*time.Minute), body), body, false},
+		{"missing header", "whsec_test", http.Header{}, body, false},
+		{"malformed header", "whsec_test", http.Header{HeaderSignature: {"v1=deadbeef"}}, body, false},
+		{"no secret configured", "", signedHeader("", now, body), body, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider("http://fakepay.invalid", "", tt.secret)
+			ev, err := p.VerifyWebhook(tt.header, tt.body)
+			if !tt.ok {
+				if !errors.Is(err, payments.ErrBadSignature) {
+					t.Fatalf("err = %v, want ErrBadSignature", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if ev.Type != payments.EventIntentSucceeded || ev.Intent.ID != "pi_1" || ev.Intent.Amount != 110 {
+				t.Fatalf("event = %+v", ev)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+// Package fakepay is a local stand‑in for a card payment gateway: Simulator
+// is a tiny HTTP server with an intent API and a checkout page, and Provider
+// is the payments.PaymentProvider that talks to it.
+package fakepay
+
+import (
+	"auctionbidgo/internal/payments"
+	"auctionbidgo/internal/services/webhook"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// HeaderSignature carries the webhook signature, "t=<unix>,v1=<hex>"
+// (same scheme as our own outbound webhooks).
+const HeaderSignature = "Fakepay-Signature"
+
+// Simulator keeps intents in memory. Buyers pay on GET /checkout/{id};
+// every state change is POSTed, signed, to WebhookURL.
+type Simulator struct {
+	BaseURL       string // public URL of the simulator, for checkout links
+	APIKey        string // expected bearer token on /v1 calls; empty = open
+	WebhookURL    string
+	WebhookSecret string
+	Client        *http.Client
+
+	mu      sync.Mutex
+	intents map[string]*payments.Intent
+}
+
+func NewSimulator(baseURL, apiKey, webhookURL, webhookSecret string) *Simulator {
+	return &Simulator{
+		BaseURL:       strings.TrimSuffix(baseURL, "/"),
+		APIKey:        apiKey,
+		WebhookURL:    webhookURL,
+		WebhookSecret: webhookSecret,
+		Client:        &http.Client{Timeout: 5 * time.Second},
+		intents:       make(map[string]*payments.Intent),
+	}
+}
+
+func newID(prefix string) string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
+
+// Handler serves the API and the checkout pages.
+func (s *Simulator) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/intents", s.auth(s.createIntent))
+	mux.HandleFunc("GET /v1/intents/{id}", s.auth(s.getIntent))
+	mux.HandleFunc("POST /v1/intents/{id}/capture", s.auth(s.capture))
+	mux.HandleFunc("POST /v1/intents/{id}/refunds", s.auth(s.refund))
+	mux.HandleFunc("GET /checkout/{id}", s.checkout)
+	mux.HandleFunc("POST /checkout/{id}/authorize", s.decide(payments.IntentRequiresCapture, payments.EventIntentAuthorized))
+	mux.HandleFunc("POST /checkout/{id}/decline", s.decide(payments.IntentFailed, payments.EventIntentFailed))
+	return mux
+}
+
+// ListenAndServe runs the simulator until ctx is done.
+func (s *Simulator) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{Addr: addr, Handler: s.Handler()}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (s *Simulator) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.APIKey != "" && r.Header.Get("Authorization") != "Bearer "+s.APIKey {
+			writeJSON(w, http.StatusUnauthorized, apiError{"invalid api key"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// lookup returns a copy of the intent and whether it exists.
+func (s *Simulator) lookup(id string) (payments.Intent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	in, ok := s.intents[id]
+	if !ok {
+		return payments.Intent{}, false
+	}
+	return *in, true
+}
+
+func (s *Simulator) createIntent(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Reference string  `json:"reference"`
+		Amount    float64 `json:"amount"`
+		Currency  string  `json:"currency"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Amount <= 0 {
+		writeJSON(w, http.StatusBadRequest, apiError{"amount must be > 0"})
+		return
+	}
+	in := &payments.Intent{
+		ID:        newID("pi_"),
+		Reference: req.Reference,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+		Status:    payments.IntentRequiresPayment,
+	}
+	in.CheckoutURL = s.BaseURL + "/checkout/" + in.ID
+	s.mu.Lock()
+	s.intents[in.ID] = in
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, in)
+}
+
+func (s *Simulator) getIntent(w http.ResponseWriter, r *http.Request) {
+	in, ok := s.lookup(r.PathValue("id"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, apiError{"no such intent"})
+		return
+	}
+	writeJSON(w, http.StatusOK, in)
+}
+
+func (s *Simulator) capture(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	in, ok := s.intents[r.PathValue("id")]
+	if !ok {
+		s.mu.Unlock()
+		writeJSON(w, http.StatusNotFound, apiError{"no such intent"})
+		return
+	}
+	switch in.Status {
+	case payments.IntentSucceeded: // idempotent
+	case payments.IntentRequiresCapture:
+		in.Status = payments.IntentSucceeded
+	default:
+		s.mu.Unlock()
+		writeJSON(w, http.StatusConflict, apiError{"intent is " + in.Status})
+		return
+	}
+	out := *in
+	s.mu.Unlock()
+
+	s.notify(payments.EventIntentSucceeded, out)
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Simulator) refund(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Amount float64 `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Amount <= 0 {
+		writeJSON(w, http.StatusBadRequest, apiError{"amount must be > 0"})
+		return
+	}
+	s.mu.Lock()
+	in, ok := s.intents[r.PathValue("id")]
+	if !ok {
+		s.mu.Unlock()
+		writeJSON(w, http.StatusNotFound, apiError{"no such intent"})
+		return
+	}
+	if in.Status != payments.IntentSucceeded || in.Refunded+req.Amount > in.Amount+1e-9 {
+		s.mu.Unlock()
+		writeJSON(w, http.StatusConflict, apiError{"nothing left to refund"})
+		return
+	}
+	in.Refunded += req.Amount
+	if in.Refunded >= in.Amount-1e-9 {
+		in.Status = payments.IntentRefunded
+	}
+	out := *in
+	s.mu.Unlock()
+
+	rf := payments.Refund{ID: newID("re_"), IntentID: out.ID, Amount: req.Amount}
+	s.notify(payments.EventRefundSucceeded, out)
+	writeJSON(w, http.StatusCreated, rf)
+}
+
+var checkoutPage = template.Must(template.New("checkout").Parse(`<!DOCTYPE html>
+<html><body style="font-family: sans-serif; max-width: 420px; margin: 3em auto;">
+<h2>fakepay checkout</h2>
+<p>{{.Reference}} – <b>{{printf "%.2f" .Amount}} {{.Currency}}</b></p>
+<p>Status: <code>{{.Status}}</code></p>
+{{if eq .Status "requires_payment"}}
+<form method="post" action="/checkout/{{.ID}}/authorize"><button>Pay</button></form>
+<form method="post" action="/checkout/{{.ID}}/decline"><button>Decline</button></form>
+{{end}}
+</body></html>`))
+
+func (s *Simulator) checkout(w http.ResponseWriter, r *http.Request) {
+	in, ok := s.lookup(r.PathValue("id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = checkoutPage.Execute(w, in)
+}
+
+// decide is the buyer's button on the checkout page.
+func (s *Simulator) decide(status, event string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		s.mu.Lock()
+		in, ok := s.intents[id]
+		if !ok {
+			s.mu.Unlock()
+			http.NotFound(w, r)
+			return
+		}
+		changed := in.Status == payments.IntentRequiresPayment
+		if changed {
+			in.Status = status
+		}
+		out := *in
+		s.mu.Unlock()
+
+		if changed {
+			s.notify(event, out)
+		}
+		http.Redirect(w, r, "/checkout/"+id, http.StatusSeeOther)
+	}
+}
+
+// notify POSTs a signed webhook; failures are only logged (the merchant can
+// always poll GET /v1/intents/{id}).
+func (s *Simulator) notify(typ string, in payments.Intent) {
+	if s.WebhookURL == "" {
+		return
+	}
+	body, _ := json.Marshal(payments.WebhookEvent{ID: newID("evt_"), Type: typ, Intent: in})
+	req, err := http.NewRequest(http.MethodPost, s.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderSignature, webhook.Sign(s.WebhookSecret, time.Now(), body))
+	go func() {
+		resp, err := s.Client.Do(req)
+		if err != nil {
+			zap.L().Warn("fakepay.webhook", zap.String("type", typ), zap.Error(err))
+			return
+		}
+		resp.Body.Close()
+	}()
+}
@@ -0,0 +1,171 @@
+package fakepay
+
+import (
+	"auctionbidgo/internal/payments"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const (
+	testAPIKey = "sk_test"
+	testSecret = "whsec_test"
+)
+
+// newTestSimulator runs a Simulator whose webhooks go to a receiver that
+// verifies them with a Provider and hands the events to the returned channel.
+func newTestSimulator(t *testing.T) (*Simulator, *Provider, <-chan *payments.WebhookEvent) {
+	t.Helper()
+	events := make(chan *payments.WebhookEvent, 16)
+	var verifier *Provider
+	hooks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ev, err := verifier.VerifyWebhook(r.Header, body)
+		if err != nil {
+			t.Errorf("webhook: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		events <- ev
+	}))
+	t.Cleanup(hooks.Close)
+
+	sim := NewSimulator("", testAPIKey, hooks.URL, testSecret)
+	srv := httptest.NewServer(sim.Handler())
+	t.Cleanup(srv.Close)
+	sim.BaseURL = srv.URL
+
+	verifier = NewProvider(srv.URL, testAPIKey, testSecret)
+	return sim, verifier, events
+}
+
+func nextEvent(t *testing.T, events <-chan *payments.WebhookEvent, typ string) *payments.WebhookEvent {
+	t.Helper()
+	select {
+	case ev := <-events:
+		if ev.Type != typ {
+			t.Fatalf("webhook type = %q, want %q", ev.Type, typ)
+		}
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no %q webhook", typ)
+		return nil
+	}
+}
+
+func checkout(t *testing.T, in *payments.Intent, action string) {
+	t.Helper()
+	resp, err := http.Post(in.CheckoutURL+"/"+action, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK { // after the redirect to the checkout page
+		t.Fatalf("%s: status %d", action, resp.StatusCode)
+	}
+}
+
+func TestSimulatorPayCaptureRefund(t *testing.T) {
+	_, p, events := newTestSimulator(t)
+	ctx := context.Background()
+
+	in, err := p.CreateIntent(ctx, payments.IntentRequest{Reference: "INV-1", Amount: 100, Currency: "EUR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.Status != payments.IntentRequiresPayment || in.CheckoutURL == "" {
+		t.Fatalf("intent = %+v", in)
+	}
+
+	// capturing before the buyer paid is declined
+	if _, err := p.Capture(ctx, in.ID); !errors.Is(err, payments.ErrDeclined) {
+		t.Fatalf("early capture err = %v, want ErrDeclined", err)
+	}
+
+	checkout(t, in, "authorize")
+	ev := nextEvent(t, events, payments.EventIntentAuthorized)
+	if ev.Intent.ID != in.ID || ev.Intent.Status != payments.IntentRequiresCapture {
+		t.Fatalf("authorized event = %+v", ev.Intent)
+	}
+
+	got, err := p.Capture(ctx, in.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != payments.IntentSucceeded {
+		t.Fatalf("captured status = %q", got.Status)
+	}
+	nextEvent(t, events, payments.EventIntentSucceeded)
+
+	// capture is idempotent
+	if _, err := p.Capture(ctx, in.ID); err != nil {
+		t.Fatalf("second capture: %v", err)
+	}
+	nextEvent(t, events, payments.EventIntentSucceeded)
+
+	if _, err := p.Refund(ctx, in.ID, 40); err != nil {
+		t.Fatal(err)
+	}
+	ev = nextEvent(t, events, payments.EventRefundSucceeded)
+	if ev.Intent.Status != payments.IntentSucceeded || ev.Intent.Refunded != 40 {
+		t.Fatalf("partial refund event = %+v", ev.Intent)
+	}
+	if _, err := p.Refund(ctx, in.ID, 61); !errors.Is(err, payments.ErrDeclined) {
+		t.Fatalf("over-refund err = %v, want ErrDeclined", err)
+	}
+	if _, err := p.Refund(ctx, in.ID, 60); err != nil {
+		t.Fatal(err)
+	}
+	ev = nextEvent(t, events, payments.EventRefundSucceeded)
+	if ev.Intent.Status != payments.IntentRefunded {
+		t.Fatalf("full refund status = %q", ev.Intent.Status)
+	}
+}
+
+func TestSimulatorDecline(t *testing.T) {
+	_, p, events := newTestSimulator(t)
+	ctx := context.Background()
+
+	in, err := p.CreateIntent(ctx, payments.IntentRequest{Reference: "INV-2", Amount: 50, Currency: "EUR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkout(t, in, "decline")
+	ev := nextEvent(t, events, payments.EventIntentFailed)
+	if ev.Intent.Status != payments.IntentFailed {
+		t.Fatalf("declined status = %q", ev.Intent.Status)
+	}
+
+	// the decision is final: a later authorize changes nothing
+	checkout(t, in, "authorize")
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected webhook %q", ev.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if _, err := p.Capture(ctx, in.ID); !errors.Is(err, payments.ErrDeclined) {
+		t.Fatalf("capture err = %v, want ErrDeclined", err)
+	}
+}
+
+func TestSimulatorRejectsBadRequests(t *testing.T) {
+	sim, _, _ := newTestSimulator(t)
+	ctx := context.Background()
+
+	wrongKey := NewProvider(sim.BaseURL, "sk_wrong", testSecret)
+	if _, err := wrongKey.CreateIntent(ctx, payments.IntentRequest{Amount: 10}); err == nil {
+		t.Fatal("wrong api key accepted")
+	}
+
+	p := NewProvider(sim.BaseURL, testAPIKey, testSecret)
+	if _, err := p.CreateIntent(ctx, payments.IntentRequest{Amount: 0}); err == nil {
+		t.Fatal("zero amount accepted")
+	}
+	if _, err := p.Capture(ctx, "pi_missing"); err == nil || errors.Is(err, payments.ErrDeclined) {
+		t.Fatalf("unknown intent err = %v, want a not-found error", err)
+	}
+}
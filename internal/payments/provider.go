@@ -0,0 +1,75 @@
+// Package payments abstracts the payment gateway behind PaymentProvider so
+// the invoice flow does not depend on a particular PSP. fakepay is the
+// built‑in provider for development and tests.
+package payments
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Intent statuses, modelled on the common card flow: the buyer authorises
+// at the provider's checkout, then the merchant captures.
+const (
+	IntentRequiresPayment = "requires_payment" // waiting for the buyer
+	IntentRequiresCapture = "requires_capture" // authorised, not captured yet
+	IntentSucceeded       = "succeeded"        // captured
+	IntentFailed          = "failed"           // declined / abandoned
+	IntentRefunded        = "refunded"         // fully refunded
+)
+
+// Webhook event types a provider reports.
+const (
+	EventIntentAuthorized = "intent.authorized"
+	EventIntentSucceeded  = "intent.succeeded"
+	EventIntentFailed     = "intent.failed"
+	EventRefundSucceeded  = "refund.succeeded"
+)
+
+var (
+	ErrBadSignature = errors.New("payment webhook signature mismatch")
+	ErrDeclined     = errors.New("payment declined")
+)
+
+// IntentRequest asks for a payment of Amount for Reference (our invoice).
+type IntentRequest struct {
+	Reference string
+	Amount    float64
+	Currency  string
+}
+
+// Intent is the provider's view of one payment.
+type Intent struct {
+	ID          string  `json:"id"`
+	Reference   string  `json:"reference"`
+	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency"`
+	Status      string  `json:"status"`
+	Refunded    float64 `json:"refunded"`
+	CheckoutURL string  `json:"checkout_url"` // where the buyer pays
+}
+
+// Refund is one (partial) refund of a captured intent.
+type Refund struct {
+	ID       string  `json:"id"`
+	IntentID string  `json:"intent_id"`
+	Amount   float64 `json:"amount"`
+}
+
+// WebhookEvent is a verified provider notification.
+type WebhookEvent struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Intent Intent `json:"intent"`
+}
+
+// PaymentProvider is a payment gateway.
+type PaymentProvider interface {
+	Name() string
+	CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error)
+	Capture(ctx context.Context, intentID string) (*Intent, error)
+	Refund(ctx context.Context, intentID string, amount float64) (*Refund, error)
+	// VerifyWebhook authenticates a webhook request body and decodes it.
+	VerifyWebhook(header http.Header, body []byte) (*WebhookEvent, error)
+}
@@ -0,0 +1,44 @@
+-- Shared helpers. Not a library of its own: LoadAll splices this file in
+-- after the "#!lua name=…" line of every library, since Redis Functions
+-- libraries cannot call each other's local functions.
+
+-- Every auction event gets a per‑auction, gap‑free sequence number and is
+-- appended to "auc_stream:<id>" (entry ID "<seq>-0") before being published,
+-- so reconnecting clients can replay what they missed. A copy also goes to
+-- the global "auc_events" stream with the Redis TIME (unix ms) it happened
+-- at, from which the auction_events history table is filled. "auc_events"
+-- is not capped here: internal/syncevents trims it behind what its consumer
+-- group has acknowledged, so no event is dropped before it is persisted.
+local STREAM_MAXLEN = 1000
+local function emit(auctionID, evt)
+  local seq = redis.call('INCR', 'auc_seq:' .. auctionID)
+  evt.seq = seq
+  local payload = cjson.encode(evt)
+  redis.call('XADD', 'auc_stream:' .. auctionID,
+    'MAXLEN', '~', STREAM_MAXLEN,
+    seq .. '-0',
+    'p', payload)
+  local now = redis.call('TIME')
+  redis.call('XADD', 'auc_events', '*',
+    'aid', auctionID,
+    'ts', now[1] .. string.format('%03d', math.floor(now[2] / 1000)),
+    'p', payload)
+  redis.call('PUBLISH', 'auc:' .. auctionID .. ':events', payload)
+  return seq
+end
+
+-- publish_lot: a lot transition on the sale channel (schema: events.Lot; not
+-- sequenced, subscribers resync from the catalog snapshot).
+local function publish_lot(saleID, auctionID, lot, transition, ea, hb, hbid)
+  redis.call('PUBLISH', 'sale:' .. saleID .. ':events', cjson.encode({
+    version     = 2,
+    event       = 'lot',
+    sale_id     = saleID,
+    auction_id  = auctionID,
+    lot         = tonumber(lot) or 0,
+    transition  = transition,
+    ends_at     = tonumber(ea) or 0,
+    high_bid    = tonumber(hb) or 0,
+    high_bidder = hbid or ''
+  }))
+end
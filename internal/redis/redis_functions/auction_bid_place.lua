@@ -5,17 +5,61 @@
   KEYS[2] = "auc_t:<id>"
   ARGV[1] = bidderId
   ARGV[2] = amountFloat
-  ARGV[3] = placedAtUnix
-  ARGV[4] = minIncrement (optional; "0" if none)
+  ARGV[3] = minIncrement (optional; "0" if none)
+  ARGV[4] = default credit limit for bidders without "credit:<id>" lim
+            (optional; negative = unlimited)
+  ARGV[5] = source (optional; "floor" | "phone" for a clerk bid, "" online)
+  ARGV[6] = clerkId (with source)
+
+  Credit: "credit:<user>" (lim, chg) is the cache of the user's limit and
+  open charges (internal/services/credit); "credit_exp:<user>" maps every
+  auction the user leads to the amount of that lead. A bid is rejected when
+  chg + the other leads + amount would exceed lim.
+
+  Soft close: when the hash has xw > 0 (extend window) and the bid lands
+  within xw seconds of the end, the end moves to bid time + xb. For a lot
+  of a sale ("sale", "lot"), every running lot after it moves by the same
+  amount, so the lots keep closing in order.
+
+  Registration: when the hash has reg = 1, only bidders in the
+  "auc_registered:<id>" set (approved by the seller,
+  internal/services/registration) may bid; others get not_registered.
+
+  Live auctions (live = 1) take bids while the auctioneer calls them (phase
+  OPEN, ONCE or TWICE) instead of until ea; a bid reopens the call, and bids
+  below the asking price are rejected. Every accepted bid is pushed onto
+  "auc_live_bids:<id>" so auction_live can undo it.
+
+  The bid timestamp and the close check use Redis TIME, the single clock
+  shared by every app instance.
 
 ]]
+
+-- emit and publish_lot: _prelude.lua (spliced in by LoadAll).
+
+-- extend moves the end of a running auction to ea and re‑arms its timer.
+local function extend(auctionID, old, ea, now)
+  redis.call('HSET', 'auc:' .. auctionID, 'ea', ea)
+  redis.call('EXPIRE', 'auc_t:' .. auctionID, ea - now)
+  -- schema: events.Extended (internal/events)
+  emit(auctionID, {
+    version          = 2,
+    event            = 'extended',
+    previous_ends_at = old,
+    ends_at          = ea
+  })
+end
+
 local function auction_place_bid(keys, argv)
   local akey      = keys[1]
   local timerKey  = keys[2]
   local bidder    = argv[1]
   local amount    = tonumber(argv[2])
-  local ts        = tonumber(argv[3])
-  local minInc    = tonumber(argv[4] or "0")
+  local ts        = tonumber(redis.call('TIME')[1])
+  local minInc    = tonumber(argv[3] or "0")
+  local defLimit  = tonumber(argv[4] or "-1")
+  local source    = argv[5] or ''
+  local clerk     = argv[6] or ''
   local auctionID = string.sub(akey, 5)
 
   -- Reject if auction is closed or timer key already expired
@@ -24,13 +68,38 @@ local function auction_place_bid(keys, argv)
     return redis.error_reply('auction_closed')
   end
 
-  -- Safety precaution: compare the bid at timestamp against stored auctions ends‑at timestamp
+  if redis.call('HGET', akey, 'reg') == '1'
+      and redis.call('SISMEMBER', 'auc_registered:' .. auctionID, bidder) == 0 then
+    return redis.error_reply('not_registered')
+  end
+
+  local live = redis.call('HGET', akey, 'live') == '1'
+  if source ~= '' and not live then
+    return redis.error_reply('not_live')
+  end
+
+  -- Safety precaution: compare the bid timestamp against the stored ends‑at timestamp
   local ea = tonumber(redis.call('HGET', akey, 'ea') or '0')
-  if ts >= ea then
+  if not live and ts >= ea then
     return redis.error_reply('auction_closed')
   end
 
   local current = tonumber(redis.call('HGET', akey, 'hb') or '0')
+  if live then
+    local ph = redis.call('HGET', akey, 'ph')
+    if ph ~= 'OPEN' and ph ~= 'ONCE' and ph ~= 'TWICE' then
+      return redis.error_reply('lot_not_open')
+    end
+    local ask = tonumber(redis.call('HGET', akey, 'ask') or '0')
+    if ask > current and amount < ask then
+      return redis.error_reply('bid_below_ask')
+    end
+  end
+  -- the opening bid must reach the start price
+  if current == 0 and amount < tonumber(redis.call('HGET', akey, 'sp') or '0') then
+    return redis.error_reply('bid_below_start')
+  end
+
   -- same price -> explicit error
   if amount == current then
     return redis.error_reply('bid_equal')
@@ -46,22 +115,95 @@ local function auction_place_bid(keys, argv)
     return redis.error_reply('bid_below_increment')
   end
 
+  local credit = redis.call('HMGET', 'credit:' .. bidder, 'lim', 'chg')
+  local limit  = tonumber(credit[1]) or defLimit
+  if limit >= 0 then
+    local exposure = tonumber(credit[2]) or 0
+    local leads    = redis.call('HGETALL', 'credit_exp:' .. bidder)
+    for i = 1, #leads, 2 do
+      if leads[i] ~= auctionID then -- raising an own lead replaces it
+        exposure = exposure + tonumber(leads[i + 1])
+      end
+    end
+    if exposure + amount > limit then
+      return redis.error_reply('insufficient_credit')
+    end
+  end
+
+  local prevBidder = redis.call('HGET', akey, 'hbid')
+  if prevBidder == false or prevBidder == '' then
+    prevBidder = nil
+  end
+
+  if live then
+    -- what auction_live needs to undo this bid
+    redis.call('RPUSH', 'auc_live_bids:' .. auctionID, cjson.encode({
+      bidder   = bidder,
+      amount   = amount,
+      at       = ts,
+      prev_hb  = current,
+      prev_bid = prevBidder or '',
+      prev_ts  = redis.call('HGET', akey, 'ts') or ''
+    }))
+    redis.call('HSET', akey, 'ph', 'OPEN')
+  end
   redis.call('HSET', akey, 'hb', amount, 'hbid', bidder, 'ts', ts)
+  redis.call('SADD', 'auc_bidders:' .. auctionID, bidder) -- presence: distinct bidders
+
+  -- move the exposure: the outbid user's lead is released
+  if prevBidder and prevBidder ~= bidder then
+    redis.call('HDEL', 'credit_exp:' .. prevBidder, auctionID)
+  end
+  redis.call('HSET', 'credit_exp:' .. bidder, auctionID, amount)
 
   -- append to global stream for persistence
-  redis.call('XADD', 'bids_stream', '*',
-    'aid', auctionID,
-    'bidder', bidder,
-    'amount', amount,
-    'at', ts)
-
-  redis.call('PUBLISH', 'auc:' .. auctionID .. ':events', cjson.encode({
-    version = 1,
-    event   = 'bid',
-    bidder  = bidder,
-    amount  = amount,
-    at      = ts
-  }))
+  if source ~= '' then
+    redis.call('XADD', 'bids_stream', '*',
+      'aid', auctionID,
+      'bidder', bidder,
+      'amount', amount,
+      'at', ts,
+      'src', source,
+      'by', clerk)
+  else
+    redis.call('XADD', 'bids_stream', '*',
+      'aid', auctionID,
+      'bidder', bidder,
+      'amount', amount,
+      'at', ts)
+  end
+
+  -- schema: events.Bid (internal/events)
+  emit(auctionID, {
+    version         = 2,
+    event           = 'bid',
+    bidder          = bidder,
+    amount          = amount,
+    at              = ts,
+    previous_bidder = prevBidder,                            -- nil ⇒ omitted
+    previous_amount = prevBidder and current or nil,
+    source          = source ~= '' and source or nil
+  })
+
+  local xw = tonumber(redis.call('HGET', akey, 'xw') or '0')
+  local newEa = ts + tonumber(redis.call('HGET', akey, 'xb') or '0')
+  if xw > 0 and ea - ts <= xw and newEa > ea then
+    extend(auctionID, ea, newEa, ts)
+    local sale = redis.call('HMGET', akey, 'sale', 'lot')
+    if sale[1] then
+      publish_lot(sale[1], auctionID, sale[2], 'extended', newEa, amount, bidder)
+      local delta = newEa - ea
+      local later = redis.call('ZRANGEBYSCORE', 'sale_lots:' .. sale[1], '(' .. sale[2], '+inf')
+      for _, id in ipairs(later) do
+        local f = redis.call('HMGET', 'auc:' .. id, 'st', 'ea', 'hb', 'hbid', 'lot')
+        if f[1] == 'RUNNING' then
+          local old = tonumber(f[2])
+          extend(id, old, old + delta, ts)
+          publish_lot(sale[1], id, f[5], 'extended', old + delta, f[3], f[4])
+        end
+      end
+    end
+  end
   return 1
 end
 redis.register_function('auction_place_bid', auction_place_bid)
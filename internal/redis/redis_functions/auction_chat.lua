@@ -0,0 +1,80 @@
+#!lua name=auction_chat
+--[[
+
+  auction_chat_post
+    KEYS[1] = "auc_chat:<id>"              capped list of recent messages (JSON, oldest first)
+    KEYS[2] = "auc_chat_slow:<id>:<user>"  slow‑mode marker
+    ARGV[1] = userId
+    ARGV[2] = text (already length‑checked and filtered)
+    ARGV[3] = historySize
+    ARGV[4] = slowModeMillis ("0" disables)
+    returns the new message ID
+
+  auction_chat_delete
+    KEYS[1] = "auc_chat:<id>"
+    ARGV[1] = messageId
+    ARGV[2] = moderatorId
+
+]]
+
+-- emit: _prelude.lua (spliced in by LoadAll).
+
+local function chat_auction_id(listKey)
+  return string.sub(listKey, string.len('auc_chat:') + 1)
+end
+
+local function auction_chat_post(keys, argv)
+  local listKey   = keys[1]
+  local auctionID = chat_auction_id(listKey)
+  local slowMs    = tonumber(argv[4] or '0')
+
+  if slowMs > 0 and not redis.call('SET', keys[2], '1', 'NX', 'PX', slowMs) then
+    return redis.error_reply('chat_slow_mode')
+  end
+
+  local msg = {
+    id      = redis.call('INCR', 'auc_chat_id:' .. auctionID),
+    user_id = argv[1],
+    text    = argv[2],
+    at      = tonumber(redis.call('TIME')[1])
+  }
+  redis.call('RPUSH', listKey, cjson.encode(msg))
+  redis.call('LTRIM', listKey, -tonumber(argv[3]), -1)
+
+  -- schema: events.Chat (internal/events)
+  emit(auctionID, {
+    version = 2,
+    event   = 'chat',
+    id      = msg.id,
+    user_id = msg.user_id,
+    text    = msg.text,
+    at      = msg.at
+  })
+  return msg.id
+end
+
+local function auction_chat_delete(keys, argv)
+  local listKey   = keys[1]
+  local auctionID = chat_auction_id(listKey)
+  local want      = tonumber(argv[1])
+
+  for _, raw in ipairs(redis.call('LRANGE', listKey, 0, -1)) do
+    local ok, m = pcall(cjson.decode, raw)
+    if ok and tonumber(m.id) == want then
+      redis.call('LREM', listKey, 1, raw)
+
+      -- schema: events.ChatRetracted (internal/events)
+      emit(auctionID, {
+        version = 2,
+        event   = 'chat_retracted',
+        id      = want,
+        by      = argv[2]
+      })
+      return 1
+    end
+  end
+  return redis.error_reply('chat_message_not_found')
+end
+
+redis.register_function('auction_chat_post', auction_chat_post)
+redis.register_function('auction_chat_delete', auction_chat_delete)
@@ -0,0 +1,145 @@
+#!lua name=auction_live
+--[[
+
+  auction_live – one auctioneer (or clerk) action on a live auction
+
+  KEYS[1] = "auc:<id>"
+  KEYS[2] = "auc_t:<id>"
+  KEYS[3] = "auc_live_bids:<id>"   bids that can be undone, oldest first
+  ARGV[1] = action: open | ask | going_once | going_twice | sold | pass | undo
+  ARGV[2] = actorId
+  ARGV[3] = amount (ask; optional opening ask for open)
+
+  Phases (hash field 'ph'):
+
+    WAITING ─open─▶ OPEN ─going_once─▶ ONCE ─going_twice─▶ TWICE ─sold─▶ SOLD
+                     ▲                  │                   │
+                     └──── a bid or undo reopens the call ──┘
+
+  pass ends the auction in any phase as PASSED, without a winner: the high
+  bid is dropped. sold and pass set ea to now and delete the timer, so no
+  bid gets in; the caller then finalises the auction (auction_stop).
+
+  undo retracts the last bid: the previous high bid and the bidders' credit
+  exposure are restored, and a bids_stream entry with undo = 1 tells syncbid
+  to drop the persisted bid.
+
+]]
+
+-- emit: _prelude.lua (spliced in by LoadAll).
+
+local function auction_live(keys, argv)
+  local akey      = keys[1]
+  local timerKey  = keys[2]
+  local undoKey   = keys[3]
+  local action    = argv[1]
+  local by        = argv[2]
+  local amount    = tonumber(argv[3] or '0') or 0
+  local auctionID = string.sub(akey, 5)
+
+  local f = redis.call('HMGET', akey, 'st', 'live', 'ph', 'ask', 'hb', 'hbid')
+  if f[1] ~= 'RUNNING' or redis.call('EXISTS', timerKey) == 0 then
+    return redis.error_reply('auction_closed')
+  end
+  if f[2] ~= '1' then
+    return redis.error_reply('not_live')
+  end
+
+  local ph      = f[3]
+  local ask     = tonumber(f[4]) or 0
+  local hb      = tonumber(f[5]) or 0
+  local hbid    = f[6] or ''
+  local calling = ph == 'OPEN' or ph == 'ONCE' or ph == 'TWICE'
+  local retracted
+
+  if action == 'open' then
+    if ph ~= 'WAITING' then
+      return redis.error_reply('bad_phase')
+    end
+    ph = 'OPEN'
+    if amount > 0 then
+      ask = amount
+    end
+  elseif action == 'ask' then
+    if amount <= hb then
+      return redis.error_reply('ask_below_current')
+    end
+    ask = amount
+  elseif action == 'going_once' then
+    if ph ~= 'OPEN' then
+      return redis.error_reply('bad_phase')
+    end
+    ph = 'ONCE'
+  elseif action == 'going_twice' then
+    if ph ~= 'ONCE' then
+      return redis.error_reply('bad_phase')
+    end
+    ph = 'TWICE'
+  elseif action == 'sold' then
+    if ph ~= 'TWICE' then
+      return redis.error_reply('bad_phase')
+    end
+    if hbid == '' then
+      return redis.error_reply('no_bids')
+    end
+    ph = 'SOLD'
+  elseif action == 'pass' then
+    if hbid ~= '' then
+      redis.call('HDEL', 'credit_exp:' .. hbid, auctionID)
+    end
+    hb, hbid = 0, ''
+    ph = 'PASSED'
+  elseif action == 'undo' then
+    if not calling then
+      return redis.error_reply('bad_phase')
+    end
+    local last = redis.call('RPOP', undoKey)
+    if not last then
+      return redis.error_reply('nothing_to_undo')
+    end
+    -- the hash holds exactly this bid: bids are pushed as they are accepted
+    local b = cjson.decode(last)
+    redis.call('HDEL', 'credit_exp:' .. b.bidder, auctionID)
+    if b.prev_bid ~= '' then
+      redis.call('HSET', 'credit_exp:' .. b.prev_bid, auctionID, b.prev_hb)
+    end
+    if b.prev_ts ~= '' then
+      redis.call('HSET', akey, 'ts', b.prev_ts)
+    else
+      redis.call('HDEL', akey, 'ts')
+    end
+    redis.call('XADD', 'bids_stream', '*',
+      'aid', auctionID,
+      'bidder', b.bidder,
+      'amount', b.amount,
+      'at', b.at,
+      'undo', 1)
+    retracted = b
+    hb, hbid = b.prev_hb, b.prev_bid
+    ph = 'OPEN'
+  else
+    return redis.error_reply('unknown_action')
+  end
+
+  redis.call('HSET', akey, 'ph', ph, 'ask', ask, 'hb', hb, 'hbid', hbid)
+  if ph == 'SOLD' or ph == 'PASSED' then
+    redis.call('HSET', akey, 'ea', redis.call('TIME')[1])
+    redis.call('DEL', timerKey)
+  end
+
+  -- schema: events.Live (internal/events)
+  emit(auctionID, {
+    version          = 2,
+    event            = 'live',
+    action           = action,
+    phase            = ph,
+    ask              = ask,
+    high_bid         = hb,
+    high_bidder      = hbid,
+    by               = by,
+    retracted_bidder = retracted and retracted.bidder or nil,
+    retracted_amount = retracted and retracted.amount or nil
+  })
+  return 1
+end
+redis.register_function('auction_live', auction_live)
@@ -0,0 +1,50 @@
+#!lua name=auction_presence
+--[[
+
+  KEYS[1] = "auc_presence:<id>"       zset, member "<instanceId>|<userId>",
+                                       score = heartbeat expiry (unix)
+  KEYS[2] = "auc_bidders:<id>"        set of distinct bidders
+  KEYS[3] = "auc_presence_last:<id>"  last published "<viewers>:<bidders>"
+
+  ARGV[1] = nowUnix
+
+  Prunes expired entries (crashed instances), counts distinct viewers and
+  bidders, and publishes "presence" only when the counts changed – so many
+  instances may call it for the same room without flooding clients.
+  Returns { viewers, bidders, published (0|1) }.
+
+]]
+local function auction_presence(keys, argv)
+  local zkey      = keys[1]
+  local now       = tonumber(argv[1])
+  local auctionID = string.sub(zkey, string.len('auc_presence:') + 1)
+
+  redis.call('ZREMRANGEBYSCORE', zkey, '-inf', '(' .. now)
+
+  local seen, viewers = {}, 0
+  for _, m in ipairs(redis.call('ZRANGE', zkey, 0, -1)) do
+    local user = string.match(m, '^[^|]*|(.*)$') or m
+    if not seen[user] then
+      seen[user] = true
+      viewers = viewers + 1
+    end
+  end
+  local bidders = redis.call('SCARD', keys[2])
+
+  local cur = viewers .. ':' .. bidders
+  if redis.call('GET', keys[3]) == cur then
+    return { viewers, bidders, 0 }
+  end
+  redis.call('SET', keys[3], cur, 'EX', 3600)
+
+  -- schema: events.Presence (ephemeral → plain PUBLISH, no seq/stream)
+  redis.call('PUBLISH', 'auc:' .. auctionID .. ':events', cjson.encode({
+    version = 2,
+    event   = 'presence',
+    seq     = 0,
+    viewers = viewers,
+    bidders = bidders
+  }))
+  return { viewers, bidders, 1 }
+end
+redis.register_function('auction_presence', auction_presence)
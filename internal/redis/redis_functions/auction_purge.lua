@@ -0,0 +1,33 @@
+#!lua name=auction_purge
+--[[
+
+  auction_purge – drop every Redis trace of a deleted auction (idempotent)
+
+  KEYS[1]  = "auc:<id>"
+  KEYS[2…] = the auction's other keys (timer, seq, stream, presence, …)
+
+  The leader's "credit_exp:<hbid>" entry for the auction (written by
+  auction_place_bid, auction_start and auction_live) is removed in the same
+  call, read from the hash before it goes, so a deleted auction never keeps
+  counting against the bidder's credit.
+
+]]
+
+local function auction_purge(keys, argv)
+  local hashKey   = keys[1]
+  local auctionID = string.sub(hashKey, 5)
+
+  local f = redis.call('HMGET', hashKey, 'hbid', 'sale')
+  if f[1] and f[1] ~= '' then
+    redis.call('HDEL', 'credit_exp:' .. f[1], auctionID)
+  end
+  if f[2] then
+    redis.call('ZREM', 'sale_lots:' .. f[2], auctionID)
+  end
+
+  redis.call('DEL', unpack(keys))
+  redis.call('SREM', 'aucs:active', hashKey)
+  redis.call('SREM', 'aucs:ended', hashKey)
+  return 1
+end
+redis.register_function('auction_purge', auction_purge)
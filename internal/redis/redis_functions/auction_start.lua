@@ -5,42 +5,205 @@
   KEYS[2] = "auc_t:<id>"
 
   ARGV[1] = sellerId
-  ARGV[2] = startsAtUnix
-  ARGV[3] = endsAtUnix
-  ARGV[4] = ttlSeconds
+  ARGV[2] = endsAtUnix
+  ARGV[3] = startPrice (optional; opening bid, "0" if none)
+  ARGV[4] = extendWindow seconds (optional; soft close, "0" = off)
+  ARGV[5] = extendBy seconds (optional)
+  ARGV[6] = saleId (optional; the auction is a lot of that sale)
+  ARGV[7] = lotNo (with saleId)
+  ARGV[8] = live (optional; "1" = closed by an auctioneer, see auction_live.lua)
+  ARGV[9] = minIncrement (optional; "0" if none)
+  ARGV[10] = default credit limit (optional; negative = unlimited)
+  ARGV[11] = registration (optional; "1" = only bidders in
+             "auc_registered:<id>" may bid, see auction_place_bid.lua)
+  ARGV[12…] = absentee bids in submission order: bidderId, maxAmount, …
+
+  Lots are indexed in the "sale_lots:<saleId>" sorted set (score = lot
+  number) so an extension can shift the lots that follow.
+
+  A live auction's timer has no TTL: it waits in phase WAITING until the
+  auctioneer opens it, and ends on "sold" or "pass". ends_at is only the
+  scheduled end.
+
+  Absentee bids are resolved like proxy bids, in submission order: the
+  first one that reaches the opening price (start price or increment, 1
+  when neither is set) leads at that price; a later one that beats the
+  leader's maximum takes the lead at that maximum + increment (capped at
+  its own), otherwise the leader rises to the challenger's maximum +
+  increment (capped at the leader's). Each maximum is first capped at the
+  bidder's credit headroom, checked as in auction_place_bid; bids of
+  bidders who are not (or no longer) registered are skipped. Every change
+  of the lead is a bid (source "absentee"), so the auction opens at the
+  resulting price.
+
+  starts_at and the timer TTL are derived from Redis TIME, so every instance
+  agrees on when the auction closes.
+
+  Idempotent: StartAuction retries a start whose Postgres side did not
+  complete, so an auction that is already running is left as it is (no
+  events, the absentee bids are not replayed). An auction that already ran
+  and ended ("aucs:ended") is refused with auction_finished.
+
+  returns { started (1, or 0 when it was already running), starts_at, ends_at }
 
 ]]
+
+-- emit and publish_lot: _prelude.lua (spliced in by LoadAll).
+
+-- headroom: how much more the bidder may lead with (see auction_place_bid.lua).
+local function headroom(bidder, auctionID, defLimit)
+  local credit = redis.call('HMGET', 'credit:' .. bidder, 'lim', 'chg')
+  local limit  = tonumber(credit[1]) or defLimit
+  if limit < 0 then
+    return math.huge
+  end
+  local exposure = tonumber(credit[2]) or 0
+  local leads    = redis.call('HGETALL', 'credit_exp:' .. bidder)
+  for i = 1, #leads, 2 do
+    if leads[i] ~= auctionID then
+      exposure = exposure + tonumber(leads[i + 1])
+    end
+  end
+  return limit - exposure
+end
+
+-- lead records an absentee bid taking (or raising) the lead, with the same
+-- effects as an accepted auction_place_bid.
+local function lead(hashKey, auctionID, bidder, amount, now)
+  local prev    = redis.call('HMGET', hashKey, 'hb', 'hbid')
+  local current = tonumber(prev[1]) or 0
+  local prevBidder = prev[2]
+  if prevBidder == false or prevBidder == '' then
+    prevBidder = nil
+  end
+
+  redis.call('HSET', hashKey, 'hb', amount, 'hbid', bidder, 'ts', now)
+  redis.call('SADD', 'auc_bidders:' .. auctionID, bidder)
+  if prevBidder and prevBidder ~= bidder then
+    redis.call('HDEL', 'credit_exp:' .. prevBidder, auctionID)
+  end
+  redis.call('HSET', 'credit_exp:' .. bidder, auctionID, amount)
+  redis.call('XADD', 'bids_stream', '*',
+    'aid', auctionID,
+    'bidder', bidder,
+    'amount', amount,
+    'at', now,
+    'src', 'absentee')
+
+  -- schema: events.Bid (internal/events)
+  emit(auctionID, {
+    version         = 2,
+    event           = 'bid',
+    bidder          = bidder,
+    amount          = amount,
+    at              = now,
+    previous_bidder = prevBidder,
+    previous_amount = prevBidder and current or nil,
+    source          = 'absentee'
+  })
+end
+
 local function auction_start(keys, argv)
   local hashKey   = keys[1]
   local timerKey  = keys[2]
   local auctionID = string.sub(hashKey, 5)
 
   if redis.call('EXISTS', hashKey) == 1 then
-    return { err = 'already_started' }
+    local f = redis.call('HMGET', hashKey, 'sa', 'ea')
+    return { 0, tonumber(f[1]) or 0, tonumber(f[2]) or 0 }
+  end
+  if redis.call('SISMEMBER', 'aucs:ended', hashKey) == 1 then
+    return redis.error_reply('auction_finished')
   end
 
-  local state = redis.call('HGET', hashKey, 'st')
-  if state == 'RUNNING' or state == 'FINISHED' then
-    return redis.error_reply('already_started')
+  local now = tonumber(redis.call('TIME')[1])
+  local ea  = tonumber(argv[2])
+  if ea <= now then
+    return redis.error_reply('auction_closed')
   end
 
   redis.call('HSET', hashKey,
     'sid', argv[1],
-    'sa', argv[2],
-    'ea', argv[3],
+    'sa', now,
+    'ea', ea,
     'st', 'RUNNING',
     'hb', 0,
-    'hbid', ''
+    'hbid', '',
+    'sp', tonumber(argv[3] or '0'),
+    'xw', tonumber(argv[4] or '0'),
+    'xb', tonumber(argv[5] or '0')
   )
+  if argv[11] == '1' then
+    redis.call('HSET', hashKey, 'reg', 1)
+  end
+  local live = argv[8] == '1'
+  if live then
+    redis.call('HSET', hashKey, 'live', 1, 'ph', 'WAITING', 'ask', 0)
+  end
+  local saleID = argv[6] or ''
+  if saleID ~= '' then
+    redis.call('HSET', hashKey, 'sale', saleID, 'lot', argv[7])
+    redis.call('ZADD', 'sale_lots:' .. saleID, argv[7], auctionID)
+  end
 
-  redis.call('SET', timerKey, '1', 'EX', argv[4])
+  if live then
+    redis.call('SET', timerKey, '1')
+  else
+    redis.call('SET', timerKey, '1', 'EX', ea - now)
+  end
   redis.call('SADD', 'aucs:active', hashKey)
 
-  redis.call('PUBLISH', 'auc:' .. auctionID .. ':events', cjson.encode({
-    version = 1,
-    event   = 'start',
-    endsAt  = argv[3]
-  }))
-  return 1
+  -- schema: events.Start (internal/events)
+  emit(auctionID, {
+    version   = 2,
+    event     = 'start',
+    seller_id = argv[1],
+    starts_at = now,
+    ends_at   = ea
+  })
+
+  local sp       = tonumber(argv[3] or '0')
+  local minInc   = tonumber(argv[9] or '0')
+  local defLimit = tonumber(argv[10] or '-1')
+  local leader, lmax, price
+  local registered = 'auc_registered:' .. auctionID
+  for i = 12, #argv - 1, 2 do
+    local bidder = argv[i]
+    local max    = math.min(tonumber(argv[i + 1]), headroom(bidder, auctionID, defLimit))
+    if argv[11] == '1' and redis.call('SISMEMBER', registered, bidder) == 0 then
+      max = 0 -- not (or no longer) registered: never leads nor raises
+    end
+    if not leader then
+      local open = math.max(sp, minInc)
+      if open <= 0 then
+        open = 1
+      end
+      if max >= open then
+        leader, lmax, price = bidder, max, open
+        lead(hashKey, auctionID, bidder, price, now)
+      end
+    elseif max >= price + minInc and max > price then
+      if max > lmax then
+        local amount = math.min(max, lmax + minInc)
+        if amount <= price then
+          amount = max
+        end
+        leader, lmax, price = bidder, max, amount
+        lead(hashKey, auctionID, bidder, price, now)
+      else
+        local amount = math.min(lmax, max + minInc)
+        if amount > price then
+          price = amount
+          lead(hashKey, auctionID, leader, price, now)
+        end
+      end
+    end
+  end
+
+  if saleID ~= '' then
+    local f = redis.call('HMGET', hashKey, 'hb', 'hbid')
+    publish_lot(saleID, auctionID, argv[7], 'open', ea, f[1], f[2])
+  end
+  return { 1, now, ea }
 end
 redis.register_function('auction_start', auction_start)
@@ -5,21 +5,40 @@
   KEYS[2] = "auc_t:<id>"
 
 ]]
+
+-- emit and publish_lot: _prelude.lua (spliced in by LoadAll).
+
 local function auction_stop(keys, argv)
   local hashKey   = keys[1]
   local timerKey  = keys[2]
   local auctionID = string.sub(hashKey, 5)
 
-  local snapshot  = redis.call('HGETALL', hashKey)
-  if next(snapshot) ~= nil then
-    redis.call('PUBLISH', 'auc:' .. auctionID .. ':events', cjson.encode({
-      version = 1,
-      event   = 'stop',
-      data    = snapshot
-    }))
+  -- schema: events.Stop (internal/events) – the final state, typed
+  local f = redis.call('HMGET', hashKey, 'sid', 'sa', 'ea', 'hb', 'hbid', 'sale', 'lot')
+  if f[6] then
+    publish_lot(f[6], auctionID, f[7], 'closed', f[3], f[4], f[5])
+    redis.call('ZREM', 'sale_lots:' .. f[6], auctionID)
+  end
+  if f[1] then
+    emit(auctionID, {
+      version     = 2,
+      event       = 'stop',
+      seller_id   = f[1],
+      status      = 'FINISHED',
+      starts_at   = tonumber(f[2]) or 0,
+      ends_at     = tonumber(f[3]) or 0,
+      high_bid    = tonumber(f[4]) or 0,
+      high_bidder = f[5] or ''
+    })
   end
 
-  redis.call('DEL', hashKey, timerKey)
+  redis.call('DEL', hashKey, timerKey, 'auc_live_bids:' .. auctionID, 'auc_registered:' .. auctionID)
+  -- keep the event log around for late reconnects, but not forever
+  redis.call('EXPIRE', 'auc_stream:' .. auctionID, 3600)
+  redis.call('EXPIRE', 'auc_seq:' .. auctionID, 3600)
+  redis.call('EXPIRE', 'auc_bidders:' .. auctionID, 3600)
+  redis.call('EXPIRE', 'auc_chat:' .. auctionID, 3600)
+  redis.call('EXPIRE', 'auc_chat_id:' .. auctionID, 3600)
   redis.call('SREM', 'aucs:active', hashKey)
   redis.call('SADD', 'aucs:ended', hashKey)
   return 1
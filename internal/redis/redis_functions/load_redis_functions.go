@@ -13,14 +13,24 @@ import (
 //go:embed *.lua
 var fs embed.FS
 
-// LoadAll finds every embedded Lua file and loads/replaces it in Redis.
+// prelude holds the helpers every library shares (emit, publish_lot).
+// Libraries cannot call into each other, so it is spliced into each one
+// right after its "#!lua name=…" line.
+const prelude = "_prelude.lua"
+
+// LoadAll finds every embedded Lua library and loads/replaces it in Redis.
+// Files starting with "_" are includes, not libraries.
 func LoadAll(ctx context.Context, rdb *redis.Client) error {
+	shared, err := fs.ReadFile(prelude)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", prelude, err)
+	}
 	files, err := fs.ReadDir(".")
 	if err != nil {
 		return fmt.Errorf("read embed dir: %w", err)
 	}
 	for _, f := range files {
-		if f.IsDir() || !strings.HasSuffix(f.Name(), ".lua") {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".lua") || strings.HasPrefix(f.Name(), "_") {
 			continue
 		}
 
@@ -28,7 +38,11 @@ func LoadAll(ctx context.Context, rdb *redis.Client) error {
 		if err != nil {
 			return err
 		}
-		if err := rdb.FunctionLoadReplace(ctx, string(code)).Err(); err != nil {
+		shebang, body, ok := strings.Cut(string(code), "\n")
+		if !ok || !strings.HasPrefix(shebang, "#!lua") {
+			return fmt.Errorf("load lua %s: missing #!lua header", f.Name())
+		}
+		if err := rdb.FunctionLoadReplace(ctx, shebang+"\n"+string(shared)+"\n"+body).Err(); err != nil {
 			return fmt.Errorf("load lua %s: %w", f.Name(), err)
 		}
 		zap.L().Info("lua function loaded", zap.String("file", f.Name()))
@@ -4,19 +4,29 @@ import (
 	"auctionbidgo/internal/services/auction"
 	"context"
 	"strings"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// sweepInterval paces the fallback scan for auctions whose expiry
+// notification was missed (keyspace events are fire‑and‑forget, e.g. lost
+// while no instance was running, or the instance crashed mid‑Finalize).
+const sweepInterval = 30 * time.Second
+
 // Run listens to key‑expiry events and finalises auctions in Postgres.
 // Run must be started once at service boot.
 func Run(ctx context.Context, rdb *redis.Client, svc auction.IAuctionService) {
 	_ = rdb.ConfigSet(ctx, "notify-keyspace-events", "Ex").Err()
 	ps := rdb.PSubscribe(ctx, "__keyevent@*__:expired")
+	sweep := time.NewTicker(sweepInterval)
+	defer sweep.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-sweep.C:
+			sweepOrphans(ctx, rdb, svc)
 		case m := <-ps.Channel():
 			if !strings.HasPrefix(m.Payload, "auc_t:") {
 				continue
@@ -26,3 +36,24 @@ func Run(ctx context.Context, rdb *redis.Client, svc auction.IAuctionService) {
 		}
 	}
 }
+
+// sweepOrphans finalises active auctions that have lost their timer key.
+func sweepOrphans(ctx context.Context, rdb *redis.Client, svc auction.IAuctionService) {
+	keys, err := rdb.SMembers(ctx, "aucs:active").Result()
+	if err != nil || len(keys) == 0 {
+		return
+	}
+	pipe := rdb.Pipeline()
+	timers := make([]*redis.IntCmd, len(keys))
+	for i, k := range keys {
+		timers[i] = pipe.Exists(ctx, "auc_t:"+strings.TrimPrefix(k, "auc:"))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return
+	}
+	for i, k := range keys {
+		if timers[i].Val() == 0 {
+			_ = svc.Finalize(ctx, strings.TrimPrefix(k, "auc:"))
+		}
+	}
+}
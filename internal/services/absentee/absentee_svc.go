@@ -0,0 +1,218 @@
+// Package absentee implements absentee (commission) bids: maxima left on an
+// auction before it starts. They are kept in Postgres while the auction is
+// PENDING; StartAuction claims them (Claim) and auction_start resolves them
+// in submission order, so the auction opens at the resulting price.
+//
+//	place ─▶ ACTIVE ──cancel──▶ CANCELLED
+//	         ACTIVE ──start───▶ PLACED ──start refused──▶ ACTIVE (Unclaim)
+//
+// A bidder has at most one ACTIVE bid per auction; placing again replaces
+// its maximum and moves it to the back of the queue.
+package absentee
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Bid statuses.
+const (
+	StatusActive    = "ACTIVE"
+	StatusCancelled = "CANCELLED"
+	StatusPlaced    = "PLACED" // handed to the auction when it started
+)
+
+var (
+	ErrAuctionNotFound = errors.New("auction not found")
+	ErrNotFound        = errors.New("absentee bid not found")
+	ErrNotPending      = errors.New("auction has already started")
+	ErrBadAmount       = errors.New("max_amount must be > 0")
+	ErrBelowStart      = errors.New("max_amount below start price")
+	ErrNotRegistered   = errors.New("not registered for this auction")
+	ErrForbidden       = errors.New("not your absentee bids")
+)
+
+// Bid is one absentee bid.
+type Bid struct {
+	ID          int64      `json:"id"`
+	AuctionID   string     `json:"auction_id"`
+	BidderID    string     `json:"bidder_id"`
+	MaxAmount   float64    `json:"max_amount"`
+	Status      string     `json:"status" enums:"ACTIVE,CANCELLED,PLACED"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+	PlacedAt    *time.Time `json:"placed_at,omitempty"`
+}
+
+type IAbsenteeService interface {
+	// Place leaves (or replaces) the bidder's maximum on a PENDING auction.
+	Place(ctx context.Context, auctionID, bidderID string, maxAmount float64) (*Bid, error)
+	// Cancel withdraws an ACTIVE bid of the bidder.
+	Cancel(ctx context.Context, id int64, bidderID string) error
+	// ForBidder returns the bidder's absentee bids, newest first; auctionID
+	// optionally narrows them to one auction.
+	ForBidder(ctx context.Context, bidderID, auctionID string) ([]Bid, error)
+}
+
+type absenteeService struct {
+	db *sql.DB
+}
+
+var _ IAbsenteeService = (*absenteeService)(nil)
+
+func NewAbsenteeService(db *sql.DB) IAbsenteeService {
+	return &absenteeService{db: db}
+}
+
+const selectBid = `
+	  SELECT id, auction_id, bidder_id, max_amount::float8, status, created_at, cancelled_at, placed_at
+	    FROM absentee_bids`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanBid(row scanner) (*Bid, error) {
+	var b Bid
+	err := row.Scan(&b.ID, &b.AuctionID, &b.BidderID, &b.MaxAmount, &b.Status,
+		&b.CreatedAt, &b.CancelledAt, &b.PlacedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (svc *absenteeService) Place(ctx context.Context, auctionID, bidderID string, maxAmount float64) (*Bid, error) {
+	if maxAmount <= 0 {
+		return nil, ErrBadAmount
+	}
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the row lock orders Place against Claim: a bid either makes it into
+	// the start or sees the auction RUNNING
+	var st string
+	var startPrice float64
+	var registered bool
+	err = tx.QueryRowContext(ctx, `
+	  SELECT a.status, coalesce(a.start_price, 0)::float8,
+	         NOT a.registration_required
+	         OR EXISTS (SELECT 1 FROM auction_registrations r
+	                     WHERE r.auction_id = a.id AND r.bidder_id = $2 AND r.status = 'APPROVED')
+	    FROM auctions a WHERE a.id = $1 FOR UPDATE OF a`, auctionID, bidderID).Scan(&st, &startPrice, &registered)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAuctionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if st != "PENDING" {
+		return nil, ErrNotPending
+	}
+	if !registered { // registration required (internal/services/registration)
+		return nil, ErrNotRegistered
+	}
+	if maxAmount < startPrice {
+		return nil, ErrBelowStart
+	}
+
+	b, err := scanBid(tx.QueryRowContext(ctx, `
+	  INSERT INTO absentee_bids (auction_id, bidder_id, max_amount)
+	       VALUES ($1, $2, $3)
+	  ON CONFLICT (auction_id, bidder_id) WHERE status = 'ACTIVE'
+	  DO UPDATE SET max_amount = excluded.max_amount, created_at = now()
+	  RETURNING id, auction_id, bidder_id, max_amount::float8, status, created_at, cancelled_at, placed_at`,
+		auctionID, bidderID, maxAmount))
+	if err != nil {
+		return nil, err
+	}
+	return b, tx.Commit()
+}
+
+func (svc *absenteeService) Cancel(ctx context.Context, id int64, bidderID string) error {
+	res, err := svc.db.ExecContext(ctx, `
+	  UPDATE absentee_bids SET status = 'CANCELLED', cancelled_at = now()
+	   WHERE id = $1 AND bidder_id = $2 AND status = 'ACTIVE'`, id, bidderID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 1 {
+		return nil
+	}
+	// not the bidder's, already cancelled, or placed at the start
+	b, err := scanBid(svc.db.QueryRowContext(ctx, selectBid+` WHERE id = $1 AND bidder_id = $2`, id, bidderID))
+	if err != nil {
+		return err
+	}
+	if b.Status == StatusPlaced {
+		return ErrNotPending
+	}
+	return nil // already cancelled
+}
+
+func (svc *absenteeService) ForBidder(ctx context.Context, bidderID, auctionID string) ([]Bid, error) {
+	rows, err := svc.db.QueryContext(ctx, selectBid+`
+	   WHERE bidder_id = $1 AND ($2 = '' OR auction_id = $2)
+	ORDER BY created_at DESC, id DESC`, bidderID, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Bid{}
+	for rows.Next() {
+		b, err := scanBid(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *b)
+	}
+	return out, rows.Err()
+}
+
+// Claim marks the ACTIVE bids of an auction PLACED and returns every PLACED
+// bid of it in submission order. It runs in the caller's transaction, which
+// holds the auction row lock and commits the claim before the auction is
+// started in Redis; a retried start (auction still STARTING) gets the same
+// bids again.
+func Claim(ctx context.Context, tx *sql.Tx, auctionID string) ([]Bid, error) {
+	if _, err := tx.ExecContext(ctx, `
+	  UPDATE absentee_bids SET status = 'PLACED', placed_at = now()
+	   WHERE auction_id = $1 AND status = 'ACTIVE'`, auctionID); err != nil {
+		return nil, err
+	}
+	rows, err := tx.QueryContext(ctx, selectBid+`
+	   WHERE auction_id = $1 AND status = 'PLACED'
+	ORDER BY created_at, id`, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Bid
+	for rows.Next() {
+		b, err := scanBid(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *b)
+	}
+	return out, rows.Err()
+}
+
+// Unclaim hands the PLACED bids of an auction back (ACTIVE), for a start
+// that Redis refused, so they still count when the auction is started again.
+func Unclaim(ctx context.Context, tx *sql.Tx, auctionID string) error {
+	_, err := tx.ExecContext(ctx, `
+	  UPDATE absentee_bids SET status = 'ACTIVE', placed_at = NULL
+	   WHERE auction_id = $1 AND status = 'PLACED'`, auctionID)
+	return err
+}
@@ -1,6 +1,10 @@
 package auction
 
 import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/outbox"
+	"auctionbidgo/internal/services/absentee"
+	"auctionbidgo/internal/services/registration"
 	"context"
 	"database/sql"
 	"errors"
@@ -21,18 +25,62 @@ type AuctionDTO struct {
 	Status     string    `json:"status"    example:"RUNNING"`
 	HighBid    float64   `json:"high_bid"`
 	HighBidder string    `json:"high_bidder"`
+	StartPrice float64   `json:"start_price,omitempty"` // opening bid
+	Live       bool      `json:"live,omitempty"`        // closed by an auctioneer (internal/services/live)
+
+	// Visibility and RegistrationRequired: who sees the auction and whether
+	// bidders need the seller's approval (internal/services/registration).
+	// Only on single‑auction reads; listings show public auctions only.
+	Visibility           string `json:"visibility,omitempty" enums:"public,unlisted,private"`
+	RegistrationRequired bool   `json:"registration_required,omitempty"`
+
+	// AcceptsOffers: best‑offer listing not started yet, or finished unsold
+	// (internal/services/offer). Only on single‑auction reads.
+	AcceptsOffers bool `json:"accepts_offers,omitempty"`
+
+	Presence *PresenceDTO `json:"presence,omitempty"` // only on single‑auction reads
+	Relist   *RelistDTO   `json:"relist,omitempty"`   // only on single‑auction reads
+}
+
+// RelistDTO links an auction into its relist chain (internal/services/relist).
+type RelistDTO struct {
+	Cycle     int    `json:"cycle"`              // 0 = original listing
+	Previous  string `json:"previous,omitempty"` // the unsold auction this one relists
+	Next      string `json:"next,omitempty"`     // the auction that relisted this one
+	Remaining int    `json:"remaining"`          // relists left after this one
+}
+
+// PresenceDTO holds the cluster‑wide room counts.
+type PresenceDTO struct {
+	Viewers int `json:"viewers"` // distinct users watching right now
+	Bidders int `json:"bidders"` // distinct users who placed a bid
 }
 
 const (
-	redisAuctionKeyPrefix      = "auc:"
-	redisAuctionTimerKeyPrefix = "auc_t:"
+	redisAuctionKeyPrefix       = "auc:"
+	redisAuctionTimerKeyPrefix  = "auc_t:"
+	redisAuctionSeqKeyPrefix    = "auc_seq:"    // event sequence counter
+	redisAuctionStreamKeyPrefix = "auc_stream:" // event log used for WS replay
+	redisPresenceKeyPrefix      = "auc_presence:"
+	redisPresenceLastKeyPrefix  = "auc_presence_last:"
+	redisBiddersKeyPrefix       = "auc_bidders:"
+	redisChatKeyPrefix          = "auc_chat:"
+	redisChatIDKeyPrefix        = "auc_chat_id:"
+	redisLiveBidsKeyPrefix      = "auc_live_bids:"  // undoable bids of a live auction
+	redisRegisteredKeyPrefix    = "auc_registered:" // approved bidders (registration required)
 )
 
 var (
-	ErrAuctionClosed     = errors.New("auction closed")
-	ErrBidEqual          = errors.New("bid must be higher than current bid")
-	ErrBidBelowIncrement = errors.New("bid below min increment")
-	ErrBidBelowCurrent   = errors.New("bid below current high bid")
+	ErrAuctionClosed      = errors.New("auction closed")
+	ErrBidEqual           = errors.New("bid must be higher than current bid")
+	ErrBidBelowIncrement  = errors.New("bid below min increment")
+	ErrBidBelowCurrent    = errors.New("bid below current high bid")
+	ErrInsufficientCredit = errors.New("insufficient credit")
+	ErrBidBelowStart      = errors.New("bid below start price")
+	ErrLotNotOpen         = errors.New("the auctioneer has not opened the lot")
+	ErrBidBelowAsk        = errors.New("bid below asking price")
+	ErrNotLive            = errors.New("not a live auction")
+	ErrNotRegistered      = errors.New("not registered for this auction")
 
 	ErrAlreadyRunning  = errors.New("auction already running")
 	ErrAuctionFinished = errors.New("auction already finished")
@@ -41,10 +89,13 @@ var (
 )
 
 type IAuctionService interface {
-	CreateAuction(ctx context.Context, id, sellerID, item string, endsAt time.Time) (string, error)
+	CreateAuction(ctx context.Context, id, sellerID, item string, startPrice float64, live bool, endsAt time.Time) (string, error)
 	StartAuction(ctx context.Context, auctionID, sellerID string, endsAt time.Time) error
 	StopAuction(ctx context.Context, auctionId string) error
 	PlaceBid(ctx context.Context, auctionId string, userId string, bidAmount float64) error
+	// PlaceClerkBid enters a floor or phone bid on a live auction on the
+	// bidder's behalf.
+	PlaceClerkBid(ctx context.Context, auctionID, bidderID string, amount float64, source, clerkID string) error
 	Finalize(ctx context.Context, auctionId string) error
 	GetAuction(ctx context.Context, id string) (*AuctionDTO, error)
 	ListAuctions(ctx context.Context, status string, limit, offset int) ([]AuctionDTO, error)
@@ -55,15 +106,22 @@ type auctionService struct {
 	rdc          *redis.Client
 	db           *sql.DB
 	minIncrement float64
+	creditLimit  float64       // for bidders without a credit account; < 0 = unlimited
+	relay        *outbox.Relay // woken after commits that wrote outbox rows
+	absentee     absentee.IAbsenteeService
 }
 
 var _ = (*auctionService)(nil)
 
-func NewAuctionService(rdc *redis.Client, db *sql.DB, minInc float64) IAuctionService {
+func NewAuctionService(rdc *redis.Client, db *sql.DB, minInc, creditLimit float64, relay *outbox.Relay,
+	absenteeSvc absentee.IAbsenteeService) IAuctionService {
 	return &auctionService{
 		rdc:          rdc,
 		db:           db,
 		minIncrement: minInc,
+		creditLimit:  creditLimit,
+		relay:        relay,
+		absentee:     absenteeSvc,
 	}
 }
 
@@ -71,8 +129,11 @@ func NewAuctionService(rdc *redis.Client, db *sql.DB, minInc float64) IAuctionSe
 //   - If `id` is empty a random UUID is generated.
 //   - It fails when an auction with the same ID already exists
 //     (whatever its state).
+//   - startPrice is the lowest opening bid; 0 = any.
+//   - live auctions are closed by an auctioneer; endsAt is only the
+//     scheduled end.
 func (svc *auctionService) CreateAuction(
-	ctx context.Context, id, sellerID, item string, endsAt time.Time,
+	ctx context.Context, id, sellerID, item string, startPrice float64, live bool, endsAt time.Time,
 ) (string, error) {
 	if id == "" {
 		id = uuid.NewString()
@@ -83,85 +144,236 @@ func (svc *auctionService) CreateAuction(
 		return "", ErrAuctionClosed
 	}
 
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
 	const q = `
       INSERT INTO auctions (id, seller_id, item,
-                            starts_at, ends_at, status)
-           VALUES ($1, $2, $3, now(), $4, 'PENDING')`
-	if _, err := svc.db.ExecContext(ctx, q,
-		id, sellerID, item, endsAt); err != nil {
+                            starts_at, ends_at, status, start_price, live)
+           VALUES ($1, $2, $3, now(), $4, 'PENDING', nullif($5, 0), $6)`
+	if _, err := tx.ExecContext(ctx, q,
+		id, sellerID, item, endsAt, startPrice, live); err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
 			return "", ErrAuctionExists
 		}
 		return "", err
 	}
+	if err := outbox.Write(ctx, tx, outbox.KindAuctionCreated, id, CreatedPayload{
+		ID: id, SellerID: sellerID, Item: item, StartPrice: startPrice, Live: live, EndsAt: endsAt,
+	}); err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	svc.relay.Wake()
 	return id, nil
 }
 
-// Start creates the disposable Redis hash + TTL
+// Start creates the disposable Redis hash + TTL. The TTL itself is computed
+// in Lua against Redis TIME; the local check only rejects obvious mistakes.
+// Absentee bids left while the auction was PENDING are resolved by
+// auction_start, so it opens at their price. When registration is required
+// the approved bidders are (re)loaded into Redis first.
+//
+// Postgres and Redis cannot commit together, so the start runs in steps:
+// the claim of the absentee bids and a STARTING marker are committed first,
+// then auction_start runs (idempotent, no row lock held) and the row is
+// brought in line with it. A start that fails after the claim leaves the
+// auction STARTING and is completed by calling StartAuction again.
 func (svc *auctionService) StartAuction(ctx context.Context, id, seller string, endsAt time.Time) error {
-	ttl := int(time.Until(endsAt).Seconds())
-	if ttl <= 0 {
+	if !endsAt.After(time.Now()) {
 		return ErrAuctionClosed
 	}
 
-	// DB sanity check (2 s timeout)
+	p, err := svc.claimStart(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if p.regRequired {
+		key := redisRegisteredKeyPrefix + id
+		if _, err := svc.rdc.TxPipelined(ctx, func(pl redis.Pipeliner) error {
+			pl.Del(ctx, key)
+			for _, b := range p.approved {
+				pl.SAdd(ctx, key, b)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	args := []any{
+		seller,
+		endsAt.Unix(),
+		p.startPrice,
+		p.extendWindow,
+		p.extendBy,
+		p.saleID,
+		p.lotNo,
+		p.live,
+		svc.minIncrement,
+		svc.creditLimit,
+		p.regRequired,
+	}
+	for _, b := range p.bids {
+		args = append(args, b.BidderID, b.MaxAmount)
+	}
+
+	// { started, starts_at, ends_at } – also for an auction a previous
+	// attempt already started, whose times the row then takes over
+	res, err := svc.rdc.FCall(ctx, "auction_start",
+		[]string{
+			redisAuctionKeyPrefix + id,      // "auc:<id>"
+			redisAuctionTimerKeyPrefix + id, // timer key
+		},
+		args...,
+	).Int64Slice()
+	switch {
+	case err != nil && strings.Contains(err.Error(), "auction_closed"):
+		// refused before anything was written: back to PENDING
+		return svc.abortStart(ctx, id, ErrAuctionClosed)
+	case err != nil && strings.Contains(err.Error(), "auction_finished"):
+		return ErrAuctionFinished
+	case err != nil:
+		return err // still STARTING; a retry completes it
+	case len(res) != 3:
+		return fmt.Errorf("auction_start: unexpected reply %v", res)
+	}
+
+	// mirror the state, so "is it running" reads (sales, offers, listings)
+	// don't depend on the 10 s synchroniser
 	dbCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
+	_, err = svc.db.ExecContext(dbCtx, `
+	  UPDATE auctions SET status = 'RUNNING', starts_at = to_timestamp($2), ends_at = to_timestamp($3)
+	   WHERE id = $1 AND status IN ('PENDING', 'STARTING')`, id, res[1], res[2])
+	return err
+}
 
+// startParams is what claimStart read for auction_start.
+type startParams struct {
+	startPrice             float64
+	extendWindow, extendBy int
+	saleID                 string
+	lotNo                  int
+	live, regRequired      bool
+	approved               []string       // registered bidders, with regRequired
+	bids                   []absentee.Bid // claimed absentee bids, in order
+}
+
+// claimStart moves a PENDING auction to STARTING and claims its absentee
+// bids, in one short transaction committed before Redis is touched. For an
+// auction left STARTING by a failed attempt it returns the same bids.
+func (svc *auctionService) claimStart(ctx context.Context, id string) (*startParams, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	tx, err := svc.db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// lots of a sale also get its soft close (internal/services/sale); the
+	// row lock keeps absentee bids from slipping in after the claim
 	var st string
-	err := svc.db.QueryRowContext(dbCtx, `SELECT status FROM auctions WHERE id = $1`, id).Scan(&st)
+	var p startParams
+	err = tx.QueryRowContext(dbCtx, `
+	  SELECT a.status, coalesce(a.start_price, 0)::float8, coalesce(s.extend_window, 0),
+	         coalesce(s.extend_by, 0), coalesce(a.sale_id, ''), coalesce(a.lot_no, 0), a.live,
+	         a.registration_required
+	    FROM auctions a
+	    LEFT JOIN sales s ON s.id = a.sale_id
+	   WHERE a.id = $1
+	     FOR UPDATE OF a`, id).Scan(&st, &p.startPrice, &p.extendWindow, &p.extendBy, &p.saleID, &p.lotNo,
+		&p.live, &p.regRequired)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return err
+		return nil, err
 	}
 	switch st {
 	case "RUNNING":
-		return ErrAlreadyRunning
-	case "FINISHED":
-		return ErrAuctionFinished
+		return nil, ErrAlreadyRunning
+	case "FINISHED", "PAID", "UNPAID":
+		return nil, ErrAuctionFinished
 	}
 
-	return svc.rdc.FCall(ctx, "auction_start",
-		[]string{
-			redisAuctionKeyPrefix + id,      // "auc:<id>"
-			redisAuctionTimerKeyPrefix + id, // timer key
-		},
-		seller,
-		time.Now().Unix(),
-		endsAt.Unix(),
-		ttl,
-	).Err()
+	if p.regRequired {
+		if p.approved, err = registration.Approved(dbCtx, tx, id); err != nil {
+			return nil, err
+		}
+	}
+	if p.bids, err = absentee.Claim(dbCtx, tx, id); err != nil {
+		return nil, err
+	}
+	if _, err = tx.ExecContext(dbCtx, `
+	  UPDATE auctions SET status = 'STARTING' WHERE id = $1 AND status = 'PENDING'`, id); err != nil {
+		return nil, err
+	}
+	return &p, tx.Commit()
+}
+
+// abortStart returns an auction that Redis refused to start to PENDING, with
+// its absentee bids ACTIVE again, and returns cause.
+func (svc *auctionService) abortStart(ctx context.Context, id string, cause error) error {
+	dbCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	tx, err := svc.db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(dbCtx, `
+	  UPDATE auctions SET status = 'PENDING' WHERE id = $1 AND status = 'STARTING'`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 1 {
+		if err = absentee.Unclaim(dbCtx, tx, id); err != nil {
+			return err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	return cause
 }
 
 // Stop lets seller cancel early (or system close). We simply delete the key.
 func (svc *auctionService) StopAuction(ctx context.Context, auctionID string) error {
 
-	// If DB already shows FINISHED refuse the request
+	// If DB already shows FINISHED (or PAID/UNPAID) refuse the request
 	var st string
 	err := svc.db.QueryRowContext(ctx, `SELECT status FROM auctions WHERE id = $1`, auctionID).Scan(&st)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return err
 	}
-	if st == "FINISHED" {
+	if st == "FINISHED" || st == "PAID" || st == "UNPAID" {
 		return ErrAuctionFinished
 	}
 
 	// Otherwise perform the usual finalisation path (idempotent).
-	if err := svc.Finalize(ctx, auctionID); err != nil {
-		return err
-	}
-
-	// ensure TTL key removed
-	_ = svc.rdc.Del(ctx, redisAuctionTimerKeyPrefix+auctionID).Err()
-	return nil
+	return svc.Finalize(ctx, auctionID)
 }
 
 // Bid executes Lua function that performs optimistic check & Pub/Sub.
 func (svc *auctionService) PlaceBid(ctx context.Context, auctionID, bidderID string, amount float64) error {
+	return svc.placeBid(ctx, auctionID, bidderID, amount, "", "")
+}
+
+func (svc *auctionService) PlaceClerkBid(ctx context.Context, auctionID, bidderID string, amount float64, source, clerkID string) error {
+	return svc.placeBid(ctx, auctionID, bidderID, amount, source, clerkID)
+}
 
+func (svc *auctionService) placeBid(ctx context.Context, auctionID, bidderID string, amount float64, source, clerkID string) error {
 	ctx, cancel := context.WithTimeout(ctx, 1500*time.Millisecond)
 	defer cancel()
 
-	now := time.Now().Unix()
 	res := svc.rdc.FCall(ctx, "auction_place_bid",
 		[]string{
 			redisAuctionKeyPrefix + auctionID,
@@ -169,11 +381,16 @@ func (svc *auctionService) PlaceBid(ctx context.Context, auctionID, bidderID str
 		},
 		bidderID,
 		amount,
-		now,
 		svc.minIncrement,
+		svc.creditLimit,
+		source,
+		clerkID,
 	)
 	if err := res.Err(); err != nil {
 		if strings.Contains(err.Error(), "auction_closed") {
+			if source == "" {
+				return svc.placeAbsentee(ctx, auctionID, bidderID, amount)
+			}
 			return ErrAuctionClosed
 		}
 		if strings.Contains(err.Error(), "bid_equal") {
@@ -185,12 +402,51 @@ func (svc *auctionService) PlaceBid(ctx context.Context, auctionID, bidderID str
 		if strings.Contains(err.Error(), "bid_below_increment") {
 			return ErrBidBelowIncrement
 		}
+		if strings.Contains(err.Error(), "insufficient_credit") {
+			return ErrInsufficientCredit
+		}
+		if strings.Contains(err.Error(), "bid_below_start") {
+			return ErrBidBelowStart
+		}
+		if strings.Contains(err.Error(), "lot_not_open") {
+			return ErrLotNotOpen
+		}
+		if strings.Contains(err.Error(), "bid_below_ask") {
+			return ErrBidBelowAsk
+		}
+		if strings.Contains(err.Error(), "not_live") {
+			return ErrNotLive
+		}
+		if strings.Contains(err.Error(), "not_registered") {
+			return ErrNotRegistered
+		}
 		return err
 	}
 	return nil
 }
 
-// called by key‑expiry watcher
+// placeAbsentee keeps a bid on an auction that has not started yet as the
+// bidder's absentee maximum (internal/services/absentee).
+func (svc *auctionService) placeAbsentee(ctx context.Context, auctionID, bidderID string, amount float64) error {
+	_, err := svc.absentee.Place(ctx, auctionID, bidderID, amount)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, absentee.ErrBelowStart), errors.Is(err, absentee.ErrBadAmount):
+		return ErrBidBelowStart
+	case errors.Is(err, absentee.ErrNotPending), errors.Is(err, absentee.ErrAuctionNotFound):
+		return ErrAuctionClosed
+	case errors.Is(err, absentee.ErrNotRegistered):
+		return ErrNotRegistered
+	}
+	return err
+}
+
+// Finalize is called by the key‑expiry watcher (and StopAuction). The DB
+// write and the "auction.finished" outbox row commit together; the relay then
+// runs auction_stop (publish + Redis cleanup), so a crash after the commit
+// can no longer leave a FINISHED row without its stop event. Calling it
+// again before the relay has run is a no-op.
 func (svc *auctionService) Finalize(ctx context.Context, id string) error {
 	// distributed, 5 s lock – avoids duplicate finalisations
 	lockKey := "auc_lock:" + id
@@ -200,6 +456,10 @@ func (svc *auctionService) Finalize(ctx context.Context, id string) error {
 	}
 	defer svc.rdc.Del(ctx, lockKey) // snapshot hash -> result (makes DB write idempotent)
 
+	// No more bids from here on: auction_place_bid requires the timer key
+	// (already gone when the watcher calls us).
+	_ = svc.rdc.Del(ctx, redisAuctionTimerKeyPrefix+id).Err()
+
 	key := redisAuctionKeyPrefix + id
 	data, err := svc.rdc.HGetAll(ctx, key).Result()
 	if err != nil || len(data) == 0 {
@@ -212,6 +472,18 @@ func (svc *auctionService) Finalize(ctx context.Context, id string) error {
 	}
 	defer tx.Rollback()
 
+	// Already finalised (the orphan sweep retries until the relay has run
+	// auction_stop): no second outbox row, and a PAID or UNPAID row stays so.
+	var st string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM auctions WHERE id = $1 FOR UPDATE`, id).Scan(&st)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if st == "FINISHED" || st == "PAID" || st == "UNPAID" {
+		svc.relay.Wake()
+		return nil
+	}
+
 	// Up‑sert so that we also persist auctions that finished before the 10 s
 	// high‑bid synchroniser had a chance to create their row.
 	const upsertQ = `
@@ -221,6 +493,7 @@ func (svc *auctionService) Finalize(ctx context.Context, id string) error {
 	                        'FINISHED', $5,       $6)
 	  ON CONFLICT (id) DO UPDATE
 	        SET status     = 'FINISHED',
+	            ends_at    = EXCLUDED.ends_at, -- may have been extended
 	            high_bid   = EXCLUDED.high_bid,
 	            high_bidder= EXCLUDED.high_bidder`
 
@@ -245,16 +518,26 @@ func (svc *auctionService) Finalize(ctx context.Context, id string) error {
 			return err
 		}
 	}
+
+	final := events.SnapshotFromHash(0, data)
+	if err = outbox.Write(ctx, tx, outbox.KindAuctionFinished, id, events.Stop{
+		Header:     events.Header{Version: events.Version},
+		SellerID:   final.SellerID,
+		Status:     "FINISHED",
+		StartsAt:   final.StartsAt,
+		EndsAt:     final.EndsAt,
+		HighBid:    final.HighBid,
+		HighBidder: final.HighBidder,
+	}); err != nil {
+		return err
+	}
 	if err = tx.Commit(); err != nil {
 		return err
 	}
 
-	// broadcast and clean redis
-	return svc.rdc.FCall(ctx, "auction_stop",
-		[]string{
-			key,
-			redisAuctionTimerKeyPrefix + id,
-		}).Err()
+	// broadcast and clean redis – via the relay
+	svc.relay.Wake()
+	return nil
 }
 func (svc *auctionService) GetAuction(ctx context.Context, id string) (*AuctionDTO, error) {
 	// 1. Fast‑path ‑ if it is RUNNING, serve directly from Redis
@@ -268,26 +551,92 @@ func (svc *auctionService) GetAuction(ctx context.Context, id string) (*AuctionD
 			Status:     st,
 			HighBid:    atof(snap["hb"]),
 			HighBidder: snap["hbid"],
+			StartPrice: atof(snap["sp"]),
+			Live:       snap["live"] == "1",
+			Presence:   svc.presence(ctx, id, true),
+			Relist:     svc.relist(ctx, id),
+
+			RegistrationRequired: snap["reg"] == "1",
+			Visibility:           svc.visibility(ctx, id),
 		}, nil
 	}
 
 	// 2. Otherwise go to Postgres
 	const q = `SELECT id, seller_id, starts_at, ends_at,
-                      status, coalesce(high_bid,0), coalesce(high_bidder,'')
+                      status, coalesce(high_bid,0), coalesce(high_bidder,''),
+                      coalesce(start_price,0), live, visibility, registration_required,
+                      (status = 'PENDING' AND best_offer)
+                        OR (status = 'FINISHED' AND coalesce(high_bidder,'') = ''
+                            AND NOT EXISTS (SELECT 1 FROM auctions n WHERE n.relisted_from = auctions.id))
                  FROM auctions WHERE id = $1`
 	row := svc.db.QueryRowContext(ctx, q, id)
 	dto := &AuctionDTO{}
 	if err := row.Scan(&dto.ID, &dto.SellerID,
 		&dto.StartsAt, &dto.EndsAt, &dto.Status,
-		&dto.HighBid, &dto.HighBidder); err != nil {
+		&dto.HighBid, &dto.HighBidder, &dto.StartPrice, &dto.Live, &dto.Visibility, &dto.RegistrationRequired,
+		&dto.AcceptsOffers); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("auction %s not found", id)
 		}
 		return nil, err
 	}
+	dto.Presence = svc.presence(ctx, id, false)
+	dto.Relist = svc.relist(ctx, id)
 	return dto, nil
 }
 
+// visibility reads a running auction's visibility, which is not mirrored in
+// Redis; empty when unknown.
+func (svc *auctionService) visibility(ctx context.Context, id string) string {
+	var v string
+	_ = svc.db.QueryRowContext(ctx, `SELECT visibility FROM auctions WHERE id = $1`, id).Scan(&v)
+	return v
+}
+
+// relist returns the auction's place in its relist chain; nil when it is
+// neither a relist nor relisted nor set to relist.
+func (svc *auctionService) relist(ctx context.Context, id string) *RelistDTO {
+	r := &RelistDTO{}
+	err := svc.db.QueryRowContext(ctx, `
+	  SELECT a.relist_cycle, coalesce(a.relisted_from, ''), coalesce(n.id, ''), a.relist_remaining
+	    FROM auctions a
+	    LEFT JOIN auctions n ON n.relisted_from = a.id
+	   WHERE a.id = $1`, id).Scan(&r.Cycle, &r.Previous, &r.Next, &r.Remaining)
+	if err != nil || *r == (RelistDTO{}) {
+		return nil
+	}
+	return r
+}
+
+// presence counts distinct live viewers (entries whose heartbeat hasn't
+// expired, across all instances) and distinct bidders – from Redis while
+// the auction runs, from the bids table afterwards.
+func (svc *auctionService) presence(ctx context.Context, id string, running bool) *PresenceDTO {
+	p := &PresenceDTO{}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	members, _ := svc.rdc.ZRangeByScore(ctx, redisPresenceKeyPrefix+id,
+		&redis.ZRangeBy{Min: now, Max: "+inf"}).Result()
+	seen := make(map[string]struct{}, len(members))
+	for _, m := range members {
+		_, user, found := strings.Cut(m, "|") // "<instanceID>|<userID>"
+		if !found {
+			user = m
+		}
+		seen[user] = struct{}{}
+	}
+	p.Viewers = len(seen)
+
+	if running {
+		n, _ := svc.rdc.SCard(ctx, redisBiddersKeyPrefix+id).Result()
+		p.Bidders = int(n)
+	} else {
+		_ = svc.db.QueryRowContext(ctx,
+			`SELECT count(DISTINCT bidder_id) FROM bids WHERE auction_id = $1`, id).Scan(&p.Bidders)
+	}
+	return p
+}
+
 func (svc *auctionService) ListAuctions(ctx context.Context, st string,
 	limit, offset int) ([]AuctionDTO, error) {
 
@@ -298,12 +647,14 @@ func (svc *auctionService) ListAuctions(ctx context.Context, st string,
 		rows *sql.Rows
 		err  error
 	)
+	// unlisted and private auctions are reachable by ID only
 	base := `SELECT id, seller_id, starts_at, ends_at,
                     status, coalesce(high_bid,0), coalesce(high_bidder,'')
-               FROM auctions`
+               FROM auctions
+              WHERE visibility = 'public'`
 	switch st {
-	case "RUNNING", "FINISHED":
-		base += " WHERE status = $1"
+	case "RUNNING", "FINISHED", "PAID", "UNPAID":
+		base += " AND status = $1"
 		rows, err = svc.db.QueryContext(ctx, base+" ORDER BY ends_at DESC LIMIT $2 OFFSET $3",
 			st, limit, offset)
 	default:
@@ -337,7 +688,8 @@ func atof(s string) float64 {
 	return v
 }
 
-// DeleteAuction removes all traces of an auction provided it is not RUNNING.
+// DeleteAuction removes all traces of an auction provided it is not RUNNING
+// (or STARTING: Redis may already run it).
 func (svc *auctionService) DeleteAuction(ctx context.Context, id string) error {
 	// ── 1. Fast check in Redis (if hash exists) ───────────────────────
 	st, _ := svc.rdc.HGet(ctx, redisAuctionKeyPrefix+id, "st").Result()
@@ -353,7 +705,7 @@ func (svc *auctionService) DeleteAuction(ctx context.Context, id string) error {
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return err // unexpected DB error
 	}
-	if dbStatus == "RUNNING" { // row exists and is RUNNING → forbid
+	if dbStatus == "RUNNING" || dbStatus == "STARTING" { // row exists and is (being) started → forbid
 		return ErrAuctionRunning
 	}
 	if errors.Is(err, sql.ErrNoRows) && st == "" { // nothing to delete
@@ -371,20 +723,40 @@ func (svc *auctionService) DeleteAuction(ctx context.Context, id string) error {
 		`DELETE FROM bids WHERE auction_id = $1`, id); err != nil {
 		return err
 	}
+	if _, err = tx.ExecContext(ctx,
+		`DELETE FROM auction_events WHERE auction_id = $1`, id); err != nil {
+		return err
+	}
 	if _, err = tx.ExecContext(ctx,
 		`DELETE FROM auctions WHERE id = $1`, id); err != nil {
 		return err
 	}
+	if err = outbox.Write(ctx, tx, outbox.KindAuctionDeleted, id, DeletedPayload{ID: id}); err != nil {
+		return err
+	}
 	if err = tx.Commit(); err != nil {
 		return err
 	}
 
-	// ── 4. Redis: purge keys & sets  (idempotent ops) ─────────────────
-	_ = svc.rdc.Del(ctx,
-		redisAuctionKeyPrefix+id,
-		redisAuctionTimerKeyPrefix+id).Err()
-	_ = svc.rdc.SRem(ctx, "aucs:active", redisAuctionKeyPrefix+id).Err()
-	_ = svc.rdc.SRem(ctx, "aucs:ended", redisAuctionKeyPrefix+id).Err()
-
+	// ── 4. Redis: purge keys & sets – via the relay ───────────────────
+	svc.relay.Wake()
 	return nil
 }
+
+// purge removes every Redis key of a deleted auction, and the leader's
+// credit exposure for it, in one auction_purge call (idempotent).
+func purge(ctx context.Context, rdc *redis.Client, id string) error {
+	return rdc.FCall(ctx, "auction_purge", []string{
+		redisAuctionKeyPrefix + id, // read for hbid before it goes
+		redisAuctionTimerKeyPrefix + id,
+		redisAuctionSeqKeyPrefix + id,
+		redisAuctionStreamKeyPrefix + id,
+		redisPresenceKeyPrefix + id,
+		redisPresenceLastKeyPrefix + id,
+		redisBiddersKeyPrefix + id,
+		redisChatKeyPrefix + id,
+		redisChatIDKeyPrefix + id,
+		redisLiveBidsKeyPrefix + id,
+		redisRegisteredKeyPrefix + id,
+	}).Err()
+}
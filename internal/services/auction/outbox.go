@@ -0,0 +1,42 @@
+package auction
+
+import (
+	"auctionbidgo/internal/outbox"
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CreatedPayload is the outbox payload of "auction.created".
+type CreatedPayload struct {
+	ID         string    `json:"id"`
+	SellerID   string    `json:"seller_id"`
+	Item       string    `json:"item"`
+	StartPrice float64   `json:"start_price,omitempty"`
+	Live       bool      `json:"live,omitempty"` // closed by an auctioneer
+	EndsAt     time.Time `json:"ends_at"`
+
+	RelistedFrom string `json:"relisted_from,omitempty"` // set on automatic relists
+}
+
+// DeletedPayload is the outbox payload of "auction.deleted".
+type DeletedPayload struct {
+	ID string `json:"id"`
+}
+
+// RegisterOutboxHandlers wires the Redis side of the auction lifecycle into
+// the relay. Both handlers are idempotent, as the relay requires.
+func RegisterOutboxHandlers(r *outbox.Relay, rdc *redis.Client) {
+	// publish the typed stop event and clean up (no‑op once the hash is gone)
+	r.Handle(outbox.KindAuctionFinished, func(ctx context.Context, m outbox.Message) error {
+		return rdc.FCall(ctx, "auction_stop",
+			[]string{
+				redisAuctionKeyPrefix + m.AggregateID,
+				redisAuctionTimerKeyPrefix + m.AggregateID,
+			}).Err()
+	})
+	r.Handle(outbox.KindAuctionDeleted, func(ctx context.Context, m outbox.Message) error {
+		return purge(ctx, rdc, m.AggregateID)
+	})
+}
@@ -0,0 +1,443 @@
+package bidhistory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Bid statuses.
+const (
+	StatusWinning = "winning" // current high bid of a running auction
+	StatusOutbid  = "outbid"
+	StatusWinner  = "winner" // the winning bid of a finished auction
+)
+
+// Sort orders.
+const (
+	SortNewest  = "placed_at_desc"
+	SortOldest  = "placed_at_asc"
+	SortHighest = "amount_desc"
+	SortLowest  = "amount_asc"
+)
+
+const (
+	bidsStream = "bids_stream"
+	// streamWindow bounds the bids_stream tail merged into results. syncbid
+	// persists within seconds, so older entries are already in Postgres.
+	streamWindow = time.Minute
+)
+
+var ErrBadCursor = errors.New("invalid cursor")
+
+// Bid is one history row.
+type Bid struct {
+	AuctionID string    `json:"auction_id"`
+	BidderID  string    `json:"bidder_id"`
+	Amount    float64   `json:"amount"`
+	PlacedAt  time.Time `json:"placed_at"`
+	Status    string    `json:"status"            enums:"winning,outbid,winner"`
+	Pending   bool      `json:"pending,omitempty"` // from bids_stream, not yet in Postgres
+}
+
+// Query filters and pages a listing. From is inclusive, To exclusive.
+type Query struct {
+	From, To *time.Time
+	Sort     string // one of the Sort* constants; default SortNewest
+	Cursor   string // NextCursor of the previous page
+	Limit    int
+}
+
+// Page is one listing page.
+type Page struct {
+	Bids       []Bid  `json:"bids"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type IBidHistoryService interface {
+	ForAuction(ctx context.Context, auctionID string, q Query) (*Page, error)
+	ForBidder(ctx context.Context, bidderID string, q Query) (*Page, error)
+}
+
+type bidHistoryService struct {
+	rdc *redis.Client
+	db  *sql.DB
+}
+
+var _ IBidHistoryService = (*bidHistoryService)(nil)
+
+func NewBidHistoryService(rdc *redis.Client, db *sql.DB) IBidHistoryService {
+	return &bidHistoryService{rdc: rdc, db: db}
+}
+
+// Mask hides most of a bidder ID for public viewers: "user123" → "u***3".
+func Mask(id string) string {
+	r := []rune(id)
+	if len(r) <= 2 {
+		return string(r[:min(len(r), 1)]) + "***"
+	}
+	return string(r[0]) + "***" + string(r[len(r)-1])
+}
+
+// ── sorting & cursors ───────────────────────────────────────────────────────
+//
+// Rows are ordered by a full key – (placed_at | amount, then the other one,
+// auction_id, bidder_id) – which is unique once duplicates are folded, so a
+// cursor is simply the last row's key and pages never skip or repeat.
+
+type cursor struct {
+	PlacedAt int64   `json:"p"` // unix µs
+	Amount   float64 `json:"a"`
+	Auction  string  `json:"aid"`
+	Bidder   string  `json:"bid"`
+}
+
+func encodeCursor(b Bid) string {
+	raw, _ := json.Marshal(cursor{b.PlacedAt.UnixMicro(), b.Amount, b.AuctionID, b.BidderID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrBadCursor
+	}
+	return &c, nil
+}
+
+type order struct {
+	sqlOrder string // ORDER BY clause
+	sqlAfter string // row comparison against the cursor ($c1..$c4 → %d placeholders)
+	byAmount bool
+	desc     bool
+}
+
+var orders = map[string]order{
+	SortNewest:  {"placed_at DESC, amount DESC, auction_id DESC, bidder_id DESC", "(placed_at, amount, auction_id, bidder_id) < ($%d, $%d, $%d, $%d)", false, true},
+	SortOldest:  {"placed_at, amount, auction_id, bidder_id", "(placed_at, amount, auction_id, bidder_id) > ($%d, $%d, $%d, $%d)", false, false},
+	SortHighest: {"amount DESC, placed_at DESC, auction_id DESC, bidder_id DESC", "(amount, placed_at, auction_id, bidder_id) < ($%d, $%d, $%d, $%d)", true, true},
+	SortLowest:  {"amount, placed_at, auction_id, bidder_id", "(amount, placed_at, auction_id, bidder_id) > ($%d, $%d, $%d, $%d)", true, false},
+}
+
+// compare orders a and b by the full key in ascending direction.
+func (o order) compare(a, b Bid) int {
+	first := func(x, y Bid) int {
+		if o.byAmount {
+			return cmpFloat(x.Amount, y.Amount)
+		}
+		return x.PlacedAt.Compare(y.PlacedAt)
+	}
+	second := func(x, y Bid) int {
+		if o.byAmount {
+			return x.PlacedAt.Compare(y.PlacedAt)
+		}
+		return cmpFloat(x.Amount, y.Amount)
+	}
+	if c := first(a, b); c != 0 {
+		return c
+	}
+	if c := second(a, b); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.AuctionID, b.AuctionID); c != 0 {
+		return c
+	}
+	return strings.Compare(a.BidderID, b.BidderID)
+}
+
+// before reports whether a comes before b in this order.
+func (o order) before(a, b Bid) bool {
+	if o.desc {
+		return o.compare(a, b) > 0
+	}
+	return o.compare(a, b) < 0
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// ── listing ────────────────────────────────────────────────────────────────
+
+func (svc *bidHistoryService) ForAuction(ctx context.Context, auctionID string, q Query) (*Page, error) {
+	return svc.list(ctx, "auction_id", auctionID, q)
+}
+
+func (svc *bidHistoryService) ForBidder(ctx context.Context, bidderID string, q Query) (*Page, error) {
+	return svc.list(ctx, "bidder_id", bidderID, q)
+}
+
+// list pages the bids whose column (auction_id | bidder_id) equals value.
+func (svc *bidHistoryService) list(ctx context.Context, column, value string, q Query) (*Page, error) {
+	o, ok := orders[q.Sort]
+	if !ok {
+		o = orders[SortNewest]
+	}
+	if q.Limit <= 0 {
+		q.Limit = 50
+	}
+	var after *cursor
+	if q.Cursor != "" {
+		var err error
+		if after, err = decodeCursor(q.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	persisted, err := svc.query(ctx, column, value, q, o, after)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := svc.pending(ctx, column, value, q, o, after)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := append(persisted, pending...)
+	slices.SortFunc(rows, func(a, b Bid) int {
+		if o.before(a, b) {
+			return -1
+		}
+		if o.before(b, a) {
+			return 1
+		}
+		return 0
+	})
+
+	p := &Page{Bids: rows}
+	if len(rows) > q.Limit {
+		p.Bids = rows[:q.Limit]
+		p.NextCursor = encodeCursor(p.Bids[q.Limit-1])
+	}
+	if err := svc.annotate(ctx, p.Bids); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// query reads one page (+1 row) from Postgres. Duplicate rows of the same
+// bid (syncbid replays, the copy Finalize writes) are folded to the first.
+func (svc *bidHistoryService) query(ctx context.Context, column, value string, q Query, o order, after *cursor) ([]Bid, error) {
+	where := []string{"TRUE"}
+	args := []any{value}
+	if q.From != nil {
+		args = append(args, *q.From)
+		where = append(where, fmt.Sprintf("placed_at >= $%d", len(args)))
+	}
+	if q.To != nil {
+		args = append(args, *q.To)
+		where = append(where, fmt.Sprintf("placed_at < $%d", len(args)))
+	}
+	if after != nil {
+		n := len(args)
+		if o.byAmount {
+			args = append(args, after.Amount, time.UnixMicro(after.PlacedAt), after.Auction, after.Bidder)
+		} else {
+			args = append(args, time.UnixMicro(after.PlacedAt), after.Amount, after.Auction, after.Bidder)
+		}
+		where = append(where, fmt.Sprintf(o.sqlAfter, n+1, n+2, n+3, n+4))
+	}
+	args = append(args, q.Limit+1)
+
+	// column is one of two constants, never user input
+	stmt := `
+	  WITH b AS (
+	    SELECT DISTINCT ON (auction_id, bidder_id, amount)
+	           auction_id, bidder_id, amount::float8 AS amount, placed_at
+	      FROM bids
+	     WHERE ` + column + ` = $1
+	  ORDER BY auction_id, bidder_id, amount, placed_at)
+	  SELECT auction_id, bidder_id, amount, placed_at
+	    FROM b
+	   WHERE ` + strings.Join(where, " AND ") + `
+	ORDER BY ` + o.sqlOrder + `
+	   LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := svc.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Bid
+	for rows.Next() {
+		var b Bid
+		if err := rows.Scan(&b.AuctionID, &b.BidderID, &b.Amount, &b.PlacedAt); err != nil {
+			return nil, err
+		}
+		b.PlacedAt = b.PlacedAt.UTC()
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// pending returns the recent bids_stream entries that match the filter and
+// are not in Postgres yet, so the history never lags the live state. Bids
+// undone by a live auction's clerk (entries with undo set) are left out.
+func (svc *bidHistoryService) pending(ctx context.Context, column, value string, q Query, o order, after *cursor) ([]Bid, error) {
+	start := strconv.FormatInt(time.Now().Add(-streamWindow).UnixMilli(), 10)
+	msgs, err := svc.rdc.XRange(ctx, bidsStream, start, "+").Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	type bidKey struct{ aid, bidder, amount string }
+	keyOf := func(m redis.XMessage) bidKey {
+		aid, _ := m.Values["aid"].(string)
+		bidder, _ := m.Values["bidder"].(string)
+		amt, _ := m.Values["amount"].(string)
+		return bidKey{aid, bidder, amt}
+	}
+	undone := map[bidKey]int{}
+	for _, m := range msgs {
+		if _, ok := m.Values["undo"]; ok {
+			undone[keyOf(m)]++
+		}
+	}
+
+	var cand []Bid
+	for _, m := range msgs {
+		if _, ok := m.Values["undo"]; ok {
+			continue
+		}
+		if k := keyOf(m); undone[k] > 0 {
+			undone[k]--
+			continue
+		}
+		b := Bid{Pending: true}
+		b.AuctionID, _ = m.Values["aid"].(string)
+		b.BidderID, _ = m.Values["bidder"].(string)
+		amt, _ := m.Values["amount"].(string)
+		at, _ := m.Values["at"].(string)
+		b.Amount, _ = strconv.ParseFloat(amt, 64)
+		sec, _ := strconv.ParseInt(at, 10, 64)
+		b.PlacedAt = time.Unix(sec, 0).UTC()
+
+		if (column == "auction_id" && b.AuctionID != value) || (column == "bidder_id" && b.BidderID != value) {
+			continue
+		}
+		if (q.From != nil && b.PlacedAt.Before(*q.From)) || (q.To != nil && !b.PlacedAt.Before(*q.To)) {
+			continue
+		}
+		if after != nil {
+			last := Bid{
+				AuctionID: after.Auction, BidderID: after.Bidder,
+				Amount: after.Amount, PlacedAt: time.UnixMicro(after.PlacedAt).UTC(),
+			}
+			if !o.before(last, b) {
+				continue
+			}
+		}
+		cand = append(cand, b)
+	}
+	if len(cand) == 0 {
+		return nil, nil
+	}
+
+	// drop those syncbid has persisted meanwhile
+	aids := make([]string, len(cand))
+	bidders := make([]string, len(cand))
+	amounts := make([]float64, len(cand))
+	for i, b := range cand {
+		aids[i], bidders[i], amounts[i] = b.AuctionID, b.BidderID, b.Amount
+	}
+	const q2 = `
+	  SELECT c.aid, c.bidder, c.amount
+	    FROM unnest($1::text[], $2::text[], $3::float8[]) AS c(aid, bidder, amount)
+	   WHERE EXISTS (SELECT 1 FROM bids
+	                  WHERE auction_id = c.aid AND bidder_id = c.bidder AND amount = c.amount)`
+	rows, err := svc.db.QueryContext(ctx, q2, aids, bidders, amounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type key struct {
+		aid, bidder string
+		amount      float64
+	}
+	stored := map[key]bool{}
+	for rows.Next() {
+		var k key
+		if err := rows.Scan(&k.aid, &k.bidder, &k.amount); err != nil {
+			return nil, err
+		}
+		stored[k] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := cand[:0]
+	for _, b := range cand {
+		if !stored[key{b.AuctionID, b.BidderID, b.Amount}] {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+// annotate sets each bid's status from its auction's current high bid:
+// live from Redis while running, else the final result in Postgres.
+func (svc *bidHistoryService) annotate(ctx context.Context, bids []Bid) error {
+	type high struct {
+		bidder   string
+		amount   float64
+		finished bool
+	}
+	highs := map[string]*high{}
+	for _, b := range bids {
+		if _, ok := highs[b.AuctionID]; ok {
+			continue
+		}
+		h := &high{}
+		highs[b.AuctionID] = h
+
+		f, _ := svc.rdc.HMGet(ctx, "auc:"+b.AuctionID, "st", "hb", "hbid").Result()
+		if len(f) == 3 && f[0] == "RUNNING" {
+			h.amount, _ = strconv.ParseFloat(fmt.Sprint(f[1]), 64)
+			h.bidder = fmt.Sprint(f[2])
+			continue
+		}
+		var st string
+		err := svc.db.QueryRowContext(ctx,
+			`SELECT status, coalesce(high_bid,0)::float8, coalesce(high_bidder,'')
+			   FROM auctions WHERE id = $1`, b.AuctionID).Scan(&st, &h.amount, &h.bidder)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		h.finished = st == "FINISHED" || st == "PAID" || st == "UNPAID"
+	}
+
+	for i := range bids {
+		h := highs[bids[i].AuctionID]
+		switch {
+		case h.bidder != bids[i].BidderID || h.amount != bids[i].Amount:
+			bids[i].Status = StatusOutbid
+		case h.finished:
+			bids[i].Status = StatusWinner
+		default:
+			bids[i].Status = StatusWinning
+		}
+	}
+	return nil
+}
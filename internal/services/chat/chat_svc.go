@@ -0,0 +1,143 @@
+package chat
+
+import (
+	"auctionbidgo/internal/events"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisChatKeyPrefix     = "auc_chat:"      // capped history list
+	redisChatSlowKeyPrefix = "auc_chat_slow:" // "<id>:<user>" slow‑mode marker
+)
+
+var (
+	ErrEmpty           = errors.New("chat message is empty")
+	ErrTooLong         = errors.New("chat message too long")
+	ErrSlowMode        = errors.New("slow mode: wait before sending again")
+	ErrRejected        = errors.New("chat message rejected")
+	ErrNotModerator    = errors.New("only moderators can delete chat messages")
+	ErrMessageNotFound = errors.New("chat message not found")
+)
+
+// Config holds the room chat limits.
+type Config struct {
+	MaxLen      int           // max runes per message
+	SlowMode    time.Duration // min gap between two messages of one user (0 = off)
+	HistorySize int           // messages kept per auction and sent with the snapshot
+	Moderators  []string      // staff user IDs allowed to delete messages
+}
+
+type IChatService interface {
+	Post(ctx context.Context, auctionID, userID, text string) (int64, error)
+	Delete(ctx context.Context, auctionID string, messageID int64, moderatorID string) error
+	History(ctx context.Context, auctionID string) ([]events.ChatMessage, error)
+}
+
+type chatService struct {
+	rdc     *redis.Client
+	cfg     Config
+	filters []Filter
+}
+
+var _ IChatService = (*chatService)(nil)
+
+// NewChatService builds the room chat. Filters run in order on every
+// message; any of them may rewrite or reject it.
+func NewChatService(rdc *redis.Client, cfg Config, filters ...Filter) IChatService {
+	if cfg.MaxLen <= 0 {
+		cfg.MaxLen = 500
+	}
+	if cfg.HistorySize <= 0 {
+		cfg.HistorySize = 50
+	}
+	active := make([]Filter, 0, len(filters))
+	for _, f := range filters {
+		if f != nil {
+			active = append(active, f)
+		}
+	}
+	return &chatService{rdc: rdc, cfg: cfg, filters: active}
+}
+
+// Post validates, filters, stores and broadcasts a message (as
+// "auctions/chat" on the auction's event channel) and returns its ID.
+func (svc *chatService) Post(ctx context.Context, auctionID, userID, text string) (int64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, ErrEmpty
+	}
+	if utf8.RuneCountInString(text) > svc.cfg.MaxLen {
+		return 0, ErrTooLong
+	}
+	for _, f := range svc.filters {
+		var err error
+		if text, err = f.Filter(ctx, auctionID, userID, text); err != nil {
+			return 0, err
+		}
+	}
+
+	id, err := svc.rdc.FCall(ctx, "auction_chat_post",
+		[]string{
+			redisChatKeyPrefix + auctionID,
+			redisChatSlowKeyPrefix + auctionID + ":" + userID,
+		},
+		userID,
+		text,
+		svc.cfg.HistorySize,
+		svc.cfg.SlowMode.Milliseconds(),
+	).Int64()
+	if err != nil {
+		if strings.Contains(err.Error(), "chat_slow_mode") {
+			return 0, ErrSlowMode
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// Delete removes a message from the history and broadcasts
+// "auctions/chat_retracted".
+func (svc *chatService) Delete(ctx context.Context, auctionID string, messageID int64, moderatorID string) error {
+	if moderatorID == "" || !slices.Contains(svc.cfg.Moderators, moderatorID) {
+		return ErrNotModerator
+	}
+	err := svc.rdc.FCall(ctx, "auction_chat_delete",
+		[]string{redisChatKeyPrefix + auctionID},
+		messageID,
+		moderatorID,
+	).Err()
+	if err != nil && strings.Contains(err.Error(), "chat_message_not_found") {
+		return ErrMessageNotFound
+	}
+	return err
+}
+
+// History returns the retained messages, oldest first.
+func (svc *chatService) History(ctx context.Context, auctionID string) ([]events.ChatMessage, error) {
+	raw, err := svc.rdc.LRange(ctx, redisChatKeyPrefix+auctionID, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	return DecodeHistory(raw), nil
+}
+
+// DecodeHistory parses the JSON entries of the history list, skipping
+// anything malformed.
+func DecodeHistory(raw []string) []events.ChatMessage {
+	out := make([]events.ChatMessage, 0, len(raw))
+	for _, r := range raw {
+		var m events.ChatMessage
+		if json.Unmarshal([]byte(r), &m) == nil {
+			out = append(out, m)
+		}
+	}
+	return out
+}
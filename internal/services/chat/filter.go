@@ -0,0 +1,74 @@
+package chat
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Filter is a moderation hook run on every message before it is stored.
+// It returns the (possibly rewritten) text, or an error to reject it.
+type Filter interface {
+	Filter(ctx context.Context, auctionID, userID, text string) (string, error)
+}
+
+// FilterFunc adapts a plain function to Filter.
+type FilterFunc func(ctx context.Context, auctionID, userID, text string) (string, error)
+
+func (f FilterFunc) Filter(ctx context.Context, auctionID, userID, text string) (string, error) {
+	return f(ctx, auctionID, userID, text)
+}
+
+// blocklistFilter matches whole words, case‑insensitively. Word boundaries
+// are any non‑letter, non‑digit rune (\b is ASCII‑only, so "café" or
+// "über" would not match); RE2 has no lookaround, so the boundaries are
+// part of the match and only group 1 is the word.
+type blocklistFilter struct {
+	re     *regexp.Regexp
+	reject bool
+}
+
+// NewBlocklistFilter masks blocked words with '*' or, when reject is true,
+// refuses the message with ErrRejected. An empty list yields nil.
+func NewBlocklistFilter(words []string, reject bool) Filter {
+	quoted := make([]string, 0, len(words))
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			quoted = append(quoted, regexp.QuoteMeta(w))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	return &blocklistFilter{
+		re:     regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])(` + strings.Join(quoted, "|") + `)(?:[^\p{L}\p{N}]|$)`),
+		reject: reject,
+	}
+}
+
+func (f *blocklistFilter) Filter(_ context.Context, _, _, text string) (string, error) {
+	if !f.re.MatchString(text) {
+		return text, nil
+	}
+	if f.reject {
+		return "", ErrRejected
+	}
+	// a match consumes the separator after the word, so "bad bad" needs a
+	// second pass for the second word; '*' is itself a separator
+	for f.re.MatchString(text) {
+		var b strings.Builder
+		last := 0
+		for _, m := range f.re.FindAllStringSubmatchIndex(text, -1) {
+			b.WriteString(text[last:m[2]])
+			b.WriteString(strings.Repeat("*", utf8.RuneCountInString(text[m[2]:m[3]])))
+			last = m[3]
+		}
+		b.WriteString(text[last:])
+		if b.String() == text { // a blocked word made of '*'
+			break
+		}
+		text = b.String()
+	}
+	return text, nil
+}
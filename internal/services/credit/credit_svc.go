@@ -0,0 +1,240 @@
+package credit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Redis keys shared with auction_place_bid.
+const (
+	redisCreditKeyPrefix   = "credit:"     // hash: lim, chg
+	redisExposureKeyPrefix = "credit_exp:" // hash: auction ID → leading amount
+)
+
+var ErrNegative = errors.New("credit limit and deposit must be >= 0")
+
+// Account is a user's buying power and what is using it up. Limit and
+// Available are omitted for users bidding under an unlimited default.
+type Account struct {
+	UserID      string     `json:"user_id"`
+	CreditLimit float64    `json:"credit_limit"`
+	Deposit     float64    `json:"deposit"`             // pre‑authorised, counts towards the limit
+	Limit       *float64   `json:"limit,omitempty"`     // credit_limit + deposit (or the default)
+	Exposure    float64    `json:"exposure"`            // sum of the user's current leads
+	Charges     float64    `json:"charges"`             // won, not yet paid
+	Available   *float64   `json:"available,omitempty"` // limit − exposure − charges
+	Leading     []Lead     `json:"leading"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"` // nil = no account, default limit
+}
+
+// Lead is one auction the user is currently the high bidder of.
+type Lead struct {
+	AuctionID string  `json:"auction_id"`
+	Amount    float64 `json:"amount"`
+}
+
+type ICreditService interface {
+	Get(ctx context.Context, userID string) (*Account, error)
+	// Set creates or replaces the user's account and refreshes the cache.
+	Set(ctx context.Context, userID string, creditLimit, deposit float64) (*Account, error)
+	// Charge turns the winner's lead on an auction into an open charge.
+	// Idempotent per auction.
+	Charge(ctx context.Context, auctionID, userID string, amount float64) error
+	// Transfer moves an auction's open charge to another buyer (second‑chance
+	// offer accepted), opening one if there was none.
+	Transfer(ctx context.Context, auctionID, userID string, amount float64) error
+	// Settle closes the charge of a paid auction, freeing the credit.
+	Settle(ctx context.Context, auctionID string) error
+	// Warm loads every account into Redis (startup, or after a flush).
+	Warm(ctx context.Context) error
+}
+
+type creditService struct {
+	rdc          *redis.Client
+	db           *sql.DB
+	defaultLimit float64 // < 0 = unlimited
+}
+
+var _ ICreditService = (*creditService)(nil)
+
+func NewCreditService(rdc *redis.Client, db *sql.DB, defaultLimit float64) ICreditService {
+	return &creditService{rdc: rdc, db: db, defaultLimit: defaultLimit}
+}
+
+func (svc *creditService) Get(ctx context.Context, userID string) (*Account, error) {
+	a := &Account{UserID: userID, Leading: []Lead{}}
+	var updated time.Time
+	err := svc.db.QueryRowContext(ctx, `
+	  SELECT credit_limit::float8, deposit::float8, updated_at
+	    FROM credit_accounts WHERE user_id = $1`, userID).Scan(&a.CreditLimit, &a.Deposit, &updated)
+	switch {
+	case err == nil:
+		a.UpdatedAt = &updated
+		lim := a.CreditLimit + a.Deposit
+		a.Limit = &lim
+	case errors.Is(err, sql.ErrNoRows):
+		if svc.defaultLimit >= 0 {
+			lim := svc.defaultLimit
+			a.Limit = &lim
+		}
+	default:
+		return nil, err
+	}
+
+	if err := svc.db.QueryRowContext(ctx, `
+	  SELECT coalesce(sum(amount), 0)::float8
+	    FROM credit_charges WHERE user_id = $1 AND status = 'open'`, userID).Scan(&a.Charges); err != nil {
+		return nil, err
+	}
+	leads, err := svc.rdc.HGetAll(ctx, redisExposureKeyPrefix+userID).Result()
+	if err != nil {
+		return nil, err
+	}
+	for aid, v := range leads {
+		amt, _ := strconv.ParseFloat(v, 64)
+		a.Leading = append(a.Leading, Lead{AuctionID: aid, Amount: amt})
+		a.Exposure += amt
+	}
+	slices.SortFunc(a.Leading, func(x, y Lead) int { return strings.Compare(x.AuctionID, y.AuctionID) })
+	if a.Limit != nil {
+		avail := *a.Limit - a.Exposure - a.Charges
+		a.Available = &avail
+	}
+	return a, nil
+}
+
+func (svc *creditService) Set(ctx context.Context, userID string, creditLimit, deposit float64) (*Account, error) {
+	if creditLimit < 0 || deposit < 0 {
+		return nil, ErrNegative
+	}
+	_, err := svc.db.ExecContext(ctx, `
+	  INSERT INTO credit_accounts (user_id, credit_limit, deposit)
+	       VALUES ($1, $2, $3)
+	  ON CONFLICT (user_id) DO UPDATE
+	        SET credit_limit = EXCLUDED.credit_limit,
+	            deposit      = EXCLUDED.deposit,
+	            updated_at   = now()`, userID, creditLimit, deposit)
+	if err != nil {
+		return nil, err
+	}
+	if err := svc.sync(ctx, userID, ""); err != nil {
+		return nil, err
+	}
+	return svc.Get(ctx, userID)
+}
+
+func (svc *creditService) Charge(ctx context.Context, auctionID, userID string, amount float64) error {
+	_, err := svc.db.ExecContext(ctx, `
+	  INSERT INTO credit_charges (user_id, auction_id, amount)
+	       VALUES ($1, $2, $3)
+	  ON CONFLICT (auction_id) DO NOTHING`, userID, auctionID, amount)
+	if err != nil {
+		return err
+	}
+	// the charge and the released lead land in Redis together, so the
+	// amount is never counted twice nor dropped in between
+	return svc.sync(ctx, userID, auctionID)
+}
+
+func (svc *creditService) Transfer(ctx context.Context, auctionID, userID string, amount float64) error {
+	var prev sql.NullString
+	err := svc.db.QueryRowContext(ctx, `
+	  WITH old AS (SELECT user_id FROM credit_charges WHERE auction_id = $1 AND status = 'open'),
+	       up  AS (INSERT INTO credit_charges (user_id, auction_id, amount)
+	                    VALUES ($2, $1, $3)
+	               ON CONFLICT (auction_id) DO UPDATE
+	                     SET user_id = EXCLUDED.user_id, amount = EXCLUDED.amount,
+	                         status = 'open', settled_at = NULL)
+	  SELECT (SELECT user_id FROM old)`, auctionID, userID, amount).Scan(&prev)
+	if err != nil {
+		return err
+	}
+	if prev.Valid && prev.String != userID {
+		if err := svc.sync(ctx, prev.String, ""); err != nil {
+			return err
+		}
+	}
+	return svc.sync(ctx, userID, "")
+}
+
+func (svc *creditService) Settle(ctx context.Context, auctionID string) error {
+	var userID string
+	err := svc.db.QueryRowContext(ctx, `
+	  UPDATE credit_charges SET status = 'settled', settled_at = now()
+	   WHERE auction_id = $1 AND status = 'open'
+	  RETURNING user_id`, auctionID).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return svc.sync(ctx, userID, "")
+}
+
+// sync writes the user's limit and open charges from Postgres to Redis and,
+// when wonAuctionID is set, drops that auction from the user's leads.
+func (svc *creditService) sync(ctx context.Context, userID, wonAuctionID string) error {
+	var limit sql.NullFloat64
+	var charges float64
+	err := svc.db.QueryRowContext(ctx, `
+	  SELECT (SELECT credit_limit + deposit FROM credit_accounts WHERE user_id = $1)::float8,
+	         (SELECT coalesce(sum(amount), 0) FROM credit_charges
+	           WHERE user_id = $1 AND status = 'open')::float8`, userID).Scan(&limit, &charges)
+	if err != nil {
+		return err
+	}
+
+	key := redisCreditKeyPrefix + userID
+	_, err = svc.rdc.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HSet(ctx, key, "chg", charges)
+		if limit.Valid {
+			p.HSet(ctx, key, "lim", limit.Float64)
+		} else {
+			p.HDel(ctx, key, "lim")
+		}
+		if wonAuctionID != "" {
+			p.HDel(ctx, redisExposureKeyPrefix+userID, wonAuctionID)
+		}
+		return nil
+	})
+	return err
+}
+
+func (svc *creditService) Warm(ctx context.Context) error {
+	rows, err := svc.db.QueryContext(ctx, `
+	  SELECT user_id FROM credit_accounts
+	   UNION
+	  SELECT user_id FROM credit_charges WHERE status = 'open'`)
+	if err != nil {
+		return err
+	}
+	var users []string
+	for rows.Next() {
+		var u string
+		if err := rows.Scan(&u); err != nil {
+			rows.Close()
+			return err
+		}
+		users = append(users, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, u := range users {
+		if err := svc.sync(ctx, u, ""); err != nil {
+			return err
+		}
+	}
+	zap.L().Info("credit.warm", zap.Int("users", len(users)))
+	return nil
+}
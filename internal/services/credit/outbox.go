@@ -0,0 +1,37 @@
+package credit
+
+import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/outbox"
+	"context"
+	"encoding/json"
+)
+
+// RegisterOutboxHandlers makes the relay turn the winner's lead into a
+// charge when an auction finishes, move it to the runner‑up who accepts a
+// second‑chance offer, and settle it once the auction is paid.
+func RegisterOutboxHandlers(r *outbox.Relay, svc ICreditService) {
+	r.Handle(outbox.KindAuctionFinished, func(ctx context.Context, m outbox.Message) error {
+		var stop events.Stop
+		if err := json.Unmarshal(m.Payload, &stop); err != nil {
+			return err
+		}
+		if stop.HighBidder == "" || stop.HighBid <= 0 {
+			return nil
+		}
+		return svc.Charge(ctx, m.AggregateID, stop.HighBidder, stop.HighBid)
+	})
+	r.Handle(outbox.KindAuctionResettled, func(ctx context.Context, m outbox.Message) error {
+		var rs struct { // secondchance.Resettled
+			BuyerID string  `json:"buyer_id"`
+			Amount  float64 `json:"amount"`
+		}
+		if err := json.Unmarshal(m.Payload, &rs); err != nil {
+			return err
+		}
+		return svc.Transfer(ctx, m.AggregateID, rs.BuyerID, rs.Amount)
+	})
+	r.Handle(outbox.KindAuctionPaid, func(ctx context.Context, m outbox.Message) error {
+		return svc.Settle(ctx, m.AggregateID)
+	})
+}
@@ -0,0 +1,231 @@
+package history
+
+import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/services/auction"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// KindCreated is the history kind of the Postgres‑only draft creation; the
+// other kinds are the event names of internal/events.
+const KindCreated = "created"
+
+// Kinds that are persisted; chat and presence are not auction history.
+var recorded = map[string]bool{
+	KindCreated:         true,
+	events.NameStart:    true,
+	events.NameBid:      true,
+	events.NameExtended: true,
+	events.NameStop:     true,
+	events.NameLive:     true, // auctioneer calls, undone bids
+}
+
+var ErrNoHistory = errors.New("no history for this auction at that time")
+
+// Entry is one auction_events row.
+type Entry struct {
+	AuctionID  string          `json:"auction_id"`
+	Seq        int64           `json:"seq"`
+	Kind       string          `json:"kind"`
+	Actor      string          `json:"actor"`
+	Payload    json.RawMessage `json:"payload" swaggertype:"object"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// Page is one timeline page; NextAfterSeq is the cursor for the next page
+// (nil when this page is the last).
+type Page struct {
+	Events       []Entry `json:"events"`
+	NextAfterSeq *int64  `json:"next_after_seq,omitempty"`
+}
+
+type IHistoryService interface {
+	// Record appends entries; re‑recording an (auction, seq) is a no‑op.
+	Record(ctx context.Context, entries []Entry) error
+	// Timeline lists the entries with seq > afterSeq, oldest first.
+	Timeline(ctx context.Context, auctionID string, afterSeq int64, limit int) (*Page, error)
+	// StateAt rebuilds the auction as it was at the given instant by
+	// folding its history up to (and including) that time.
+	StateAt(ctx context.Context, auctionID string, at time.Time) (*auction.AuctionDTO, error)
+}
+
+type historyService struct {
+	db *sql.DB
+}
+
+var _ IHistoryService = (*historyService)(nil)
+
+func NewHistoryService(db *sql.DB) IHistoryService {
+	return &historyService{db: db}
+}
+
+// Recorded reports whether events of kind belong in the history.
+func Recorded(kind string) bool { return recorded[kind] }
+
+// EntryFromEvent turns a published event into a history entry.
+func EntryFromEvent(auctionID string, ev events.Event, payload []byte, at time.Time) Entry {
+	return Entry{
+		AuctionID:  auctionID,
+		Seq:        ev.Sequence(),
+		Kind:       ev.EventName(),
+		Actor:      actor(ev),
+		Payload:    payload,
+		OccurredAt: at,
+	}
+}
+
+// actor names who caused the event.
+func actor(ev events.Event) string {
+	switch e := ev.(type) {
+	case events.Start:
+		return e.SellerID
+	case events.Bid:
+		return e.Bidder
+	case events.Live:
+		return e.By
+	}
+	return "system"
+}
+
+func (svc *historyService) Record(ctx context.Context, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	const ins = `
+	  INSERT INTO auction_events (auction_id, seq, kind, actor, payload, occurred_at)
+	       VALUES ($1, $2, $3, $4, $5, $6)
+	  ON CONFLICT (auction_id, seq) DO NOTHING`
+	for _, e := range entries {
+		if _, err := tx.ExecContext(ctx, ins,
+			e.AuctionID, e.Seq, e.Kind, e.Actor, string(e.Payload), e.OccurredAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (svc *historyService) Timeline(ctx context.Context, auctionID string, afterSeq int64, limit int) (*Page, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	// fetch one extra row to know whether there is a next page
+	const q = `
+	  SELECT auction_id, seq, kind, actor, payload, occurred_at
+	    FROM auction_events
+	   WHERE auction_id = $1 AND seq > $2
+	ORDER BY seq
+	   LIMIT $3`
+	rows, err := svc.db.QueryContext(ctx, q, auctionID, afterSeq, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out, err := scanEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+	p := &Page{Events: out}
+	if len(out) > limit {
+		p.Events = out[:limit]
+		next := p.Events[limit-1].Seq
+		p.NextAfterSeq = &next
+	}
+	return p, nil
+}
+
+func (svc *historyService) StateAt(ctx context.Context, auctionID string, at time.Time) (*auction.AuctionDTO, error) {
+	const q = `
+	  SELECT auction_id, seq, kind, actor, payload, occurred_at
+	    FROM auction_events
+	   WHERE auction_id = $1 AND occurred_at <= $2
+	ORDER BY seq`
+	rows, err := svc.db.QueryContext(ctx, q, auctionID, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries, err := scanEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrNoHistory
+	}
+	return Fold(auctionID, entries)
+}
+
+func scanEntries(rows *sql.Rows) ([]Entry, error) {
+	out := []Entry{}
+	for rows.Next() {
+		var e Entry
+		var payload []byte
+		if err := rows.Scan(&e.AuctionID, &e.Seq, &e.Kind, &e.Actor, &payload, &e.OccurredAt); err != nil {
+			return nil, err
+		}
+		e.Payload = payload
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// Fold applies entries (in seq order) to an empty auction and returns the
+// resulting state.
+func Fold(auctionID string, entries []Entry) (*auction.AuctionDTO, error) {
+	dto := &auction.AuctionDTO{ID: auctionID}
+	for _, e := range entries {
+		if err := apply(dto, e); err != nil {
+			return nil, fmt.Errorf("auction %s seq %d: %w", auctionID, e.Seq, err)
+		}
+	}
+	return dto, nil
+}
+
+func apply(dto *auction.AuctionDTO, e Entry) error {
+	unix := func(s int64) time.Time { return time.Unix(s, 0).UTC() }
+
+	if e.Kind == KindCreated {
+		var p auction.CreatedPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return err
+		}
+		dto.SellerID, dto.EndsAt, dto.Status = p.SellerID, p.EndsAt, "PENDING"
+		dto.StartsAt = e.OccurredAt
+		return nil
+	}
+
+	ev, err := events.Decode(e.Payload)
+	if err != nil {
+		return err
+	}
+	switch v := ev.(type) {
+	case events.Start:
+		dto.SellerID = v.SellerID
+		dto.StartsAt, dto.EndsAt = unix(v.StartsAt), unix(v.EndsAt)
+		dto.Status = "RUNNING"
+	case events.Bid:
+		dto.HighBid, dto.HighBidder = v.Amount, v.Bidder
+	case events.Extended:
+		dto.EndsAt = unix(v.EndsAt)
+	case events.Live: // undo and pass change the high bid
+		dto.HighBid, dto.HighBidder = v.HighBid, v.HighBidder
+	case events.Stop:
+		dto.SellerID = v.SellerID
+		dto.StartsAt, dto.EndsAt = unix(v.StartsAt), unix(v.EndsAt)
+		dto.HighBid, dto.HighBidder = v.HighBid, v.HighBidder
+		dto.Status = v.Status
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package history
+
+import (
+	"auctionbidgo/internal/outbox"
+	"auctionbidgo/internal/services/auction"
+	"context"
+	"encoding/json"
+)
+
+// RegisterOutboxHandlers records draft creation – which never passes
+// through Redis – as the auction's first history entry (seq 0).
+func RegisterOutboxHandlers(r *outbox.Relay, svc IHistoryService) {
+	r.Handle(outbox.KindAuctionCreated, func(ctx context.Context, m outbox.Message) error {
+		var p auction.CreatedPayload
+		if err := json.Unmarshal(m.Payload, &p); err != nil {
+			return err
+		}
+		return svc.Record(ctx, []Entry{{
+			AuctionID:  m.AggregateID,
+			Seq:        0,
+			Kind:       KindCreated,
+			Actor:      p.SellerID,
+			Payload:    m.Payload,
+			OccurredAt: m.CreatedAt,
+		}})
+	})
+}
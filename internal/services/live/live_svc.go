@@ -0,0 +1,145 @@
+// Package live runs live auctions: instead of a timer, an auctioneer drives
+// the close over WS – open the lot, set the asking price, going once, going
+// twice, sold or pass – while clerks enter floor and phone bids on bidders'
+// behalf and can undo the last bid. The state machine is the auction_live
+// Redis function; every action is published as "auctions/live".
+package live
+
+import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/services/auction"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Bid sources a clerk can enter.
+const (
+	SourceFloor = "floor" // bidder in the room
+	SourcePhone = "phone"
+)
+
+var (
+	ErrNotAuctioneer = errors.New("only auctioneers can call a live auction")
+	ErrNotClerk      = errors.New("only clerks can enter floor bids or undo bids")
+	ErrUnknownAction = errors.New("unknown live action")
+	ErrBadPhase      = errors.New("action not allowed in the current phase")
+	ErrAskTooLow     = errors.New("asking price must be above the current bid")
+	ErrNoBids        = errors.New("no bid to sell to; pass the lot instead")
+	ErrNothingToUndo = errors.New("no bid to undo")
+	ErrBadSource     = errors.New("source must be floor or phone")
+)
+
+// Config names the staff of live auctions.
+type Config struct {
+	Auctioneers []string // staff user IDs allowed to call the auctions (and act as clerks)
+	Clerks      []string // staff user IDs allowed to enter floor / phone bids and undo
+}
+
+type ILiveService interface {
+	// Call performs an auctioneer action: one of events.LiveOpen,
+	// LiveAsk, LiveGoingOnce, LiveGoingTwice, LiveSold, LivePass. ask is the
+	// asking price of LiveAsk (optional for LiveOpen). Sold and pass
+	// finalise the auction.
+	Call(ctx context.Context, auctionID, auctioneerID, action string, ask float64) error
+	// Bid enters a floor or phone bid for bidderID.
+	Bid(ctx context.Context, auctionID, clerkID, bidderID string, amount float64, source string) error
+	// Undo takes back the last bid and restores the one before it.
+	Undo(ctx context.Context, auctionID, clerkID string) error
+}
+
+type liveService struct {
+	rdc      *redis.Client
+	auctions auction.IAuctionService
+	cfg      Config
+}
+
+var _ ILiveService = (*liveService)(nil)
+
+func NewLiveService(rdc *redis.Client, auctions auction.IAuctionService, cfg Config) ILiveService {
+	return &liveService{rdc: rdc, auctions: auctions, cfg: cfg}
+}
+
+// auctioneer and clerk get the caller's verified staff ID (internal/http/
+// staffauth); "" – no staff token – is never a role.
+func (svc *liveService) auctioneer(userID string) bool {
+	return userID != "" && slices.Contains(svc.cfg.Auctioneers, userID)
+}
+
+func (svc *liveService) clerk(userID string) bool {
+	return svc.auctioneer(userID) || userID != "" && slices.Contains(svc.cfg.Clerks, userID)
+}
+
+func (svc *liveService) Call(ctx context.Context, auctionID, auctioneerID, action string, ask float64) error {
+	if !svc.auctioneer(auctioneerID) {
+		return ErrNotAuctioneer
+	}
+	switch action {
+	case events.LiveOpen, events.LiveAsk, events.LiveGoingOnce, events.LiveGoingTwice,
+		events.LiveSold, events.LivePass:
+	default:
+		return ErrUnknownAction
+	}
+	if err := svc.act(ctx, auctionID, auctioneerID, action, ask); err != nil {
+		return err
+	}
+	if action == events.LiveSold || action == events.LivePass {
+		// the timer is gone already; the orphan sweep retries if this fails
+		return svc.auctions.Finalize(ctx, auctionID)
+	}
+	return nil
+}
+
+func (svc *liveService) Bid(ctx context.Context, auctionID, clerkID, bidderID string, amount float64, source string) error {
+	if !svc.clerk(clerkID) {
+		return ErrNotClerk
+	}
+	if source != SourceFloor && source != SourcePhone {
+		return ErrBadSource
+	}
+	return svc.auctions.PlaceClerkBid(ctx, auctionID, bidderID, amount, source, clerkID)
+}
+
+func (svc *liveService) Undo(ctx context.Context, auctionID, clerkID string) error {
+	if !svc.clerk(clerkID) {
+		return ErrNotClerk
+	}
+	return svc.act(ctx, auctionID, clerkID, events.LiveUndo, 0)
+}
+
+func (svc *liveService) act(ctx context.Context, auctionID, actorID, action string, amount float64) error {
+	ctx, cancel := context.WithTimeout(ctx, 1500*time.Millisecond)
+	defer cancel()
+
+	err := svc.rdc.FCall(ctx, "auction_live",
+		[]string{
+			"auc:" + auctionID,
+			"auc_t:" + auctionID,
+			"auc_live_bids:" + auctionID,
+		},
+		action,
+		actorID,
+		amount,
+	).Err()
+	if err == nil {
+		return nil
+	}
+	for code, mapped := range map[string]error{
+		"auction_closed":    auction.ErrAuctionClosed,
+		"not_live":          auction.ErrNotLive,
+		"bad_phase":         ErrBadPhase,
+		"ask_below_current": ErrAskTooLow,
+		"no_bids":           ErrNoBids,
+		"nothing_to_undo":   ErrNothingToUndo,
+		"unknown_action":    ErrUnknownAction,
+	} {
+		if strings.Contains(err.Error(), code) {
+			return mapped
+		}
+	}
+	return err
+}
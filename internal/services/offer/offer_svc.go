@@ -0,0 +1,552 @@
+// Package offer implements make‑an‑offer: private offers on best‑offer
+// listings (before they start) and on auctions that finished unsold. An
+// offer is a small state machine persisted in Postgres:
+//
+//	submit ─▶ pending ──counter──▶ countered ──counter──▶ pending …
+//	          pending / countered ──accept──▶ accepted  (sold, settled)
+//	          pending / countered ──decline─▶ declined
+//	          pending / countered ──timeout─▶ expired
+//
+// pending waits for the seller, countered for the buyer. The seller's
+// thresholds accept or decline a buyer's amount automatically.
+package offer
+
+import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/notify"
+	"auctionbidgo/internal/outbox"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Offer statuses.
+const (
+	StatusPending   = "pending"   // the seller's turn
+	StatusCountered = "countered" // the buyer's turn
+	StatusAccepted  = "accepted"
+	StatusDeclined  = "declined"
+	StatusExpired   = "expired"
+)
+
+// Event actions.
+const (
+	ActionSubmit      = "submit"
+	ActionCounter     = "counter"
+	ActionAccept      = "accept"
+	ActionDecline     = "decline"
+	ActionExpire      = "expire"
+	ActionAutoAccept  = "auto_accept"
+	ActionAutoDecline = "auto_decline"
+	ActionSuperseded  = "superseded" // another offer on the auction was accepted
+	ActionRelisted    = "relisted"   // the unsold auction was relisted (internal/services/relist)
+)
+
+const actorSystem = "system"
+
+var (
+	ErrAuctionNotFound = errors.New("auction not found")
+	ErrNotFound        = errors.New("offer not found")
+	ErrNotOpen         = errors.New("auction does not take offers")
+	ErrOwnAuction      = errors.New("sellers cannot make offers on their own auction")
+	ErrOfferExists     = errors.New("you already have an open offer on this auction")
+	ErrNotYourTurn     = errors.New("waiting for the other party")
+	ErrForbidden       = errors.New("not a party to this offer")
+	ErrClosed          = errors.New("offer is closed")
+	ErrBadAmount       = errors.New("amount must be > 0")
+	ErrBadThresholds   = errors.New("auto_decline must not exceed auto_accept")
+)
+
+// Settings are the seller's offer options of an auction. Thresholds are
+// private to the seller.
+type Settings struct {
+	AuctionID   string   `json:"auction_id"`
+	BestOffer   bool     `json:"best_offer"`             // take offers before the auction starts
+	AutoAccept  *float64 `json:"auto_accept,omitempty"`  // buyer amounts >= this are accepted
+	AutoDecline *float64 `json:"auto_decline,omitempty"` // buyer amounts < this are declined
+}
+
+// Event is one transition of an offer.
+type Event struct {
+	Action string    `json:"action" enums:"submit,counter,accept,decline,expire,auto_accept,auto_decline,superseded,relisted"`
+	Actor  string    `json:"actor"`
+	Amount *float64  `json:"amount,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+type Offer struct {
+	ID        int64     `json:"id"`
+	AuctionID string    `json:"auction_id"`
+	SellerID  string    `json:"seller_id"`
+	BuyerID   string    `json:"buyer_id"`
+	Amount    float64   `json:"amount"`
+	Status    string    `json:"status" enums:"pending,countered,accepted,declined,expired"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	History   []Event   `json:"history,omitempty"`
+}
+
+type IOfferService interface {
+	// Configure sets the best‑offer flag and thresholds; seller only.
+	Configure(ctx context.Context, sellerID string, s Settings) (*Settings, error)
+	Submit(ctx context.Context, auctionID, buyerID string, amount float64) (*Offer, error)
+	// Counter, Accept and Decline are made by the party whose turn it is.
+	Counter(ctx context.Context, id int64, actorID string, amount float64) (*Offer, error)
+	Accept(ctx context.Context, id int64, actorID string) (*Offer, error)
+	Decline(ctx context.Context, id int64, actorID string) (*Offer, error)
+	// Get returns the offer with its history to its buyer or seller.
+	Get(ctx context.Context, id int64, viewerID string) (*Offer, error)
+	ListForAuction(ctx context.Context, auctionID, sellerID string) ([]Offer, error)
+	ListForBuyer(ctx context.Context, buyerID string) ([]Offer, error)
+	// RunExpiry expires offers nobody answered, every interval until ctx is done.
+	RunExpiry(ctx context.Context, every time.Duration)
+}
+
+type offerService struct {
+	db       *sql.DB
+	notifier *notify.Dispatcher
+	relay    *outbox.Relay
+	ttl      time.Duration
+}
+
+var _ IOfferService = (*offerService)(nil)
+
+// NewOfferService: ttl is how long each side has to answer.
+func NewOfferService(db *sql.DB, notifier *notify.Dispatcher, relay *outbox.Relay, ttl time.Duration) IOfferService {
+	if ttl <= 0 {
+		ttl = 48 * time.Hour
+	}
+	return &offerService{db: db, notifier: notifier, relay: relay, ttl: ttl}
+}
+
+// listing is the auction row as far as offers care.
+type listing struct {
+	sellerID   string
+	status     string
+	highBidder string
+	startsAt   time.Time
+	endsAt     time.Time
+	relisted   bool // unsold and relisted: offers go to the successor
+	Settings
+}
+
+// open tells whether the listing takes offers: a best‑offer listing that has
+// not started, or an auction that finished without a winner and was not
+// relisted.
+func (l listing) open() bool {
+	return (l.status == "PENDING" && l.BestOffer) ||
+		(l.status == "FINISHED" && l.highBidder == "" && !l.relisted)
+}
+
+func lockListing(ctx context.Context, tx *sql.Tx, auctionID string) (*listing, error) {
+	l := listing{Settings: Settings{AuctionID: auctionID}}
+	err := tx.QueryRowContext(ctx, `
+	  SELECT seller_id, status, coalesce(high_bidder, ''), starts_at, ends_at,
+	         best_offer, offer_auto_accept::float8, offer_auto_decline::float8,
+	         EXISTS (SELECT 1 FROM auctions n WHERE n.relisted_from = a.id)
+	    FROM auctions a WHERE id = $1 FOR UPDATE OF a`, auctionID).Scan(&l.sellerID, &l.status, &l.highBidder,
+		&l.startsAt, &l.endsAt, &l.BestOffer, &l.AutoAccept, &l.AutoDecline, &l.relisted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAuctionNotFound
+	}
+	return &l, err
+}
+
+func (svc *offerService) Configure(ctx context.Context, sellerID string, s Settings) (*Settings, error) {
+	if s.AutoAccept != nil && s.AutoDecline != nil && *s.AutoDecline > *s.AutoAccept {
+		return nil, ErrBadThresholds
+	}
+	res, err := svc.db.ExecContext(ctx, `
+	  UPDATE auctions SET best_offer = $3, offer_auto_accept = $4, offer_auto_decline = $5
+	   WHERE id = $1 AND seller_id = $2`, s.AuctionID, sellerID, s.BestOffer, s.AutoAccept, s.AutoDecline)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		var exists bool
+		if err := svc.db.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM auctions WHERE id = $1)`, s.AuctionID).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrForbidden
+		}
+		return nil, ErrAuctionNotFound
+	}
+	return &s, nil
+}
+
+func (svc *offerService) Submit(ctx context.Context, auctionID, buyerID string, amount float64) (*Offer, error) {
+	if amount <= 0 {
+		return nil, ErrBadAmount
+	}
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	l, err := lockListing(ctx, tx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if !l.open() {
+		return nil, ErrNotOpen
+	}
+	if buyerID == l.sellerID {
+		return nil, ErrOwnAuction
+	}
+	var exists bool
+	if err = tx.QueryRowContext(ctx, `
+	  SELECT EXISTS (SELECT 1 FROM offers WHERE auction_id = $1 AND buyer_id = $2
+	                                        AND status IN ('pending', 'countered'))`,
+		auctionID, buyerID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrOfferExists
+	}
+
+	o := Offer{AuctionID: auctionID, SellerID: l.sellerID, BuyerID: buyerID, Amount: amount, Status: StatusPending}
+	if err = tx.QueryRowContext(ctx, `
+	  INSERT INTO offers (auction_id, seller_id, buyer_id, amount, expires_at)
+	       VALUES ($1, $2, $3, $4, now() + make_interval(secs => $5))
+	    RETURNING id, expires_at, created_at, updated_at`,
+		auctionID, l.sellerID, buyerID, amount, svc.ttl.Seconds()).Scan(&o.ID, &o.ExpiresAt, &o.CreatedAt, &o.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err = addEvent(ctx, tx, o.ID, ActionSubmit, buyerID, &amount); err != nil {
+		return nil, err
+	}
+	return svc.buyerMove(ctx, tx, l, &o, ActionSubmit)
+}
+
+func (svc *offerService) Counter(ctx context.Context, id int64, actorID string, amount float64) (*Offer, error) {
+	if amount <= 0 {
+		return nil, ErrBadAmount
+	}
+	tx, l, o, err := svc.turn(ctx, id, actorID)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	next := StatusCountered
+	if actorID == o.BuyerID {
+		next = StatusPending
+	}
+	if err = tx.QueryRowContext(ctx, `
+	  UPDATE offers SET amount = $2, status = $3, updated_at = now(),
+	                    expires_at = now() + make_interval(secs => $4)
+	   WHERE id = $1
+	  RETURNING expires_at, updated_at`, id, amount, next, svc.ttl.Seconds()).Scan(&o.ExpiresAt, &o.UpdatedAt); err != nil {
+		return nil, err
+	}
+	o.Amount, o.Status = amount, next
+	if err = addEvent(ctx, tx, id, ActionCounter, actorID, &amount); err != nil {
+		return nil, err
+	}
+	if actorID == o.BuyerID {
+		return svc.buyerMove(ctx, tx, l, o, ActionCounter)
+	}
+	return svc.commit(ctx, tx, o, ActionCounter)
+}
+
+// buyerMove applies the seller's thresholds to the buyer's amount on the
+// table and commits.
+func (svc *offerService) buyerMove(ctx context.Context, tx *sql.Tx, l *listing, o *Offer, action string) (*Offer, error) {
+	switch {
+	case l.AutoAccept != nil && o.Amount >= *l.AutoAccept:
+		if err := svc.accept(ctx, tx, l, o, ActionAutoAccept, actorSystem); err != nil {
+			return nil, err
+		}
+		return svc.commit(ctx, tx, o, ActionAutoAccept)
+	case l.AutoDecline != nil && o.Amount < *l.AutoDecline:
+		if err := svc.setStatus(ctx, tx, o, StatusDeclined, ActionAutoDecline, actorSystem); err != nil {
+			return nil, err
+		}
+		return svc.commit(ctx, tx, o, ActionAutoDecline)
+	}
+	return svc.commit(ctx, tx, o, action)
+}
+
+func (svc *offerService) Accept(ctx context.Context, id int64, actorID string) (*Offer, error) {
+	tx, l, o, err := svc.turn(ctx, id, actorID)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if err := svc.accept(ctx, tx, l, o, ActionAccept, actorID); err != nil {
+		return nil, err
+	}
+	return svc.commit(ctx, tx, o, ActionAccept)
+}
+
+func (svc *offerService) Decline(ctx context.Context, id int64, actorID string) (*Offer, error) {
+	tx, _, o, err := svc.turn(ctx, id, actorID)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if err := svc.setStatus(ctx, tx, o, StatusDeclined, ActionDecline, actorID); err != nil {
+		return nil, err
+	}
+	return svc.commit(ctx, tx, o, ActionDecline)
+}
+
+// turn opens a transaction with the auction and the offer locked (in that
+// order, like Submit) and checks that it is actorID's move.
+func (svc *offerService) turn(ctx context.Context, id int64, actorID string) (*sql.Tx, *listing, *Offer, error) {
+	var auctionID string
+	err := svc.db.QueryRowContext(ctx, `SELECT auction_id FROM offers WHERE id = $1`, id).Scan(&auctionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	fail := func(err error) (*sql.Tx, *listing, *Offer, error) {
+		tx.Rollback()
+		return nil, nil, nil, err
+	}
+	l, err := lockListing(ctx, tx, auctionID)
+	if err != nil {
+		return fail(err)
+	}
+	o, err := scanOffer(tx.QueryRowContext(ctx, selectOffer+` WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		return fail(err)
+	}
+	switch {
+	case actorID != o.BuyerID && actorID != o.SellerID:
+		return fail(ErrForbidden)
+	case o.Status != StatusPending && o.Status != StatusCountered, !o.ExpiresAt.After(time.Now()):
+		return fail(ErrClosed)
+	case (o.Status == StatusPending) != (actorID == o.SellerID):
+		return fail(ErrNotYourTurn)
+	}
+	return tx, l, o, nil
+}
+
+// accept sells the item to the offer's buyer: the auction is finished with
+// the buyer as winner and an auction.finished outbox row is written, so the
+// relay settles, charges and mails exactly as for a won auction. The other
+// live offers on the auction are superseded.
+func (svc *offerService) accept(ctx context.Context, tx *sql.Tx, l *listing, o *Offer, action, actor string) error {
+	if !l.open() {
+		return ErrNotOpen // sold meanwhile, or the auction was started
+	}
+	if err := svc.setStatus(ctx, tx, o, StatusAccepted, action, actor); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+	  WITH closed AS (
+	    UPDATE offers SET status = 'declined', updated_at = now()
+	     WHERE auction_id = $1 AND id <> $2 AND status IN ('pending', 'countered')
+	    RETURNING id)
+	  INSERT INTO offer_events (offer_id, action, actor)
+	  SELECT id, 'superseded', 'system' FROM closed`, o.AuctionID, o.ID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+	  UPDATE auctions SET status = 'FINISHED', high_bid = $2, high_bidder = $3
+	   WHERE id = $1`, o.AuctionID, o.Amount, o.BuyerID); err != nil {
+		return err
+	}
+	return outbox.Write(ctx, tx, outbox.KindAuctionFinished, o.AuctionID, events.Stop{
+		Header:     events.Header{Version: events.Version},
+		SellerID:   l.sellerID,
+		Status:     "FINISHED",
+		StartsAt:   l.startsAt.Unix(),
+		EndsAt:     l.endsAt.Unix(),
+		HighBid:    o.Amount,
+		HighBidder: o.BuyerID,
+	})
+}
+
+func (svc *offerService) setStatus(ctx context.Context, tx *sql.Tx, o *Offer, status, action, actor string) error {
+	if err := tx.QueryRowContext(ctx, `
+	  UPDATE offers SET status = $2, updated_at = now() WHERE id = $1
+	  RETURNING updated_at`, o.ID, status).Scan(&o.UpdatedAt); err != nil {
+		return err
+	}
+	o.Status = status
+	return addEvent(ctx, tx, o.ID, action, actor, nil)
+}
+
+func addEvent(ctx context.Context, tx *sql.Tx, offerID int64, action, actor string, amount *float64) error {
+	_, err := tx.ExecContext(ctx, `
+	  INSERT INTO offer_events (offer_id, action, actor, amount)
+	       VALUES ($1, $2, $3, $4)`, offerID, action, actor, amount)
+	return err
+}
+
+// commit commits tx and tells both parties what happened.
+func (svc *offerService) commit(ctx context.Context, tx *sql.Tx, o *Offer, action string) (*Offer, error) {
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	if o.Status == StatusAccepted {
+		svc.relay.Wake()
+	}
+	svc.notify(ctx, o, action)
+	return o, nil
+}
+
+func (svc *offerService) notify(ctx context.Context, o *Offer, action string) {
+	msg := fmt.Sprintf("Offer %d on auction %s: %s (%.2f, %s)", o.ID, o.AuctionID, action, o.Amount, o.Status)
+	for _, user := range []string{o.SellerID, o.BuyerID} {
+		svc.notifier.Dispatch(ctx, notify.Notification{
+			// one per transition and party
+			Key:       "offer:" + strconv.FormatInt(o.ID, 10) + ":" + o.UpdatedAt.Format(time.RFC3339Nano) + ":" + user,
+			UserID:    user,
+			Kind:      notify.KindOffer,
+			AuctionID: o.AuctionID,
+			Message:   msg,
+			Data:      o,
+			CreatedAt: o.UpdatedAt.UTC(),
+		})
+	}
+}
+
+const selectOffer = `
+  SELECT id, auction_id, seller_id, buyer_id, amount::float8, status, expires_at, created_at, updated_at
+    FROM offers`
+
+func scanOffer(row interface{ Scan(...any) error }) (*Offer, error) {
+	var o Offer
+	err := row.Scan(&o.ID, &o.AuctionID, &o.SellerID, &o.BuyerID, &o.Amount, &o.Status,
+		&o.ExpiresAt, &o.CreatedAt, &o.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return &o, err
+}
+
+func (svc *offerService) Get(ctx context.Context, id int64, viewerID string) (*Offer, error) {
+	o, err := scanOffer(svc.db.QueryRowContext(ctx, selectOffer+` WHERE id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+	if viewerID != o.BuyerID && viewerID != o.SellerID {
+		return nil, ErrForbidden
+	}
+	rows, err := svc.db.QueryContext(ctx, `
+	  SELECT action, actor, amount::float8, at FROM offer_events WHERE offer_id = $1 ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	o.History = []Event{}
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(&e.Action, &e.Actor, &e.Amount, &e.At); err != nil {
+			return nil, err
+		}
+		o.History = append(o.History, e)
+	}
+	return o, rows.Err()
+}
+
+func (svc *offerService) ListForAuction(ctx context.Context, auctionID, sellerID string) ([]Offer, error) {
+	var seller string
+	err := svc.db.QueryRowContext(ctx, `SELECT seller_id FROM auctions WHERE id = $1`, auctionID).Scan(&seller)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAuctionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if seller != sellerID {
+		return nil, ErrForbidden
+	}
+	return svc.list(ctx, selectOffer+` WHERE auction_id = $1 ORDER BY id DESC`, auctionID)
+}
+
+func (svc *offerService) ListForBuyer(ctx context.Context, buyerID string) ([]Offer, error) {
+	return svc.list(ctx, selectOffer+` WHERE buyer_id = $1 ORDER BY created_at DESC, id DESC`, buyerID)
+}
+
+func (svc *offerService) list(ctx context.Context, q string, args ...any) ([]Offer, error) {
+	rows, err := svc.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Offer{}
+	for rows.Next() {
+		o, err := scanOffer(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *o)
+	}
+	return out, rows.Err()
+}
+
+func (svc *offerService) RunExpiry(ctx context.Context, every time.Duration) {
+	tk := time.NewTicker(every)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+		}
+		if err := svc.expire(ctx); err != nil {
+			zap.L().Warn("offer.expiry", zap.Error(err))
+		}
+	}
+}
+
+func (svc *offerService) expire(ctx context.Context) error {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+	  UPDATE offers SET status = 'expired', updated_at = now()
+	   WHERE status IN ('pending', 'countered') AND expires_at <= now()
+	  RETURNING id, auction_id, seller_id, buyer_id, amount::float8, status, expires_at, created_at, updated_at`)
+	if err != nil {
+		return err
+	}
+	var expired []*Offer
+	for rows.Next() {
+		o, err := scanOffer(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		expired = append(expired, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, o := range expired {
+		if err := addEvent(ctx, tx, o.ID, ActionExpire, actorSystem, nil); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, o := range expired {
+		svc.notify(ctx, o, ActionExpire)
+	}
+	return nil
+}
@@ -0,0 +1,380 @@
+package payment
+
+import (
+	"auctionbidgo/internal/outbox"
+	"auctionbidgo/internal/payments"
+	"auctionbidgo/internal/services/settlement"
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Payment statuses.
+const (
+	StatusPending    = "pending"    // intent created, buyer has not paid yet
+	StatusAuthorized = "authorized" // buyer paid, capture pending
+	StatusCaptured   = "captured"
+	StatusFailed     = "failed"
+	StatusRefunded   = "refunded"
+)
+
+var (
+	ErrNotFound      = errors.New("payment not found")
+	ErrNotPayable    = errors.New("only issued buyer invoices can be paid")
+	ErrNotRefundable = errors.New("payment is not captured or already refunded")
+)
+
+// Payment is one attempt to pay an invoice.
+type Payment struct {
+	ID          int64     `json:"id"`
+	InvoiceID   int64     `json:"invoice_id"`
+	Provider    string    `json:"provider"`
+	IntentID    string    `json:"intent_id"`
+	Amount      float64   `json:"amount"`
+	Refunded    float64   `json:"refunded"`
+	Status      string    `json:"status" enums:"pending,authorized,captured,failed,refunded"`
+	CheckoutURL string    `json:"checkout_url"` // send the buyer here
+	LastError   string    `json:"last_error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PaidPayload is the outbox payload of auction.paid and auction.unpaid.
+type PaidPayload struct {
+	AuctionID string  `json:"auction_id"`
+	InvoiceID int64   `json:"invoice_id"`
+	BuyerID   string  `json:"buyer_id"`
+	Amount    float64 `json:"amount"`
+}
+
+type IPaymentService interface {
+	// Pay opens (or returns the open) payment of a buyer invoice.
+	Pay(ctx context.Context, invoiceID int64) (*Payment, error)
+	Get(ctx context.Context, id int64) (*Payment, error)
+	// Refund refunds amount of a captured payment; 0 = what is left.
+	Refund(ctx context.Context, id int64, amount float64) (*Payment, error)
+	// HandleWebhook applies a provider notification.
+	HandleWebhook(ctx context.Context, header http.Header, body []byte) error
+	// RunDeadlines marks auctions whose buyer invoice is overdue as UNPAID,
+	// every interval until ctx is done.
+	RunDeadlines(ctx context.Context, every time.Duration)
+}
+
+type paymentService struct {
+	db       *sql.DB
+	provider payments.PaymentProvider
+	currency string
+	relay    *outbox.Relay
+}
+
+var _ IPaymentService = (*paymentService)(nil)
+
+func NewPaymentService(db *sql.DB, provider payments.PaymentProvider, currency string, relay *outbox.Relay) IPaymentService {
+	return &paymentService{db: db, provider: provider, currency: currency, relay: relay}
+}
+
+const selectPayment = `
+  SELECT id, invoice_id, provider, intent_id, amount::float8, refunded::float8, status,
+         checkout_url, coalesce(last_error, ''), created_at, updated_at
+    FROM payments`
+
+func scanPayment(row interface{ Scan(...any) error }) (*Payment, error) {
+	var p Payment
+	err := row.Scan(&p.ID, &p.InvoiceID, &p.Provider, &p.IntentID, &p.Amount, &p.Refunded,
+		&p.Status, &p.CheckoutURL, &p.LastError, &p.CreatedAt, &p.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return &p, err
+}
+
+func (svc *paymentService) Get(ctx context.Context, id int64) (*Payment, error) {
+	return scanPayment(svc.db.QueryRowContext(ctx, selectPayment+` WHERE id = $1`, id))
+}
+
+// Pay holds the invoice row lock from the open‑payment check to the insert,
+// so concurrent calls cannot each open an intent at the provider.
+func (svc *paymentService) Pay(ctx context.Context, invoiceID int64) (*Payment, error) {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var party, status, number string
+	var total float64
+	err = tx.QueryRowContext(ctx,
+		`SELECT party, status, number, total::float8 FROM invoices WHERE id = $1 FOR UPDATE`, invoiceID).
+		Scan(&party, &status, &number, &total)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, settlement.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if party != settlement.PartyBuyer || status != settlement.StatusIssued {
+		return nil, ErrNotPayable
+	}
+
+	// a buyer reopening the checkout gets the same intent
+	open, err := scanPayment(tx.QueryRowContext(ctx, selectPayment+`
+	   WHERE invoice_id = $1 AND status IN ('pending', 'authorized')
+	ORDER BY id DESC LIMIT 1`, invoiceID))
+	if err == nil {
+		return open, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	in, err := svc.provider.CreateIntent(ctx, payments.IntentRequest{
+		Reference: number,
+		Amount:    total,
+		Currency:  svc.currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+	const ins = `
+	  INSERT INTO payments (invoice_id, provider, intent_id, amount, checkout_url)
+	       VALUES ($1, $2, $3, $4, $5)
+	    RETURNING id`
+	var id int64
+	if err := tx.QueryRowContext(ctx, ins,
+		invoiceID, svc.provider.Name(), in.ID, total, in.CheckoutURL).Scan(&id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return svc.Get(ctx, id)
+}
+
+// Refund holds the payment row lock from reading what is left to recording
+// the refund, so concurrent refunds cannot both refund the same amount.
+func (svc *paymentService) Refund(ctx context.Context, id int64, amount float64) (*Payment, error) {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	p, err := scanPayment(tx.QueryRowContext(ctx, selectPayment+` WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		return nil, err
+	}
+	left := math.Round((p.Amount-p.Refunded)*100) / 100 // cents, like the invoice totals
+	if p.Status != StatusCaptured || left <= 0 {
+		return nil, ErrNotRefundable
+	}
+	if amount <= 0 || amount > left {
+		amount = left
+	}
+	if _, err := svc.provider.Refund(ctx, p.IntentID, amount); err != nil {
+		return nil, err
+	}
+	res, err := tx.ExecContext(ctx, `
+	  UPDATE payments
+	     SET refunded = refunded + $2,
+	         status = CASE WHEN refunded + $2 >= amount THEN 'refunded' ELSE status END,
+	         updated_at = now()
+	   WHERE id = $1 AND refunded + $2 <= amount`, id, amount)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, ErrNotRefundable
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return svc.Get(ctx, id)
+}
+
+func (svc *paymentService) HandleWebhook(ctx context.Context, header http.Header, body []byte) error {
+	ev, err := svc.provider.VerifyWebhook(header, body)
+	if err != nil {
+		return err
+	}
+	p, err := scanPayment(svc.db.QueryRowContext(ctx, selectPayment+` WHERE intent_id = $1`, ev.Intent.ID))
+	if err != nil {
+		return err
+	}
+
+	switch ev.Type {
+	case payments.EventIntentAuthorized:
+		if _, err := svc.db.ExecContext(ctx, `
+		  UPDATE payments SET status = 'authorized', updated_at = now()
+		   WHERE id = $1 AND status = 'pending'`, p.ID); err != nil {
+			return err
+		}
+		in, err := svc.provider.Capture(ctx, p.IntentID)
+		if err != nil {
+			_, _ = svc.db.ExecContext(ctx,
+				`UPDATE payments SET last_error = $2, updated_at = now() WHERE id = $1`, p.ID, err.Error())
+			return err
+		}
+		if in.Status == payments.IntentSucceeded {
+			return svc.captured(ctx, p)
+		}
+	case payments.EventIntentSucceeded:
+		return svc.captured(ctx, p)
+	case payments.EventIntentFailed:
+		_, err := svc.db.ExecContext(ctx, `
+		  UPDATE payments SET status = 'failed', updated_at = now()
+		   WHERE id = $1 AND status IN ('pending', 'authorized')`, p.ID)
+		return err
+	}
+	return nil
+}
+
+// captured records a successful capture: payment captured, invoice paid and
+// the auction PAID, with an auction.paid outbox row – all or nothing, and a
+// no‑op when the capture was already recorded. The auction row is locked
+// and its high bidder re‑checked, so a payment racing a second‑chance
+// Accept (which hands the item to another bidder) cannot settle the sale
+// for the previous winner.
+func (svc *paymentService) captured(ctx context.Context, p *Payment) error {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+	  UPDATE payments SET status = 'captured', last_error = NULL, updated_at = now()
+	   WHERE id = $1 AND status IN ('pending', 'authorized')`, p.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil
+	}
+
+	var pl PaidPayload
+	err = tx.QueryRowContext(ctx, `
+	  SELECT auction_id, user_id, total::float8 FROM invoices
+	   WHERE id = $1 AND status = 'issued' FOR UPDATE`, p.InvoiceID).Scan(&pl.AuctionID, &pl.BuyerID, &pl.Amount)
+	if errors.Is(err, sql.ErrNoRows) {
+		// voided meanwhile: keep the money on record, a refund is an admin call
+		zap.L().Warn("payment.captured_for_closed_invoice",
+			zap.Int64("payment", p.ID), zap.Int64("invoice", p.InvoiceID))
+		return tx.Commit()
+	}
+	if err != nil {
+		return err
+	}
+	pl.InvoiceID = p.InvoiceID
+
+	// same lock as secondchance Accept: whichever commits first wins
+	var highBidder string
+	if err = tx.QueryRowContext(ctx, `
+	  SELECT coalesce(high_bidder, '') FROM auctions WHERE id = $1 FOR UPDATE`,
+		pl.AuctionID).Scan(&highBidder); err != nil {
+		return err
+	}
+	if highBidder != pl.BuyerID {
+		// resettled to a second‑chance buyer: like a voided invoice, the
+		// money stays on record for an admin refund
+		zap.L().Warn("payment.captured_after_resettle",
+			zap.Int64("payment", p.ID), zap.Int64("invoice", p.InvoiceID), zap.String("auction", pl.AuctionID))
+		return tx.Commit()
+	}
+	if _, err = tx.ExecContext(ctx, `
+	  UPDATE invoices SET status = 'paid', paid_at = now() WHERE id = $1`, p.InvoiceID); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+	  UPDATE auctions SET status = 'PAID'
+	   WHERE id = $1 AND status IN ('FINISHED', 'UNPAID')`, pl.AuctionID); err != nil {
+		return err
+	}
+	if err = outbox.Write(ctx, tx, outbox.KindAuctionPaid, pl.AuctionID, pl); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	svc.relay.Wake()
+	return nil
+}
+
+func (svc *paymentService) RunDeadlines(ctx context.Context, every time.Duration) {
+	tk := time.NewTicker(every)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+		}
+		if err := svc.sweepOverdue(ctx); err != nil {
+			zap.L().Warn("payment.deadlines", zap.Error(err))
+		}
+	}
+}
+
+// sweepOverdue flags the auctions of overdue buyer invoices as UNPAID. The
+// invoice stays issued: a late payment is still accepted until the seller
+// voids it (e.g. to make a second‑chance offer).
+func (svc *paymentService) sweepOverdue(ctx context.Context) error {
+	rows, err := svc.db.QueryContext(ctx, `
+	  SELECT i.id, i.auction_id, i.user_id, i.total::float8
+	    FROM invoices i
+	    JOIN auctions a ON a.id = i.auction_id AND a.status = 'FINISHED'
+	   WHERE i.party = 'buyer' AND i.status = 'issued' AND i.due_at < now()`)
+	if err != nil {
+		return err
+	}
+	var due []PaidPayload
+	for rows.Next() {
+		var pl PaidPayload
+		if err := rows.Scan(&pl.InvoiceID, &pl.AuctionID, &pl.BuyerID, &pl.Amount); err != nil {
+			rows.Close()
+			return err
+		}
+		due = append(due, pl)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, pl := range due {
+		if err := svc.markUnpaid(ctx, pl); err != nil {
+			zap.L().Warn("payment.unpaid",
+				zap.String("auction", pl.AuctionID), zap.Int64("invoice", pl.InvoiceID), zap.Error(err))
+		}
+	}
+	if len(due) > 0 {
+		svc.relay.Wake()
+	}
+	return nil
+}
+
+func (svc *paymentService) markUnpaid(ctx context.Context, pl PaidPayload) error {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE auctions SET status = 'UNPAID' WHERE id = $1 AND status = 'FINISHED'`, pl.AuctionID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil // another instance got there first
+	}
+	if err = outbox.Write(ctx, tx, outbox.KindAuctionUnpaid, pl.AuctionID, pl); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,439 @@
+// Package registration implements auction visibility and bidder
+// registration. A public auction is listed; an unlisted one is reachable by
+// its ID only; a private one is neither listed nor viewable except by its
+// seller and approved bidders. When registration is required (always for
+// private auctions) only approved bidders may bid:
+//
+//	request ─▶ PENDING ──approve──▶ APPROVED (paddle number assigned)
+//	           PENDING ──reject───▶ REJECTED
+//	           APPROVED ──reject──▶ REJECTED (revoked; keeps its paddle)
+//
+// Approved bidders are mirrored in the Redis set "auc_registered:<id>",
+// which auction_place_bid checks.
+package registration
+
+import (
+	"auctionbidgo/internal/notify"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Visibilities.
+const (
+	VisibilityPublic   = "public"   // listed
+	VisibilityUnlisted = "unlisted" // reachable by ID only
+	VisibilityPrivate  = "private"  // seller and approved bidders only
+)
+
+// Registration statuses.
+const (
+	StatusPending  = "PENDING"
+	StatusApproved = "APPROVED"
+	StatusRejected = "REJECTED"
+)
+
+const redisRegisteredKeyPrefix = "auc_registered:" // approved bidders of an auction
+
+var (
+	ErrAuctionNotFound = errors.New("auction not found")
+	ErrNotFound        = errors.New("registration not found")
+	ErrForbidden       = errors.New("not the seller of this auction")
+	ErrBadVisibility   = errors.New("visibility must be public, unlisted or private")
+	ErrStarted         = errors.New("registration can only be changed before the auction starts")
+	ErrClosed          = errors.New("auction already finished")
+	ErrNotRequired     = errors.New("auction does not require registration")
+	ErrOwnAuction      = errors.New("sellers cannot register for their own auction")
+)
+
+// Settings are the access options of an auction.
+type Settings struct {
+	AuctionID            string `json:"auction_id"`
+	Visibility           string `json:"visibility" enums:"public,unlisted,private"`
+	RegistrationRequired bool   `json:"registration_required"` // always true for private auctions
+}
+
+// Registration is one bidder's request to bid on an auction.
+type Registration struct {
+	AuctionID string     `json:"auction_id"`
+	BidderID  string     `json:"bidder_id"`
+	Status    string     `json:"status" enums:"PENDING,APPROVED,REJECTED"`
+	Paddle    *int       `json:"paddle,omitempty"` // assigned on approval
+	Note      string     `json:"note,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+}
+
+type IRegistrationService interface {
+	// Configure sets visibility and registration. Registration can only be
+	// changed while the auction is PENDING; private implies it.
+	Configure(ctx context.Context, sellerID string, s Settings) (*Settings, error)
+	// Request asks the seller for permission to bid; asking again returns
+	// the existing registration.
+	Request(ctx context.Context, auctionID, bidderID, note string) (*Registration, error)
+	// Decide approves (assigning a paddle number) or rejects a request; a
+	// rejected approval stops further bids. If Redis cannot be updated the
+	// decision is saved but an error is returned; repeating it applies it.
+	Decide(ctx context.Context, auctionID, sellerID, bidderID string, approve bool) (*Registration, error)
+	// ForAuction returns an auction's registrations for its seller, oldest
+	// first; status optionally filters them.
+	ForAuction(ctx context.Context, auctionID, sellerID, status string) ([]Registration, error)
+	// ForBidder returns the bidder's registrations, newest first.
+	ForBidder(ctx context.Context, bidderID string) ([]Registration, error)
+	// CanView reports whether the user may watch the auction. Unknown
+	// auctions are not restricted.
+	CanView(ctx context.Context, auctionID, userID string) (bool, error)
+	// Audience returns who may watch the auction: everyone (all) unless it
+	// is private, else its seller and approved bidders. Unknown auctions are
+	// not restricted.
+	Audience(ctx context.Context, auctionID string) (all bool, userIDs []string, err error)
+}
+
+type registrationService struct {
+	db       *sql.DB
+	rdc      *redis.Client
+	notifier *notify.Dispatcher
+}
+
+var _ IRegistrationService = (*registrationService)(nil)
+
+func NewRegistrationService(db *sql.DB, rdc *redis.Client, notifier *notify.Dispatcher) IRegistrationService {
+	return &registrationService{db: db, rdc: rdc, notifier: notifier}
+}
+
+const selectRegistration = `
+	  SELECT auction_id, bidder_id, status, paddle, coalesce(note, ''), created_at, decided_at
+	    FROM auction_registrations`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRegistration(row scanner) (*Registration, error) {
+	var r Registration
+	err := row.Scan(&r.AuctionID, &r.BidderID, &r.Status, &r.Paddle, &r.Note, &r.CreatedAt, &r.DecidedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// listing is the auction row as far as access cares.
+type listing struct {
+	Settings
+	sellerID string
+	status   string
+}
+
+func lockListing(ctx context.Context, tx *sql.Tx, auctionID string) (*listing, error) {
+	l := listing{Settings: Settings{AuctionID: auctionID}}
+	err := tx.QueryRowContext(ctx, `
+	  SELECT seller_id, status, visibility, registration_required
+	    FROM auctions WHERE id = $1 FOR UPDATE`, auctionID).
+		Scan(&l.sellerID, &l.status, &l.Visibility, &l.RegistrationRequired)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAuctionNotFound
+	}
+	return &l, err
+}
+
+func finished(status string) bool {
+	return status == "FINISHED" || status == "PAID" || status == "UNPAID"
+}
+
+func (svc *registrationService) Configure(ctx context.Context, sellerID string, s Settings) (*Settings, error) {
+	switch s.Visibility {
+	case VisibilityPublic, VisibilityUnlisted:
+	case VisibilityPrivate:
+		s.RegistrationRequired = true
+	default:
+		return nil, ErrBadVisibility
+	}
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	l, err := lockListing(ctx, tx, s.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case l.sellerID != sellerID:
+		return nil, ErrForbidden
+	case finished(l.status):
+		return nil, ErrClosed
+	case l.status != "PENDING" && s.RegistrationRequired != l.RegistrationRequired:
+		// auction_place_bid reads the flag from the running auction's hash
+		return nil, ErrStarted
+	}
+	if _, err := tx.ExecContext(ctx, `
+	  UPDATE auctions SET visibility = $2, registration_required = $3 WHERE id = $1`,
+		s.AuctionID, s.Visibility, s.RegistrationRequired); err != nil {
+		return nil, err
+	}
+	return &s, tx.Commit()
+}
+
+func (svc *registrationService) Request(ctx context.Context, auctionID, bidderID, note string) (*Registration, error) {
+	var sellerID, status string
+	var required bool
+	err := svc.db.QueryRowContext(ctx, `
+	  SELECT seller_id, status, registration_required FROM auctions WHERE id = $1`, auctionID).
+		Scan(&sellerID, &status, &required)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAuctionNotFound
+	}
+	switch {
+	case err != nil:
+		return nil, err
+	case !required:
+		return nil, ErrNotRequired
+	case finished(status):
+		return nil, ErrClosed
+	case bidderID == sellerID:
+		return nil, ErrOwnAuction
+	}
+
+	res, err := svc.db.ExecContext(ctx, `
+	  INSERT INTO auction_registrations (auction_id, bidder_id, note)
+	       VALUES ($1, $2, nullif($3, ''))
+	  ON CONFLICT DO NOTHING`, auctionID, bidderID, note)
+	if err != nil {
+		return nil, err
+	}
+	r, err := scanRegistration(svc.db.QueryRowContext(ctx,
+		selectRegistration+` WHERE auction_id = $1 AND bidder_id = $2`, auctionID, bidderID))
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 1 {
+		svc.notify(ctx, sellerID, r, fmt.Sprintf("%s asks to bid on auction %s", bidderID, auctionID))
+	}
+	return r, nil
+}
+
+func (svc *registrationService) Decide(ctx context.Context, auctionID, sellerID, bidderID string, approve bool) (*Registration, error) {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the auction row lock also serialises paddle numbers
+	l, err := lockListing(ctx, tx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case l.sellerID != sellerID:
+		return nil, ErrForbidden
+	case finished(l.status):
+		return nil, ErrClosed
+	}
+
+	q := `
+	  UPDATE auction_registrations SET status = 'REJECTED', decided_at = now()
+	   WHERE auction_id = $1 AND bidder_id = $2
+	  RETURNING auction_id, bidder_id, status, paddle, coalesce(note, ''), created_at, decided_at`
+	if approve {
+		q = `
+	  UPDATE auction_registrations r
+	     SET status = 'APPROVED', decided_at = now(),
+	         paddle = coalesce(r.paddle, (SELECT coalesce(max(paddle), 0) + 1
+	                                        FROM auction_registrations WHERE auction_id = $1))
+	   WHERE auction_id = $1 AND bidder_id = $2
+	  RETURNING auction_id, bidder_id, status, paddle, coalesce(note, ''), created_at, decided_at`
+	}
+	r, err := scanRegistration(tx.QueryRowContext(ctx, q, auctionID, bidderID))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// StartAuction reloads the set from Postgres, but a running auction
+	// checks only the set: a revoke that misses it must be retried
+	if err := svc.mirror(ctx, auctionID, bidderID); err != nil {
+		return nil, fmt.Errorf("decision saved but not applied to bidding, retry it: %w", err)
+	}
+
+	msg := fmt.Sprintf("Your registration for auction %s was rejected", auctionID)
+	if approve {
+		msg = fmt.Sprintf("You are approved to bid on auction %s (paddle %d)", auctionID, *r.Paddle)
+	}
+	svc.notify(ctx, bidderID, r, msg)
+	return r, nil
+}
+
+// mirror writes the registration's current status (not the decision that
+// led to it) to the Redis set, holding the row lock across the write so
+// concurrent decisions are mirrored in turn and the last leaves Redis
+// matching Postgres.
+func (svc *registrationService) mirror(ctx context.Context, auctionID, bidderID string) error {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var st string
+	err = tx.QueryRowContext(ctx, `
+	  SELECT status FROM auction_registrations
+	   WHERE auction_id = $1 AND bidder_id = $2 FOR UPDATE`, auctionID, bidderID).Scan(&st)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	key := redisRegisteredKeyPrefix + auctionID
+	if st == StatusApproved {
+		err = svc.rdc.SAdd(ctx, key, bidderID).Err()
+	} else {
+		err = svc.rdc.SRem(ctx, key, bidderID).Err()
+	}
+	if err != nil {
+		zap.L().Warn("registration.redis", zap.String("auction", auctionID),
+			zap.String("bidder", bidderID), zap.Error(err))
+		return err
+	}
+	return tx.Commit()
+}
+
+func (svc *registrationService) ForAuction(ctx context.Context, auctionID, sellerID, status string) ([]Registration, error) {
+	var owner string
+	err := svc.db.QueryRowContext(ctx, `SELECT seller_id FROM auctions WHERE id = $1`, auctionID).Scan(&owner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAuctionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if owner != sellerID {
+		return nil, ErrForbidden
+	}
+	return svc.list(ctx, selectRegistration+`
+	   WHERE auction_id = $1 AND ($2 = '' OR status = $2)
+	ORDER BY created_at, bidder_id`, auctionID, status)
+}
+
+func (svc *registrationService) ForBidder(ctx context.Context, bidderID string) ([]Registration, error) {
+	return svc.list(ctx, selectRegistration+`
+	   WHERE bidder_id = $1
+	ORDER BY created_at DESC, auction_id`, bidderID)
+}
+
+func (svc *registrationService) list(ctx context.Context, q string, args ...any) ([]Registration, error) {
+	rows, err := svc.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Registration{}
+	for rows.Next() {
+		r, err := scanRegistration(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *r)
+	}
+	return out, rows.Err()
+}
+
+func (svc *registrationService) CanView(ctx context.Context, auctionID, userID string) (bool, error) {
+	var ok bool
+	err := svc.db.QueryRowContext(ctx, `
+	  SELECT a.visibility <> 'private' OR a.seller_id = $2
+	         OR EXISTS (SELECT 1 FROM auction_registrations r
+	                     WHERE r.auction_id = a.id AND r.bidder_id = $2 AND r.status = 'APPROVED')
+	    FROM auctions a WHERE a.id = $1`, auctionID, userID).Scan(&ok)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	return ok, err
+}
+
+func (svc *registrationService) Audience(ctx context.Context, auctionID string) (bool, []string, error) {
+	var private bool
+	var sellerID string
+	err := svc.db.QueryRowContext(ctx, `
+	  SELECT visibility = 'private', seller_id FROM auctions WHERE id = $1`, auctionID).Scan(&private, &sellerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	if !private {
+		return true, nil, nil
+	}
+
+	rows, err := svc.db.QueryContext(ctx, `
+	  SELECT bidder_id FROM auction_registrations
+	   WHERE auction_id = $1 AND status = 'APPROVED'`, auctionID)
+	if err != nil {
+		return false, nil, err
+	}
+	defer rows.Close()
+	users := []string{sellerID}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return false, nil, err
+		}
+		users = append(users, id)
+	}
+	return false, users, rows.Err()
+}
+
+func (svc *registrationService) notify(ctx context.Context, userID string, r *Registration, msg string) {
+	at := r.CreatedAt
+	if r.DecidedAt != nil {
+		at = *r.DecidedAt
+	}
+	svc.notifier.Dispatch(ctx, notify.Notification{
+		// one per transition and recipient
+		Key:       "registration:" + r.AuctionID + ":" + r.BidderID + ":" + strconv.FormatInt(at.UnixNano(), 10) + ":" + userID,
+		UserID:    userID,
+		Kind:      notify.KindRegistration,
+		AuctionID: r.AuctionID,
+		Message:   msg,
+		Data:      r,
+		CreatedAt: at.UTC(),
+	})
+}
+
+// Approved returns the approved bidders of an auction, for StartAuction to
+// load into Redis.
+func Approved(ctx context.Context, tx *sql.Tx, auctionID string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+	  SELECT bidder_id FROM auction_registrations
+	   WHERE auction_id = $1 AND status = 'APPROVED'`, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,24 @@
+package relist
+
+import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/outbox"
+	"context"
+	"encoding/json"
+)
+
+// RegisterOutboxHandlers makes the relay relist auctions that finish
+// without a winner. Relist is idempotent, as the relay requires.
+func RegisterOutboxHandlers(r *outbox.Relay, svc IRelistService) {
+	r.Handle(outbox.KindAuctionFinished, func(ctx context.Context, m outbox.Message) error {
+		var stop events.Stop
+		if err := json.Unmarshal(m.Payload, &stop); err != nil {
+			return err
+		}
+		if stop.HighBidder != "" {
+			return nil
+		}
+		_, err := svc.Relist(ctx, m.AggregateID)
+		return err
+	})
+}
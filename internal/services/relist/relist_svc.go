@@ -0,0 +1,254 @@
+// Package relist relists auctions that finish unsold. The seller sets the
+// rules on an auction (how many relists, their duration, a price drop per
+// cycle); when it finishes without a winner a linked successor auction is
+// created and started, carrying the rules over with one relist less:
+//
+//	auc123 ──unsold──▶ <uuid> (cycle 1, start price −10 %) ──unsold──▶ …
+package relist
+
+import (
+	"auctionbidgo/internal/outbox"
+	"auctionbidgo/internal/services/auction"
+	"auctionbidgo/internal/services/offer"
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAuctionNotFound = errors.New("auction not found")
+	ErrForbidden       = errors.New("only the seller can set relist rules")
+	ErrEnded           = errors.New("auction already ended")
+	ErrNoDuration      = errors.New("duration_seconds is required to relist")
+)
+
+// Rules are the relist rules of an auction.
+type Rules struct {
+	AuctionID       string   `json:"auction_id"`
+	Relists         int      `json:"relists"`                 // relists left; 0 = off
+	DurationSeconds int      `json:"duration_seconds"`        // of every relist
+	PriceDropPct    float64  `json:"price_drop_pct"`          // start price cut per cycle
+	MinPrice        *float64 `json:"min_price,omitempty"`     // the cuts stop here
+	StartPrice      float64  `json:"start_price,omitempty"`   // of this auction, for reference
+	Cycle           int      `json:"cycle"`                   // 0 = original listing
+	RelistedFrom    string   `json:"relisted_from,omitempty"` // previous auction of the chain
+}
+
+type IRelistService interface {
+	// SetRules replaces the rules of a PENDING or RUNNING auction; seller only.
+	SetRules(ctx context.Context, sellerID string, r Rules) (*Rules, error)
+	GetRules(ctx context.Context, auctionID string) (*Rules, error)
+	// Relist creates and starts the successor of a finished, unsold auction
+	// whose rules allow it and returns its ID ("" = not relisted). Calling it
+	// again returns (and if needed starts) the same successor.
+	Relist(ctx context.Context, auctionID string) (string, error)
+}
+
+type relistService struct {
+	db       *sql.DB
+	auctions auction.IAuctionService
+	relay    *outbox.Relay
+}
+
+var _ IRelistService = (*relistService)(nil)
+
+func NewRelistService(db *sql.DB, auctions auction.IAuctionService, relay *outbox.Relay) IRelistService {
+	return &relistService{db: db, auctions: auctions, relay: relay}
+}
+
+const selectRules = `
+  SELECT id, relist_remaining, coalesce(relist_duration, 0), relist_price_drop_pct::float8,
+         relist_min_price::float8, coalesce(start_price, 0)::float8, relist_cycle,
+         coalesce(relisted_from, '')
+    FROM auctions`
+
+func scanRules(row interface{ Scan(...any) error }) (*Rules, error) {
+	var r Rules
+	err := row.Scan(&r.AuctionID, &r.Relists, &r.DurationSeconds, &r.PriceDropPct,
+		&r.MinPrice, &r.StartPrice, &r.Cycle, &r.RelistedFrom)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAuctionNotFound
+	}
+	return &r, err
+}
+
+func (svc *relistService) GetRules(ctx context.Context, auctionID string) (*Rules, error) {
+	return scanRules(svc.db.QueryRowContext(ctx, selectRules+` WHERE id = $1`, auctionID))
+}
+
+func (svc *relistService) SetRules(ctx context.Context, sellerID string, r Rules) (*Rules, error) {
+	if r.Relists > 0 && r.DurationSeconds <= 0 {
+		return nil, ErrNoDuration
+	}
+	var owner, status string
+	err := svc.db.QueryRowContext(ctx,
+		`SELECT seller_id, status FROM auctions WHERE id = $1`, r.AuctionID).Scan(&owner, &status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAuctionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if owner != sellerID {
+		return nil, ErrForbidden
+	}
+	if status != "PENDING" && status != "RUNNING" {
+		return nil, ErrEnded
+	}
+
+	var dur *int
+	if r.DurationSeconds > 0 {
+		dur = &r.DurationSeconds
+	}
+	if _, err := svc.db.ExecContext(ctx, `
+	  UPDATE auctions
+	     SET relist_remaining = $2, relist_duration = $3,
+	         relist_price_drop_pct = $4, relist_min_price = $5
+	   WHERE id = $1`, r.AuctionID, r.Relists, dur, r.PriceDropPct, r.MinPrice); err != nil {
+		return nil, err
+	}
+	return svc.GetRules(ctx, r.AuctionID)
+}
+
+// successor is the row a relist creates.
+type successor struct {
+	id       string
+	sellerID string
+	endsAt   time.Time
+	status   string
+}
+
+func (svc *relistService) Relist(ctx context.Context, auctionID string) (string, error) {
+	next, err := svc.create(ctx, auctionID)
+	if err != nil || next == nil {
+		return "", err
+	}
+	if next.status != "PENDING" && next.status != "STARTING" {
+		return next.id, nil // started on an earlier attempt
+	}
+	err = svc.auctions.StartAuction(ctx, next.id, next.sellerID, next.endsAt)
+	switch {
+	case errors.Is(err, auction.ErrAlreadyRunning):
+	case errors.Is(err, auction.ErrAuctionClosed):
+		// retried past the relist's end: leave it PENDING for the seller
+		zap.L().Warn("relist.start_too_late", zap.String("auction", auctionID), zap.String("next", next.id))
+	case err != nil:
+		return "", err
+	}
+	return next.id, nil
+}
+
+// create inserts the successor of an unsold auction, or returns the one an
+// earlier call inserted; nil when the auction is not to be relisted. The
+// auction row is locked, so an offer accepted at the same time (which also
+// needs the lock) either wins the item or finds the auction relisted.
+func (svc *relistService) create(ctx context.Context, auctionID string) (*successor, error) {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status, highBidder, item string
+	err = tx.QueryRowContext(ctx, `
+	  SELECT status, coalesce(high_bidder, ''), item
+	    FROM auctions WHERE id = $1 FOR UPDATE`, auctionID).Scan(&status, &highBidder, &item)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // deleted meanwhile
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	next := &successor{}
+	err = tx.QueryRowContext(ctx, `
+	  SELECT id, seller_id, ends_at, status FROM auctions WHERE relisted_from = $1`, auctionID).
+		Scan(&next.id, &next.sellerID, &next.endsAt, &next.status)
+	if err == nil {
+		return next, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	r, err := scanRules(tx.QueryRowContext(ctx, selectRules+` WHERE id = $1`, auctionID))
+	if err != nil {
+		return nil, err
+	}
+	if status != "FINISHED" || highBidder != "" || r.Relists <= 0 || r.DurationSeconds <= 0 {
+		return nil, nil
+	}
+
+	price := r.StartPrice * (1 - r.PriceDropPct/100)
+	price = math.Round(price*100) / 100
+	if r.MinPrice != nil && price < *r.MinPrice {
+		price = *r.MinPrice
+	}
+	if price < 0 {
+		price = 0
+	}
+	next.id = uuid.NewString()
+	next.endsAt = time.Now().Add(time.Duration(r.DurationSeconds) * time.Second).UTC().Truncate(time.Second)
+	next.status = "PENDING"
+
+	// same item, seller, offer and access settings; the rules carry over
+	// with one relist less
+	err = tx.QueryRowContext(ctx, `
+	  INSERT INTO auctions (id, seller_id, item, starts_at, ends_at, status, start_price,
+	                        relisted_from, relist_cycle, relist_remaining, relist_duration,
+	                        relist_price_drop_pct, relist_min_price,
+	                        best_offer, offer_auto_accept, offer_auto_decline,
+	                        visibility, registration_required)
+	  SELECT $2, seller_id, item, now(), $3, 'PENDING', nullif($4, 0)::numeric,
+	         id, relist_cycle + 1, relist_remaining - 1, relist_duration,
+	         relist_price_drop_pct, relist_min_price,
+	         best_offer, offer_auto_accept, offer_auto_decline,
+	         visibility, registration_required
+	    FROM auctions WHERE id = $1
+	  RETURNING seller_id`, auctionID, next.id, next.endsAt, price).Scan(&next.sellerID)
+	if err != nil {
+		return nil, err
+	}
+	// approved bidders stay approved, with their paddles
+	if _, err = tx.ExecContext(ctx, `
+	  INSERT INTO auction_registrations (auction_id, bidder_id, status, paddle, note, created_at, decided_at)
+	  SELECT $2, bidder_id, status, paddle, note, created_at, decided_at
+	    FROM auction_registrations WHERE auction_id = $1 AND status = 'APPROVED'`,
+		auctionID, next.id); err != nil {
+		return nil, err
+	}
+	// offers still open on the unsold auction are declined, as when an
+	// offer is accepted; buyers can offer again on the successor
+	if _, err = tx.ExecContext(ctx, `
+	  WITH closed AS (
+	    UPDATE offers SET status = $2, updated_at = now()
+	     WHERE auction_id = $1 AND status IN ($3, $4)
+	    RETURNING id)
+	  INSERT INTO offer_events (offer_id, action, actor)
+	  SELECT id, $5, 'system' FROM closed`,
+		auctionID, offer.StatusDeclined, offer.StatusPending, offer.StatusCountered, offer.ActionRelisted); err != nil {
+		return nil, err
+	}
+	if err = outbox.Write(ctx, tx, outbox.KindAuctionCreated, next.id, auction.CreatedPayload{
+		ID:           next.id,
+		SellerID:     next.sellerID,
+		Item:         item,
+		StartPrice:   price,
+		EndsAt:       next.endsAt,
+		RelistedFrom: auctionID,
+	}); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	svc.relay.Wake()
+	zap.L().Info("relist.created", zap.String("auction", auctionID), zap.String("next", next.id),
+		zap.Int("cycle", r.Cycle+1), zap.Float64("start_price", price))
+	return next, nil
+}
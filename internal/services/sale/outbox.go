@@ -0,0 +1,14 @@
+package sale
+
+import (
+	"auctionbidgo/internal/outbox"
+	"context"
+)
+
+// RegisterOutboxHandlers makes the relay close a sale when its last lot
+// finishes. LotFinished is idempotent, as the relay requires.
+func RegisterOutboxHandlers(r *outbox.Relay, svc ISaleService) {
+	r.Handle(outbox.KindAuctionFinished, func(ctx context.Context, m outbox.Message) error {
+		return svc.LotFinished(ctx, m.AggregateID)
+	})
+}
@@ -0,0 +1,514 @@
+// Package sale groups auctions into sales: catalogs of ordered lots that
+// open together when the sale is published and close one after another,
+// lot N at closing_starts_at + (N‑1)·lot_interval. Lots get the sale's soft
+// close; when a late bid extends lot N, auction_place_bid shifts every
+// later lot by the same amount, so the closing order holds. Lot transitions
+// are published on "sale:<id>:events" (events.Lot).
+package sale
+
+import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/services/auction"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Sale statuses.
+const (
+	StatusDraft     = "DRAFT" // the catalog is being built
+	StatusPublished = "PUBLISHED"
+	StatusClosed    = "CLOSED" // every lot has ended
+)
+
+var (
+	ErrNotFound       = errors.New("sale not found")
+	ErrExists         = errors.New("sale already exists")
+	ErrForbidden      = errors.New("only the seller can change the sale")
+	ErrNotDraft       = errors.New("sale is already published")
+	ErrClosed         = errors.New("sale is closed")
+	ErrBadSchedule    = errors.New("lots must close in the future")
+	ErrEmpty          = errors.New("sale has no lots")
+	ErrLotUnavailable = errors.New("lot must be a timed PENDING auction of the seller that is in no other sale")
+	ErrLotNotFound    = errors.New("lot not found in this sale")
+	ErrBadOrder       = errors.New("order must list every lot of the sale exactly once")
+)
+
+type Sale struct {
+	ID              string     `json:"id"`
+	SellerID        string     `json:"seller_id"`
+	Title           string     `json:"title"`
+	Status          string     `json:"status" enums:"DRAFT,PUBLISHED,CLOSED"`
+	ClosingStartsAt time.Time  `json:"closing_starts_at"` // lot 1 closes then
+	LotInterval     int        `json:"lot_interval"`      // seconds between two lot closes
+	ExtendWindow    int        `json:"extend_window"`     // seconds; 0 = no soft close
+	ExtendBy        int        `json:"extend_by"`         // seconds after the late bid
+	LotCount        int        `json:"lot_count"`
+	CreatedAt       time.Time  `json:"created_at"`
+	PublishedAt     *time.Time `json:"published_at,omitempty"`
+	ClosedAt        *time.Time `json:"closed_at,omitempty"`
+}
+
+// Lot is one auction of a sale; live values come from Redis while it runs.
+type Lot struct {
+	LotNo      int       `json:"lot"`
+	AuctionID  string    `json:"auction_id"`
+	Item       string    `json:"item"`
+	StartPrice float64   `json:"start_price,omitempty"`
+	Status     string    `json:"status"`
+	EndsAt     time.Time `json:"ends_at"` // scheduled while the sale is a draft
+	HighBid    float64   `json:"high_bid"`
+	HighBidder string    `json:"high_bidder"`
+}
+
+type Catalog struct {
+	Sale
+	Lots []Lot `json:"lots"`
+}
+
+// NewLot adds an existing PENDING auction (AuctionID) or creates one.
+type NewLot struct {
+	AuctionID  string
+	Item       string
+	StartPrice float64
+}
+
+type ISaleService interface {
+	Create(ctx context.Context, s Sale) (*Sale, error)
+	// Update changes title, schedule and soft close of a draft.
+	Update(ctx context.Context, sellerID string, s Sale) (*Sale, error)
+	// Get returns the sale with the lots viewerID may see: private lots only
+	// for their seller and approved bidders (see registration).
+	Get(ctx context.Context, id, viewerID string) (*Catalog, error)
+	List(ctx context.Context, status string, limit, offset int) ([]Sale, error)
+	// AddLot, RemoveLot and Reorder build the catalog of a draft.
+	AddLot(ctx context.Context, saleID, sellerID string, l NewLot) (*Catalog, error)
+	RemoveLot(ctx context.Context, saleID, sellerID, auctionID string) (*Catalog, error)
+	Reorder(ctx context.Context, saleID, sellerID string, auctionIDs []string) (*Catalog, error)
+	// Publish fixes the lot end times and starts every lot. Calling it again
+	// starts the lots a previous call could not.
+	Publish(ctx context.Context, saleID, sellerID string) (*Catalog, error)
+	// LotFinished closes the sale once its last lot has ended.
+	LotFinished(ctx context.Context, auctionID string) error
+}
+
+type saleService struct {
+	db       *sql.DB
+	rdc      *redis.Client
+	auctions auction.IAuctionService
+}
+
+var _ ISaleService = (*saleService)(nil)
+
+func NewSaleService(db *sql.DB, rdc *redis.Client, auctions auction.IAuctionService) ISaleService {
+	return &saleService{db: db, rdc: rdc, auctions: auctions}
+}
+
+// lotEnd is the scheduled close of lot n.
+func (s *Sale) lotEnd(n int) time.Time {
+	return s.ClosingStartsAt.Add(time.Duration(n-1) * time.Duration(s.LotInterval) * time.Second)
+}
+
+// a lot needs some bidding time: the first one may not close right away
+const minLead = time.Minute
+
+const selectSale = `
+  SELECT s.id, s.seller_id, s.title, s.status, s.closing_starts_at, s.lot_interval,
+         s.extend_window, s.extend_by,
+         (SELECT count(*) FROM auctions a WHERE a.sale_id = s.id),
+         s.created_at, s.published_at, s.closed_at
+    FROM sales s`
+
+func scanSale(row interface{ Scan(...any) error }) (*Sale, error) {
+	var s Sale
+	err := row.Scan(&s.ID, &s.SellerID, &s.Title, &s.Status, &s.ClosingStartsAt, &s.LotInterval,
+		&s.ExtendWindow, &s.ExtendBy, &s.LotCount, &s.CreatedAt, &s.PublishedAt, &s.ClosedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return &s, err
+}
+
+func (svc *saleService) Create(ctx context.Context, s Sale) (*Sale, error) {
+	if s.ID == "" {
+		s.ID = uuid.NewString()
+	}
+	if s.ClosingStartsAt.Before(time.Now().Add(minLead)) {
+		return nil, ErrBadSchedule
+	}
+	_, err := svc.db.ExecContext(ctx, `
+	  INSERT INTO sales (id, seller_id, title, closing_starts_at, lot_interval, extend_window, extend_by)
+	       VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		s.ID, s.SellerID, s.Title, s.ClosingStartsAt, s.LotInterval, s.ExtendWindow, s.ExtendBy)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, ErrExists
+		}
+		return nil, err
+	}
+	return scanSale(svc.db.QueryRowContext(ctx, selectSale+` WHERE s.id = $1`, s.ID))
+}
+
+func (svc *saleService) Update(ctx context.Context, sellerID string, s Sale) (*Sale, error) {
+	if s.ClosingStartsAt.Before(time.Now().Add(minLead)) {
+		return nil, ErrBadSchedule
+	}
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockDraft(ctx, tx, s.ID, sellerID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+	  UPDATE sales
+	     SET title = $2, closing_starts_at = $3, lot_interval = $4, extend_window = $5, extend_by = $6
+	   WHERE id = $1`, s.ID, s.Title, s.ClosingStartsAt, s.LotInterval, s.ExtendWindow, s.ExtendBy); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return scanSale(svc.db.QueryRowContext(ctx, selectSale+` WHERE s.id = $1`, s.ID))
+}
+
+// lock reads the sale FOR UPDATE and checks that sellerID owns it.
+func lock(ctx context.Context, tx *sql.Tx, saleID, sellerID string) (*Sale, error) {
+	s, err := scanSale(tx.QueryRowContext(ctx, selectSale+` WHERE s.id = $1 FOR UPDATE OF s`, saleID))
+	if err != nil {
+		return nil, err
+	}
+	if s.SellerID != sellerID {
+		return nil, ErrForbidden
+	}
+	return s, nil
+}
+
+// lockDraft is lock for catalog changes, which only drafts allow.
+func lockDraft(ctx context.Context, tx *sql.Tx, saleID, sellerID string) (*Sale, error) {
+	s, err := lock(ctx, tx, saleID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if s.Status != StatusDraft {
+		return nil, ErrNotDraft
+	}
+	return s, nil
+}
+
+func (svc *saleService) Get(ctx context.Context, id, viewerID string) (*Catalog, error) {
+	s, err := scanSale(svc.db.QueryRowContext(ctx, selectSale+` WHERE s.id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{Sale: *s, Lots: []Lot{}}
+
+	rows, err := svc.db.QueryContext(ctx, `
+	  SELECT lot_no, id, item, coalesce(start_price, 0)::float8, status, ends_at,
+	         coalesce(high_bid, 0)::float8, coalesce(high_bidder, '')
+	    FROM auctions a
+	   WHERE sale_id = $1
+	     AND (a.visibility <> 'private' OR a.seller_id = $2
+	          OR EXISTS (SELECT 1 FROM auction_registrations r
+	                      WHERE r.auction_id = a.id AND r.bidder_id = $2 AND r.status = 'APPROVED'))
+	ORDER BY lot_no`, id, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var l Lot
+		if err := rows.Scan(&l.LotNo, &l.AuctionID, &l.Item, &l.StartPrice, &l.Status, &l.EndsAt,
+			&l.HighBid, &l.HighBidder); err != nil {
+			return nil, err
+		}
+		if s.Status == StatusDraft {
+			l.EndsAt = s.lotEnd(l.LotNo)
+		}
+		c.Lots = append(c.Lots, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if s.Status == StatusPublished {
+		svc.overlayLive(ctx, c.Lots)
+	}
+	return c, nil
+}
+
+// overlayLive replaces the (up to 10 s old) Postgres values of running lots
+// with the live ones from their Redis hashes.
+func (svc *saleService) overlayLive(ctx context.Context, lots []Lot) {
+	cmds := make([]*redis.SliceCmd, len(lots))
+	_, err := svc.rdc.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for i, l := range lots {
+			cmds[i] = p.HMGet(ctx, "auc:"+l.AuctionID, "st", "ea", "hb", "hbid")
+		}
+		return nil
+	})
+	if err != nil {
+		zap.L().Warn("sale.live", zap.Error(err))
+		return
+	}
+	for i, cmd := range cmds {
+		var h struct {
+			Status     string  `redis:"st"`
+			EndsAt     int64   `redis:"ea"`
+			HighBid    float64 `redis:"hb"`
+			HighBidder string  `redis:"hbid"`
+		}
+		if err := cmd.Scan(&h); err != nil || h.Status == "" {
+			continue
+		}
+		lots[i].Status = h.Status
+		lots[i].EndsAt = time.Unix(h.EndsAt, 0).UTC()
+		lots[i].HighBid = h.HighBid
+		lots[i].HighBidder = h.HighBidder
+	}
+}
+
+func (svc *saleService) List(ctx context.Context, status string, limit, offset int) ([]Sale, error) {
+	if limit == 0 {
+		limit = 10
+	}
+	rows, err := svc.db.QueryContext(ctx, selectSale+`
+	   WHERE $1 = '' OR s.status = $1
+	ORDER BY s.closing_starts_at DESC
+	   LIMIT $2 OFFSET $3`, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]Sale, 0, limit)
+	for rows.Next() {
+		s, err := scanSale(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *s)
+	}
+	return out, rows.Err()
+}
+
+func (svc *saleService) AddLot(ctx context.Context, saleID, sellerID string, l NewLot) (*Catalog, error) {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	s, err := lockDraft(ctx, tx, saleID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	n := s.LotCount + 1
+	endsAt := s.lotEnd(n)
+	if endsAt.Before(time.Now().Add(minLead)) {
+		return nil, ErrBadSchedule
+	}
+
+	if l.AuctionID == "" {
+		// a fresh PENDING auction; it stays a plain draft auction if the
+		// attach below fails
+		if l.AuctionID, err = svc.auctions.CreateAuction(ctx, "", sellerID, l.Item, l.StartPrice, false, endsAt); err != nil {
+			return nil, err
+		}
+	}
+	res, err := tx.ExecContext(ctx, `
+	  UPDATE auctions SET sale_id = $2, lot_no = $3, ends_at = $4
+	   WHERE id = $1 AND seller_id = $5 AND status = 'PENDING' AND sale_id IS NULL AND NOT live`,
+		l.AuctionID, saleID, n, endsAt, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if k, _ := res.RowsAffected(); k == 0 {
+		return nil, ErrLotUnavailable
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return svc.Get(ctx, saleID, sellerID)
+}
+
+func (svc *saleService) RemoveLot(ctx context.Context, saleID, sellerID, auctionID string) (*Catalog, error) {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockDraft(ctx, tx, saleID, sellerID); err != nil {
+		return nil, err
+	}
+	var n int
+	err = tx.QueryRowContext(ctx,
+		`SELECT lot_no FROM auctions WHERE id = $1 AND sale_id = $2`, auctionID, saleID).Scan(&n)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrLotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE auctions SET sale_id = NULL, lot_no = NULL WHERE id = $1`, auctionID); err != nil {
+		return nil, err
+	}
+	// close the gap
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE auctions SET lot_no = lot_no - 1 WHERE sale_id = $1 AND lot_no > $2`, saleID, n); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return svc.Get(ctx, saleID, sellerID)
+}
+
+func (svc *saleService) Reorder(ctx context.Context, saleID, sellerID string, auctionIDs []string) (*Catalog, error) {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	s, err := lockDraft(ctx, tx, saleID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(auctionIDs) != s.LotCount {
+		return nil, ErrBadOrder
+	}
+	res, err := tx.ExecContext(ctx, `
+	  UPDATE auctions a SET lot_no = o.n
+	    FROM unnest($2::text[]) WITH ORDINALITY AS o(id, n)
+	   WHERE a.id = o.id AND a.sale_id = $1`, saleID, auctionIDs)
+	if err != nil {
+		return nil, err
+	}
+	// as many IDs as lots, each lot updated once: a permutation
+	if k, _ := res.RowsAffected(); int(k) != s.LotCount {
+		return nil, ErrBadOrder
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return svc.Get(ctx, saleID, sellerID)
+}
+
+func (svc *saleService) Publish(ctx context.Context, saleID, sellerID string) (*Catalog, error) {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	s, err := lock(ctx, tx, saleID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	switch s.Status {
+	case StatusClosed:
+		return nil, ErrClosed
+	case StatusDraft:
+		if s.LotCount == 0 {
+			return nil, ErrEmpty
+		}
+		if s.ClosingStartsAt.Before(time.Now().Add(minLead)) {
+			return nil, ErrBadSchedule
+		}
+		if _, err := tx.ExecContext(ctx, `
+		  UPDATE auctions
+		     SET ends_at = $2::timestamptz + (lot_no - 1) * $3 * interval '1 second'
+		   WHERE sale_id = $1`, saleID, s.ClosingStartsAt, s.LotInterval); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE sales SET status = 'PUBLISHED', published_at = now() WHERE id = $1`, saleID); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+	  SELECT id, ends_at FROM auctions
+	   WHERE sale_id = $1 AND status IN ('PENDING', 'STARTING') ORDER BY lot_no`, saleID)
+	if err != nil {
+		return nil, err
+	}
+	type pending struct {
+		id     string
+		endsAt time.Time
+	}
+	var lots []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.endsAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		lots = append(lots, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	if s.Status == StatusDraft {
+		svc.publish(ctx, events.SaleStatus{SaleID: saleID, Status: StatusPublished})
+	}
+
+	// start outside the transaction: StartAuction reads the lot rows
+	var errs []error
+	for _, l := range lots {
+		err := svc.auctions.StartAuction(ctx, l.id, sellerID, l.endsAt)
+		if err != nil && !errors.Is(err, auction.ErrAlreadyRunning) {
+			zap.L().Warn("sale.start_lot", zap.String("sale", saleID), zap.String("auction", l.id), zap.Error(err))
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return svc.Get(ctx, saleID, sellerID)
+}
+
+func (svc *saleService) LotFinished(ctx context.Context, auctionID string) error {
+	var saleID string
+	err := svc.db.QueryRowContext(ctx, `
+	  UPDATE sales s SET status = 'CLOSED', closed_at = now()
+	    FROM auctions a
+	   WHERE a.id = $1 AND s.id = a.sale_id AND s.status = 'PUBLISHED'
+	     AND NOT EXISTS (SELECT 1 FROM auctions l
+	                      WHERE l.sale_id = s.id AND l.status IN ('PENDING', 'STARTING', 'RUNNING'))
+	  RETURNING s.id`, auctionID).Scan(&saleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil // not a lot, or not the last one
+	}
+	if err != nil {
+		return err
+	}
+	svc.publish(ctx, events.SaleStatus{SaleID: saleID, Status: StatusClosed})
+	return svc.rdc.Del(ctx, "sale_lots:"+saleID).Err()
+}
+
+// publish sends a sale status event to the sale's subscribers.
+func (svc *saleService) publish(ctx context.Context, ev events.SaleStatus) {
+	ev.Header = events.Header{Version: events.Version}
+	payload, _ := json.Marshal(struct {
+		Event string `json:"event"`
+		events.SaleStatus
+	}{events.NameSaleStatus, ev})
+	if err := svc.rdc.Publish(ctx, "sale:"+ev.SaleID+":events", payload).Err(); err != nil {
+		zap.L().Warn("sale.publish", zap.String("sale", ev.SaleID), zap.Error(err))
+	}
+}
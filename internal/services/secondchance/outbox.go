@@ -0,0 +1,23 @@
+package secondchance
+
+import (
+	"auctionbidgo/internal/outbox"
+	"context"
+	"encoding/json"
+)
+
+// RegisterOutboxHandlers makes the relay re‑settle accepted offers and,
+// with Config.Auto, offer an unpaid auction to the best runner‑up.
+func RegisterOutboxHandlers(r *outbox.Relay, svc ISecondChanceService) {
+	r.Handle(outbox.KindAuctionUnpaid, func(ctx context.Context, m outbox.Message) error {
+		svc.OfferNext(ctx, m.AggregateID)
+		return nil
+	})
+	r.Handle(outbox.KindAuctionResettled, func(ctx context.Context, m outbox.Message) error {
+		var rs Resettled
+		if err := json.Unmarshal(m.Payload, &rs); err != nil {
+			return err
+		}
+		return svc.Resettle(ctx, rs)
+	})
+}
@@ -0,0 +1,536 @@
+// Package secondchance offers an item whose winner did not pay (auction
+// UNPAID) to the runner‑up bidders, one at a time, at each one's own last
+// bid. An accepted offer re‑settles the auction to that bidder; every step
+// is recorded in second_chance_audit.
+package secondchance
+
+import (
+	"auctionbidgo/internal/notify"
+	"auctionbidgo/internal/outbox"
+	"auctionbidgo/internal/services/settlement"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Offer statuses.
+const (
+	StatusOpen     = "open"
+	StatusAccepted = "accepted"
+	StatusDeclined = "declined"
+	StatusExpired  = "expired"
+)
+
+// Audit actions.
+const (
+	ActionOffered   = "offered"
+	ActionAccepted  = "accepted"
+	ActionDeclined  = "declined"
+	ActionExpired   = "expired"
+	ActionResettled = "resettled"
+	ActionFailed    = "resettle_failed"
+)
+
+// ActorSystem is the audit actor of automatic steps.
+const ActorSystem = "system"
+
+var (
+	ErrAuctionNotFound = errors.New("auction not found")
+	ErrNotFound        = errors.New("offer not found")
+	ErrNotSeller       = errors.New("only the seller can make offers")
+	ErrNotUnpaid       = errors.New("auction is not unpaid")
+	ErrOfferOpen       = errors.New("auction already has an open offer")
+	ErrNoCandidate     = errors.New("no eligible runner-up left")
+	ErrNotEligible     = errors.New("bidder is not an eligible runner-up")
+	ErrNotOfferee      = errors.New("offer belongs to another bidder")
+	ErrOfferClosed     = errors.New("offer is no longer open")
+)
+
+// Candidate is a runner‑up who can be offered the item at Amount, their
+// highest bid.
+type Candidate struct {
+	BidderID  string    `json:"bidder_id"`
+	Amount    float64   `json:"amount"`
+	LastBidAt time.Time `json:"last_bid_at"`
+}
+
+// Offer is a SecondChanceOffer.
+type Offer struct {
+	ID          int64      `json:"id"`
+	AuctionID   string     `json:"auction_id"`
+	BidderID    string     `json:"bidder_id"`
+	Amount      float64    `json:"amount"`
+	Status      string     `json:"status" enums:"open,accepted,declined,expired"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+}
+
+// AuditEntry is one step of the flow.
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	AuctionID string          `json:"auction_id"`
+	OfferID   *int64          `json:"offer_id,omitempty"`
+	Action    string          `json:"action" enums:"offered,accepted,declined,expired,resettled,resettle_failed"`
+	Actor     string          `json:"actor"`
+	Detail    json.RawMessage `json:"detail" swaggertype:"object"`
+	At        time.Time       `json:"at"`
+}
+
+// Resettled is the payload of the auction.resettled outbox message.
+type Resettled struct {
+	AuctionID     string  `json:"auction_id"`
+	OfferID       int64   `json:"offer_id"`
+	SellerID      string  `json:"seller_id"`
+	BuyerID       string  `json:"buyer_id"`
+	PreviousBuyer string  `json:"previous_buyer_id"`
+	Amount        float64 `json:"amount"`
+}
+
+// Config: how long an offer stays open, and whether the next runner‑up is
+// offered automatically (on auction.unpaid and after a decline or expiry).
+type Config struct {
+	TTL  time.Duration
+	Auto bool
+}
+
+type ISecondChanceService interface {
+	// Candidates lists the eligible runner‑ups, best bid first.
+	Candidates(ctx context.Context, auctionID string) ([]Candidate, error)
+	// Offer makes an offer to bidderID ("" = the best candidate). actor is
+	// the seller, or ActorSystem for automatic offers.
+	Offer(ctx context.Context, auctionID, actor, bidderID string) (*Offer, error)
+	Accept(ctx context.Context, offerID int64, bidderID string) (*Offer, error)
+	Decline(ctx context.Context, offerID int64, bidderID string) (*Offer, error)
+	ListForAuction(ctx context.Context, auctionID string) ([]Offer, error)
+	Audit(ctx context.Context, auctionID string) ([]AuditEntry, error)
+	// OfferNext offers the item to the best remaining runner‑up when
+	// Config.Auto is on; a no‑op otherwise or when nobody is left.
+	OfferNext(ctx context.Context, auctionID string)
+	// Resettle settles an accepted offer (auction.resettled, via the relay).
+	Resettle(ctx context.Context, rs Resettled) error
+	// RunExpiry expires overdue offers every interval until ctx is done.
+	RunExpiry(ctx context.Context, every time.Duration)
+}
+
+type secondChanceService struct {
+	db       *sql.DB
+	settle   settlement.ISettlementService
+	notifier *notify.Dispatcher
+	relay    *outbox.Relay
+	cfg      Config
+}
+
+var _ ISecondChanceService = (*secondChanceService)(nil)
+
+func NewSecondChanceService(db *sql.DB, settle settlement.ISettlementService, notifier *notify.Dispatcher,
+	relay *outbox.Relay, cfg Config) ISecondChanceService {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 48 * time.Hour
+	}
+	return &secondChanceService{db: db, settle: settle, notifier: notifier, relay: relay, cfg: cfg}
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, q string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, q string, args ...any) (sql.Result, error)
+}
+
+// candidates: every bidder's best bid, except the current winner, anyone who
+// was ever invoiced as the buyer and anyone who already got an offer.
+func candidates(ctx context.Context, q querier, auctionID string) ([]Candidate, error) {
+	rows, err := q.QueryContext(ctx, `
+	  SELECT b.bidder_id, max(b.amount)::float8, max(b.placed_at)
+	    FROM bids b
+	    JOIN auctions a ON a.id = b.auction_id
+	   WHERE b.auction_id = $1
+	     AND b.bidder_id <> coalesce(a.high_bidder, '')
+	     AND NOT EXISTS (SELECT 1 FROM invoices i
+	                      WHERE i.auction_id = b.auction_id AND i.party = 'buyer'
+	                        AND i.user_id = b.bidder_id)
+	     AND NOT EXISTS (SELECT 1 FROM second_chance_offers o
+	                      WHERE o.auction_id = b.auction_id AND o.bidder_id = b.bidder_id)
+	GROUP BY b.bidder_id
+	ORDER BY 2 DESC, 3`, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Candidate{}
+	for rows.Next() {
+		var c Candidate
+		if err := rows.Scan(&c.BidderID, &c.Amount, &c.LastBidAt); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// audit appends one entry; detail is marshalled to JSON.
+func audit(ctx context.Context, q querier, auctionID string, offerID int64, action, actor string, detail any) error {
+	b, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+	var oid *int64
+	if offerID != 0 {
+		oid = &offerID
+	}
+	_, err = q.ExecContext(ctx, `
+	  INSERT INTO second_chance_audit (auction_id, offer_id, action, actor, detail)
+	       VALUES ($1, $2, $3, $4, $5)`, auctionID, oid, action, actor, string(b))
+	return err
+}
+
+func (svc *secondChanceService) Candidates(ctx context.Context, auctionID string) ([]Candidate, error) {
+	var exists bool
+	if err := svc.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM auctions WHERE id = $1)`, auctionID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrAuctionNotFound
+	}
+	return candidates(ctx, svc.db, auctionID)
+}
+
+func (svc *secondChanceService) Offer(ctx context.Context, auctionID, actor, bidderID string) (*Offer, error) {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the row lock serialises offers, accepts and payments of the auction
+	var status, sellerID string
+	err = tx.QueryRowContext(ctx,
+		`SELECT status, seller_id FROM auctions WHERE id = $1 FOR UPDATE`, auctionID).Scan(&status, &sellerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAuctionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if actor != ActorSystem && actor != sellerID {
+		return nil, ErrNotSeller
+	}
+	if status != "UNPAID" {
+		return nil, ErrNotUnpaid
+	}
+	var open bool
+	if err = tx.QueryRowContext(ctx, `
+	  SELECT EXISTS (SELECT 1 FROM second_chance_offers
+	                  WHERE auction_id = $1 AND status = 'open')`, auctionID).Scan(&open); err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, ErrOfferOpen
+	}
+
+	cands, err := candidates(ctx, tx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cands) == 0 {
+		return nil, ErrNoCandidate
+	}
+	pick := cands[0]
+	if bidderID != "" {
+		found := false
+		for _, c := range cands {
+			if c.BidderID == bidderID {
+				pick, found = c, true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrNotEligible
+		}
+	}
+
+	o := Offer{AuctionID: auctionID, BidderID: pick.BidderID, Amount: pick.Amount, Status: StatusOpen}
+	if err = tx.QueryRowContext(ctx, `
+	  INSERT INTO second_chance_offers (auction_id, bidder_id, amount, expires_at)
+	       VALUES ($1, $2, $3, now() + make_interval(secs => $4))
+	    RETURNING id, expires_at, created_at`,
+		auctionID, pick.BidderID, pick.Amount, svc.cfg.TTL.Seconds()).Scan(&o.ID, &o.ExpiresAt, &o.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err = audit(ctx, tx, auctionID, o.ID, ActionOffered, actor, map[string]any{
+		"bidder_id":  o.BidderID,
+		"amount":     o.Amount,
+		"expires_at": o.ExpiresAt,
+		"candidates": len(cands),
+	}); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	svc.notifier.Dispatch(ctx, notify.Notification{
+		Key:       "second_chance:" + strconv.FormatInt(o.ID, 10),
+		UserID:    o.BidderID,
+		Kind:      notify.KindSecondChance,
+		AuctionID: auctionID,
+		Message:   fmt.Sprintf("Second chance: auction %s is offered to you at %.2f", auctionID, o.Amount),
+		Data:      notify.SecondChance{OfferID: o.ID, Amount: o.Amount, ExpiresAt: o.ExpiresAt},
+		CreatedAt: o.CreatedAt.UTC(),
+	})
+	return &o, nil
+}
+
+const selectOffer = `
+  SELECT id, auction_id, bidder_id, amount::float8, status, expires_at, created_at, responded_at
+    FROM second_chance_offers`
+
+func scanOffer(row interface{ Scan(...any) error }) (*Offer, error) {
+	var o Offer
+	err := row.Scan(&o.ID, &o.AuctionID, &o.BidderID, &o.Amount, &o.Status,
+		&o.ExpiresAt, &o.CreatedAt, &o.RespondedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return &o, err
+}
+
+func (svc *secondChanceService) get(ctx context.Context, id int64) (*Offer, error) {
+	return scanOffer(svc.db.QueryRowContext(ctx, selectOffer+` WHERE id = $1`, id))
+}
+
+func (svc *secondChanceService) Accept(ctx context.Context, offerID int64, bidderID string) (*Offer, error) {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	o, err := scanOffer(tx.QueryRowContext(ctx, selectOffer+` WHERE id = $1 FOR UPDATE`, offerID))
+	if err != nil {
+		return nil, err
+	}
+	if o.BidderID != bidderID {
+		return nil, ErrNotOfferee
+	}
+	if o.Status != StatusOpen || !o.ExpiresAt.After(time.Now()) {
+		return nil, ErrOfferClosed
+	}
+
+	// a late payment by the original winner beats the offer (auction PAID)
+	rs := Resettled{AuctionID: o.AuctionID, OfferID: o.ID, BuyerID: o.BidderID, Amount: o.Amount}
+	var status string
+	if err = tx.QueryRowContext(ctx, `
+	  SELECT status, seller_id, coalesce(high_bidder, '')
+	    FROM auctions WHERE id = $1 FOR UPDATE`, o.AuctionID).Scan(&status, &rs.SellerID, &rs.PreviousBuyer); err != nil {
+		return nil, err
+	}
+	if status != "UNPAID" {
+		return nil, ErrNotUnpaid
+	}
+	// back to FINISHED: the new buyer invoice runs its own payment deadline
+	if _, err = tx.ExecContext(ctx, `
+	  UPDATE auctions SET status = 'FINISHED', high_bid = $2, high_bidder = $3
+	   WHERE id = $1`, o.AuctionID, o.Amount, o.BidderID); err != nil {
+		return nil, err
+	}
+	if _, err = tx.ExecContext(ctx, `
+	  UPDATE second_chance_offers SET status = 'accepted', responded_at = now()
+	   WHERE id = $1`, o.ID); err != nil {
+		return nil, err
+	}
+	if err = audit(ctx, tx, o.AuctionID, o.ID, ActionAccepted, bidderID, map[string]any{
+		"amount":            o.Amount,
+		"previous_buyer_id": rs.PreviousBuyer,
+	}); err != nil {
+		return nil, err
+	}
+	if err = outbox.Write(ctx, tx, outbox.KindAuctionResettled, o.AuctionID, rs); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	svc.relay.Wake()
+	return svc.get(ctx, o.ID)
+}
+
+func (svc *secondChanceService) Decline(ctx context.Context, offerID int64, bidderID string) (*Offer, error) {
+	o, err := svc.get(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if o.BidderID != bidderID {
+		return nil, ErrNotOfferee
+	}
+	closed, err := svc.close(ctx, o.ID, StatusDeclined, ActionDeclined, bidderID)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, ErrOfferClosed
+	}
+	svc.OfferNext(ctx, o.AuctionID)
+	return svc.get(ctx, o.ID)
+}
+
+// close moves an open offer to status and audits it; false when the offer
+// was no longer open.
+func (svc *secondChanceService) close(ctx context.Context, id int64, status, action, actor string) (bool, error) {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var auctionID string
+	err = tx.QueryRowContext(ctx, `
+	  UPDATE second_chance_offers SET status = $2, responded_at = now()
+	   WHERE id = $1 AND status = 'open'
+	  RETURNING auction_id`, id, status).Scan(&auctionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err = audit(ctx, tx, auctionID, id, action, actor, map[string]any{}); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (svc *secondChanceService) OfferNext(ctx context.Context, auctionID string) {
+	if !svc.cfg.Auto {
+		return
+	}
+	_, err := svc.Offer(ctx, auctionID, ActorSystem, "")
+	switch {
+	case err == nil, errors.Is(err, ErrNoCandidate), errors.Is(err, ErrOfferOpen), errors.Is(err, ErrNotUnpaid):
+	default:
+		zap.L().Warn("secondchance.next", zap.String("auction", auctionID), zap.Error(err))
+	}
+}
+
+func (svc *secondChanceService) Resettle(ctx context.Context, rs Resettled) error {
+	invs, err := svc.settle.Resettle(ctx, settlement.Sale{
+		AuctionID: rs.AuctionID,
+		SellerID:  rs.SellerID,
+		BuyerID:   rs.BuyerID,
+		Hammer:    rs.Amount,
+	})
+	if errors.Is(err, settlement.ErrInvalidStatus) {
+		// the original winner paid in the meantime: record it, the seller
+		// sorts it out (refund one side)
+		return audit(ctx, svc.db, rs.AuctionID, rs.OfferID, ActionFailed, ActorSystem,
+			map[string]any{"error": err.Error()})
+	}
+	if err != nil || invs == nil {
+		return err // nil, nil: resettled on an earlier attempt
+	}
+	numbers := make([]string, 0, len(invs))
+	for _, inv := range invs {
+		numbers = append(numbers, inv.Number)
+	}
+	return audit(ctx, svc.db, rs.AuctionID, rs.OfferID, ActionResettled, ActorSystem, map[string]any{
+		"buyer_id": rs.BuyerID,
+		"amount":   rs.Amount,
+		"invoices": numbers,
+	})
+}
+
+func (svc *secondChanceService) ListForAuction(ctx context.Context, auctionID string) ([]Offer, error) {
+	rows, err := svc.db.QueryContext(ctx, selectOffer+` WHERE auction_id = $1 ORDER BY id`, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Offer{}
+	for rows.Next() {
+		o, err := scanOffer(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *o)
+	}
+	return out, rows.Err()
+}
+
+func (svc *secondChanceService) Audit(ctx context.Context, auctionID string) ([]AuditEntry, error) {
+	rows, err := svc.db.QueryContext(ctx, `
+	  SELECT id, auction_id, offer_id, action, actor, detail, at
+	    FROM second_chance_audit
+	   WHERE auction_id = $1
+	ORDER BY id`, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []AuditEntry{}
+	for rows.Next() {
+		var e AuditEntry
+		var detail []byte
+		if err := rows.Scan(&e.ID, &e.AuctionID, &e.OfferID, &e.Action, &e.Actor, &detail, &e.At); err != nil {
+			return nil, err
+		}
+		e.Detail = detail
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (svc *secondChanceService) RunExpiry(ctx context.Context, every time.Duration) {
+	tk := time.NewTicker(every)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+		}
+		if err := svc.expire(ctx); err != nil {
+			zap.L().Warn("secondchance.expiry", zap.Error(err))
+		}
+	}
+}
+
+func (svc *secondChanceService) expire(ctx context.Context) error {
+	rows, err := svc.db.QueryContext(ctx, `
+	  SELECT id, auction_id FROM second_chance_offers
+	   WHERE status = 'open' AND expires_at <= now()`)
+	if err != nil {
+		return err
+	}
+	due := map[int64]string{}
+	for rows.Next() {
+		var id int64
+		var aid string
+		if err := rows.Scan(&id, &aid); err != nil {
+			rows.Close()
+			return err
+		}
+		due[id] = aid
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, aid := range due {
+		closed, err := svc.close(ctx, id, StatusExpired, ActionExpired, ActorSystem)
+		if err != nil { // the next tick retries it; don't hold up the others
+			zap.L().Warn("secondchance.expire", zap.Int64("offer", id), zap.Error(err))
+			continue
+		}
+		if closed { // this instance expired it
+			svc.OfferNext(ctx, aid)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package settlement
+
+import (
+	"embed"
+	"html/template"
+	"io"
+	"strconv"
+
+	"github.com/go-pdf/fpdf"
+)
+
+//go:embed templates/invoice.html
+var templateFS embed.FS
+
+func money(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+
+// title is the document heading: buyers get an invoice, sellers a statement.
+func title(inv *Invoice) string {
+	if inv.Party == PartySeller {
+		return "Seller statement"
+	}
+	return "Invoice"
+}
+
+func totalLabel(inv *Invoice) string {
+	if inv.Party == PartySeller {
+		return "Net proceeds"
+	}
+	return "Amount due"
+}
+
+var htmlTemplate = template.Must(template.New("invoice.html").Funcs(template.FuncMap{
+	"money":      money,
+	"title":      title,
+	"totalLabel": totalLabel,
+}).ParseFS(templateFS, "templates/invoice.html"))
+
+// RenderHTML writes the invoice as a standalone HTML page.
+func RenderHTML(w io.Writer, inv *Invoice) error {
+	return htmlTemplate.Execute(w, inv)
+}
+
+// RenderPDF writes the invoice as a one‑page A4 PDF.
+func RenderPDF(w io.Writer, inv *Invoice) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("") // cp1252 core fonts
+	pdf.SetTitle(title(inv)+" "+inv.Number, true)
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, tr(title(inv)+" "+inv.Number), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(136, 136, 136)
+	pdf.CellFormat(0, 6, inv.Status, "", 1, "L", false, 0, "")
+	pdf.SetTextColor(34, 34, 34)
+
+	party := "Bill to: "
+	if inv.Party == PartySeller {
+		party = "Pay to: "
+	}
+	pdf.Ln(2)
+	for _, s := range []string{
+		party + inv.UserID,
+		"Auction: " + inv.AuctionID,
+		"Issued: " + inv.IssuedAt.Format("2006-01-02"),
+	} {
+		pdf.CellFormat(0, 6, tr(s), "", 1, "L", false, 0, "")
+	}
+	if inv.DueAt != nil && inv.Status == StatusIssued {
+		pdf.CellFormat(0, 6, "Due: "+inv.DueAt.Format("2006-01-02 15:04 MST"), "", 1, "L", false, 0, "")
+	}
+	if inv.PaidAt != nil {
+		pdf.CellFormat(0, 6, "Paid: "+inv.PaidAt.Format("2006-01-02"), "", 1, "L", false, 0, "")
+	}
+	if inv.VoidedAt != nil {
+		pdf.CellFormat(0, 6, "Voided: "+inv.VoidedAt.Format("2006-01-02"), "", 1, "L", false, 0, "")
+	}
+
+	pdf.Ln(6)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(140, 8, "Description", "B", 0, "L", false, 0, "")
+	pdf.CellFormat(40, 8, "Amount", "B", 1, "R", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	row := func(label, amount string) {
+		pdf.CellFormat(140, 7, tr(label), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(40, 7, amount, "B", 1, "R", false, 0, "")
+	}
+	for _, l := range inv.Lines {
+		row(l.Description, money(l.Amount))
+	}
+	row("Subtotal", money(inv.Subtotal))
+	row("Tax", money(inv.Tax))
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(140, 8, totalLabel(inv), "", 0, "L", false, 0, "")
+	pdf.CellFormat(40, 8, money(inv.Total), "", 1, "R", false, 0, "")
+
+	return pdf.Output(w)
+}
@@ -0,0 +1,29 @@
+package settlement
+
+import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/outbox"
+	"context"
+	"encoding/json"
+)
+
+// RegisterOutboxHandlers makes the relay settle every auction that finished
+// with a winner. Settle is idempotent, so retries are safe.
+func RegisterOutboxHandlers(r *outbox.Relay, svc ISettlementService) {
+	r.Handle(outbox.KindAuctionFinished, func(ctx context.Context, m outbox.Message) error {
+		var stop events.Stop
+		if err := json.Unmarshal(m.Payload, &stop); err != nil {
+			return err
+		}
+		if stop.HighBidder == "" || stop.HighBid <= 0 {
+			return nil // unsold: nothing to settle
+		}
+		_, err := svc.Settle(ctx, Sale{
+			AuctionID: m.AggregateID,
+			SellerID:  stop.SellerID,
+			BuyerID:   stop.HighBidder,
+			Hammer:    stop.HighBid,
+		})
+		return err
+	})
+}
@@ -0,0 +1,364 @@
+package settlement
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Invoice parties.
+const (
+	PartyBuyer  = "buyer"  // owes hammer + premium + tax
+	PartySeller = "seller" // receives hammer − commission − tax
+)
+
+// Invoice statuses.
+const (
+	StatusIssued = "issued"
+	StatusPaid   = "paid"
+	StatusVoid   = "void"
+)
+
+// Line kinds.
+const (
+	LineHammer        = "hammer"
+	LineBuyersPremium = "buyers_premium"
+	LineCommission    = "commission"
+	LineTax           = "tax"
+)
+
+var (
+	ErrNotFound      = errors.New("invoice not found")
+	ErrInvalidStatus = errors.New("invoice status does not allow this")
+)
+
+// Rates configure settlement: percentages (10 = 10 %), invoice numbering
+// and how long the buyer has to pay.
+type Rates struct {
+	BuyersPremiumPct    float64
+	SellerCommissionPct float64
+	TaxPct              float64
+	TaxOnHammer         bool // tax the hammer price too, not only the fees
+	NumberPrefix        string
+	PaymentTerms        time.Duration // buyer invoice due_at = issue + terms
+}
+
+// Line is one invoice position.
+type Line struct {
+	Kind        string  `json:"kind"        enums:"hammer,buyers_premium,commission,tax"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"` // negative for deductions
+}
+
+// Invoice is a buyer invoice or a seller statement.
+type Invoice struct {
+	ID        int64      `json:"id"`
+	Number    string     `json:"number"`
+	AuctionID string     `json:"auction_id"`
+	Party     string     `json:"party"  enums:"buyer,seller"`
+	UserID    string     `json:"user_id"`
+	Lines     []Line     `json:"lines"`
+	Subtotal  float64    `json:"subtotal"`
+	Tax       float64    `json:"tax"`
+	Total     float64    `json:"total"` // amount due (buyer) or payout (seller)
+	Status    string     `json:"status" enums:"issued,paid,void"`
+	IssuedAt  time.Time  `json:"issued_at"`
+	DueAt     *time.Time `json:"due_at,omitempty"` // buyer invoices
+	PaidAt    *time.Time `json:"paid_at,omitempty"`
+	VoidedAt  *time.Time `json:"voided_at,omitempty"`
+}
+
+// Sale is what gets settled: the auction, its parties and the hammer price.
+type Sale struct {
+	AuctionID string
+	Item      string // looked up from the auction when empty
+	SellerID  string
+	BuyerID   string
+	Hammer    float64
+}
+
+type ISettlementService interface {
+	// Settle issues the buyer invoice and seller statement of a sale. It is
+	// idempotent: an auction that already has live invoices is left as is.
+	Settle(ctx context.Context, s Sale) ([]Invoice, error)
+	// Resettle voids the auction's issued invoices and settles it again for
+	// another buyer (second‑chance offers). A no‑op when the live buyer
+	// invoice already is s.BuyerID's; ErrInvalidStatus if one was paid.
+	Resettle(ctx context.Context, s Sale) ([]Invoice, error)
+	Get(ctx context.Context, id int64) (*Invoice, error)
+	// ListForUser returns the user's invoices, newest first; status "" = all.
+	ListForUser(ctx context.Context, userID, status string) ([]Invoice, error)
+	// ListForAuction returns the auction's invoices, void ones included.
+	ListForAuction(ctx context.Context, auctionID string) ([]Invoice, error)
+	// Void cancels an issued invoice (paying is internal/services/payment's job).
+	Void(ctx context.Context, id int64) (*Invoice, error)
+}
+
+type settlementService struct {
+	db    *sql.DB
+	rates Rates
+}
+
+var _ ISettlementService = (*settlementService)(nil)
+
+func NewSettlementService(db *sql.DB, rates Rates) ISettlementService {
+	if rates.NumberPrefix == "" {
+		rates.NumberPrefix = "INV"
+	}
+	if rates.PaymentTerms <= 0 {
+		rates.PaymentTerms = 72 * time.Hour
+	}
+	return &settlementService{db: db, rates: rates}
+}
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
+
+func pct(v float64) string { return fmt.Sprintf("%g %%", v) }
+
+// Compute returns the buyer's and the seller's lines for a hammer price.
+func Compute(r Rates, item string, hammer float64) (buyer, seller []Line) {
+	hammer = round2(hammer)
+	premium := round2(hammer * r.BuyersPremiumPct / 100)
+	commission := round2(hammer * r.SellerCommissionPct / 100)
+
+	buyer = []Line{{LineHammer, "Hammer price – " + item, hammer}}
+	if premium != 0 {
+		buyer = append(buyer, Line{LineBuyersPremium, "Buyer's premium " + pct(r.BuyersPremiumPct), premium})
+	}
+	taxable := premium
+	if r.TaxOnHammer {
+		taxable += hammer
+	}
+	if tax := round2(taxable * r.TaxPct / 100); tax != 0 {
+		buyer = append(buyer, Line{LineTax, "Tax " + pct(r.TaxPct), tax})
+	}
+
+	seller = []Line{{LineHammer, "Hammer price – " + item, hammer}}
+	if commission != 0 {
+		seller = append(seller, Line{LineCommission, "Seller commission " + pct(r.SellerCommissionPct), -commission})
+		if tax := round2(commission * r.TaxPct / 100); tax != 0 {
+			seller = append(seller, Line{LineTax, "Tax on commission " + pct(r.TaxPct), -tax})
+		}
+	}
+	return buyer, seller
+}
+
+// totals splits lines into subtotal (everything but tax) and tax.
+func totals(lines []Line) (subtotal, tax, total float64) {
+	for _, l := range lines {
+		if l.Kind == LineTax {
+			tax += l.Amount
+		} else {
+			subtotal += l.Amount
+		}
+	}
+	subtotal, tax = round2(subtotal), round2(tax)
+	return subtotal, tax, round2(subtotal + tax)
+}
+
+func (svc *settlementService) Settle(ctx context.Context, s Sale) ([]Invoice, error) {
+	tx, err := svc.lock(ctx, s.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM invoices WHERE auction_id = $1 AND status <> 'void')`,
+		s.AuctionID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, nil
+	}
+	return svc.issue(ctx, tx, s)
+}
+
+func (svc *settlementService) Resettle(ctx context.Context, s Sale) ([]Invoice, error) {
+	tx, err := svc.lock(ctx, s.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var buyer sql.NullString
+	var paid bool
+	if err = tx.QueryRowContext(ctx, `
+	  SELECT max(user_id) FILTER (WHERE party = 'buyer'), coalesce(bool_or(status = 'paid'), false)
+	    FROM invoices
+	   WHERE auction_id = $1 AND status <> 'void'`, s.AuctionID).Scan(&buyer, &paid); err != nil {
+		return nil, err
+	}
+	if buyer.String == s.BuyerID {
+		return nil, nil // already done
+	}
+	if paid {
+		return nil, ErrInvalidStatus
+	}
+	if _, err = tx.ExecContext(ctx, `
+	  UPDATE invoices SET status = 'void', voided_at = now()
+	   WHERE auction_id = $1 AND status = 'issued'`, s.AuctionID); err != nil {
+		return nil, err
+	}
+	return svc.issue(ctx, tx, s)
+}
+
+// lock opens a transaction holding the auction's settlement lock, which
+// serialises concurrent (re)settlements of the same auction.
+func (svc *settlementService) lock(ctx context.Context, auctionID string) (*sql.Tx, error) {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, "settle:"+auctionID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return tx, nil
+}
+
+// issue inserts the buyer invoice and seller statement of s and commits tx.
+func (svc *settlementService) issue(ctx context.Context, tx *sql.Tx, s Sale) ([]Invoice, error) {
+	var err error
+	if s.Item == "" {
+		err = tx.QueryRowContext(ctx, `SELECT item FROM auctions WHERE id = $1`, s.AuctionID).Scan(&s.Item)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	buyerLines, sellerLines := Compute(svc.rates, s.Item, s.Hammer)
+	out := make([]Invoice, 0, 2)
+	for _, inv := range []Invoice{
+		{AuctionID: s.AuctionID, Party: PartyBuyer, UserID: s.BuyerID, Lines: buyerLines},
+		{AuctionID: s.AuctionID, Party: PartySeller, UserID: s.SellerID, Lines: sellerLines},
+	} {
+		if err := svc.insert(ctx, tx, &inv); err != nil {
+			return nil, err
+		}
+		out = append(out, inv)
+	}
+	return out, tx.Commit()
+}
+
+func (svc *settlementService) insert(ctx context.Context, tx *sql.Tx, inv *Invoice) error {
+	inv.Subtotal, inv.Tax, inv.Total = totals(inv.Lines)
+	inv.Status = StatusIssued
+
+	const ins = `
+	  INSERT INTO invoices (number, auction_id, party, user_id, subtotal, tax, total, due_at)
+	       VALUES ($1 || '-' || to_char(now(), 'YYYY') || '-' || lpad(nextval('invoice_number_seq')::text, 6, '0'),
+	               $2, $3, $4, $5, $6, $7,
+	               CASE WHEN $3 = 'buyer' THEN now() + make_interval(secs => $8) END)
+	    RETURNING id, number, issued_at, due_at`
+	if err := tx.QueryRowContext(ctx, ins, svc.rates.NumberPrefix, inv.AuctionID, inv.Party, inv.UserID,
+		inv.Subtotal, inv.Tax, inv.Total, svc.rates.PaymentTerms.Seconds()).Scan(&inv.ID, &inv.Number, &inv.IssuedAt, &inv.DueAt); err != nil {
+		return err
+	}
+	const insLine = `
+	  INSERT INTO invoice_lines (invoice_id, position, kind, description, amount)
+	       VALUES ($1, $2, $3, $4, $5)`
+	for i, l := range inv.Lines {
+		if _, err := tx.ExecContext(ctx, insLine, inv.ID, i+1, l.Kind, l.Description, l.Amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const selectInvoice = `
+  SELECT id, number, auction_id, party, user_id, subtotal::float8, tax::float8, total::float8,
+         status, issued_at, due_at, paid_at, voided_at
+    FROM invoices`
+
+func (svc *settlementService) Get(ctx context.Context, id int64) (*Invoice, error) {
+	out, err := svc.query(ctx, selectInvoice+` WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, ErrNotFound
+	}
+	return &out[0], nil
+}
+
+func (svc *settlementService) ListForUser(ctx context.Context, userID, status string) ([]Invoice, error) {
+	return svc.query(ctx, selectInvoice+`
+	   WHERE user_id = $1 AND ($2 = '' OR status = $2)
+	ORDER BY issued_at DESC, id DESC`, userID, status)
+}
+
+func (svc *settlementService) ListForAuction(ctx context.Context, auctionID string) ([]Invoice, error) {
+	return svc.query(ctx, selectInvoice+` WHERE auction_id = $1 ORDER BY id`, auctionID)
+}
+
+// query runs an invoice select and attaches the lines.
+func (svc *settlementService) query(ctx context.Context, q string, args ...any) ([]Invoice, error) {
+	rows, err := svc.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Invoice{}
+	byID := map[int64]int{}
+	ids := []int64{}
+	for rows.Next() {
+		var inv Invoice
+		if err := rows.Scan(&inv.ID, &inv.Number, &inv.AuctionID, &inv.Party, &inv.UserID,
+			&inv.Subtotal, &inv.Tax, &inv.Total, &inv.Status, &inv.IssuedAt, &inv.DueAt, &inv.PaidAt, &inv.VoidedAt); err != nil {
+			return nil, err
+		}
+		inv.Lines = []Line{}
+		byID[inv.ID] = len(out)
+		ids = append(ids, inv.ID)
+		out = append(out, inv)
+	}
+	if err := rows.Err(); err != nil || len(ids) == 0 {
+		return out, err
+	}
+
+	lines, err := svc.db.QueryContext(ctx, `
+	  SELECT invoice_id, kind, description, amount::float8
+	    FROM invoice_lines
+	   WHERE invoice_id = ANY($1)
+	ORDER BY invoice_id, position`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer lines.Close()
+	for lines.Next() {
+		var id int64
+		var l Line
+		if err := lines.Scan(&id, &l.Kind, &l.Description, &l.Amount); err != nil {
+			return nil, err
+		}
+		inv := &out[byID[id]]
+		inv.Lines = append(inv.Lines, l)
+	}
+	return out, lines.Err()
+}
+
+func (svc *settlementService) Void(ctx context.Context, id int64) (*Invoice, error) {
+	return svc.transition(ctx, id, `status = 'void', voided_at = now()`, StatusIssued)
+}
+
+// transition applies set to an invoice currently in status from.
+func (svc *settlementService) transition(ctx context.Context, id int64, set, from string) (*Invoice, error) {
+	res, err := svc.db.ExecContext(ctx,
+		`UPDATE invoices SET `+set+` WHERE id = $1 AND status = $2`, id, from)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		if _, err := svc.Get(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidStatus
+	}
+	return svc.Get(ctx, id)
+}
@@ -0,0 +1,113 @@
+package watchlist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrAuctionNotFound = errors.New("auction not found")
+
+// Item is one watched auction with its last persisted state.
+type Item struct {
+	AuctionID  string    `json:"auction_id"`
+	Item       string    `json:"item"`
+	Status     string    `json:"status"`
+	EndsAt     time.Time `json:"ends_at"`
+	HighBid    float64   `json:"high_bid"`
+	HighBidder string    `json:"high_bidder,omitempty"`
+	AddedAt    time.Time `json:"added_at"`
+}
+
+type IWatchlistService interface {
+	// Add watches an auction; adding it again is a no‑op.
+	Add(ctx context.Context, userID, auctionID string) error
+	Remove(ctx context.Context, userID, auctionID string) error
+	// List returns the user's watched auctions, most recently added first.
+	List(ctx context.Context, userID string) ([]Item, error)
+	// Watchers returns the users watching an auction.
+	Watchers(ctx context.Context, auctionID string) ([]string, error)
+}
+
+type watchlistService struct {
+	db *sql.DB
+}
+
+var _ IWatchlistService = (*watchlistService)(nil)
+
+func NewWatchlistService(db *sql.DB) IWatchlistService {
+	return &watchlistService{db: db}
+}
+
+func (svc *watchlistService) Add(ctx context.Context, userID, auctionID string) error {
+	const q = `
+	  INSERT INTO watchlists (user_id, auction_id)
+	       SELECT $1, id FROM auctions WHERE id = $2
+	  ON CONFLICT DO NOTHING`
+	if _, err := svc.db.ExecContext(ctx, q, userID, auctionID); err != nil {
+		return err
+	}
+	// nothing inserted: already watched, or no such auction
+	var ok bool
+	err := svc.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM auctions WHERE id = $1)`, auctionID).Scan(&ok)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAuctionNotFound
+	}
+	return nil
+}
+
+func (svc *watchlistService) Remove(ctx context.Context, userID, auctionID string) error {
+	_, err := svc.db.ExecContext(ctx,
+		`DELETE FROM watchlists WHERE user_id = $1 AND auction_id = $2`, userID, auctionID)
+	return err
+}
+
+func (svc *watchlistService) List(ctx context.Context, userID string) ([]Item, error) {
+	const q = `
+	  SELECT a.id, a.item, a.status, a.ends_at,
+	         coalesce(a.high_bid, 0)::float8, coalesce(a.high_bidder, ''), w.created_at
+	    FROM watchlists w
+	    JOIN auctions a ON a.id = w.auction_id
+	   WHERE w.user_id = $1
+	ORDER BY w.created_at DESC`
+	rows, err := svc.db.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Item{}
+	for rows.Next() {
+		var it Item
+		if err := rows.Scan(&it.AuctionID, &it.Item, &it.Status, &it.EndsAt,
+			&it.HighBid, &it.HighBidder, &it.AddedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, it)
+	}
+	return out, rows.Err()
+}
+
+func (svc *watchlistService) Watchers(ctx context.Context, auctionID string) ([]string, error) {
+	rows, err := svc.db.QueryContext(ctx,
+		`SELECT user_id FROM watchlists WHERE auction_id = $1`, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
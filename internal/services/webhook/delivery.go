@@ -0,0 +1,218 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DeliveryConfig tunes the delivery worker.
+type DeliveryConfig struct {
+	MaxAttempts  int           // after this many failures a delivery is dead‑lettered
+	BackoffBase  time.Duration // delay after the first failure; doubles per attempt
+	BackoffMax   time.Duration // cap on the delay
+	Timeout      time.Duration // per‑request timeout
+	PollInterval time.Duration // how often the queue is checked
+	BatchSize    int           // deliveries claimed per poll
+}
+
+func (c *DeliveryConfig) defaults() {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 8
+	}
+	if c.BackoffBase <= 0 {
+		c.BackoffBase = 2 * time.Second
+	}
+	if c.BackoffMax <= 0 {
+		c.BackoffMax = 10 * time.Minute
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 5 * time.Second
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = time.Second
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 20
+	}
+}
+
+// Backoff returns the delay before retry number attempt (1‑based).
+func (c DeliveryConfig) Backoff(attempt int) time.Duration {
+	d := c.BackoffBase
+	for i := 1; i < attempt && d < c.BackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, c.BackoffMax)
+}
+
+// claimLease keeps a claimed delivery invisible to other workers while its
+// request is in flight; a worker that dies mid‑request releases it this way.
+const claimLease = time.Minute
+
+type delivery struct {
+	id       int64
+	url      string
+	secret   string
+	typ      string
+	payload  []byte
+	attempts int
+}
+
+// RunDeliveries polls the delivery queue and POSTs due deliveries until ctx
+// is done. Several instances may run it: rows are claimed with SKIP LOCKED.
+// client may be nil (a client with cfg.Timeout is used); tests can point
+// subscriptions at an httptest.Server.
+func RunDeliveries(ctx context.Context, db *sql.DB, client *http.Client, cfg DeliveryConfig) {
+	cfg.defaults()
+	if client == nil {
+		client = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	tk := time.NewTicker(cfg.PollInterval)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+		}
+
+		batch, err := claim(ctx, db, cfg.BatchSize)
+		if err != nil {
+			zap.L().Warn("webhook.claim", zap.Error(err))
+			continue
+		}
+		for _, d := range batch {
+			status, err := post(ctx, client, d)
+			record(ctx, db, cfg, d, status, err)
+		}
+	}
+}
+
+func claim(ctx context.Context, db *sql.DB, n int) ([]delivery, error) {
+	const q = `
+	  UPDATE webhook_deliveries d
+	     SET next_attempt_at = now() + make_interval(secs => $2)
+	    FROM webhook_subscriptions s
+	   WHERE s.id = d.subscription_id
+	     AND d.id IN (SELECT id FROM webhook_deliveries
+	                   WHERE status = 'pending' AND next_attempt_at <= now()
+	                ORDER BY next_attempt_at, id
+	                   LIMIT $1
+	                     FOR UPDATE SKIP LOCKED)
+	RETURNING d.id, s.url, s.secret, d.event_type, d.payload, d.attempts`
+	rows, err := db.QueryContext(ctx, q, n, claimLease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []delivery
+	for rows.Next() {
+		var d delivery
+		if err := rows.Scan(&d.id, &d.url, &d.secret, &d.typ, &d.payload, &d.attempts); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// post sends one delivery; any 2xx counts as success.
+func post(ctx context.Context, client *http.Client, d delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(d.payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "auctionbidgo-webhooks/1")
+	req.Header.Set(HeaderEvent, d.typ)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(d.id, 10))
+	req.Header.Set(HeaderSignature, Sign(d.secret, time.Now(), d.payload))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// record stores the outcome: delivered, retry later, or dead‑lettered.
+func record(ctx context.Context, db *sql.DB, cfg DeliveryConfig, d delivery, status int, sendErr error) {
+	var lastStatus *int
+	if status != 0 {
+		lastStatus = &status
+	}
+	attempts := d.attempts + 1
+
+	if sendErr == nil {
+		const q = `
+		  UPDATE webhook_deliveries
+		     SET status = 'delivered', attempts = $2, last_status = $3,
+		         last_error = NULL, delivered_at = now()
+		   WHERE id = $1`
+		if _, err := db.ExecContext(ctx, q, d.id, attempts, lastStatus); err != nil {
+			zap.L().Warn("webhook.record", zap.Int64("delivery", d.id), zap.Error(err))
+		}
+		return
+	}
+
+	if attempts < cfg.MaxAttempts {
+		const q = `
+		  UPDATE webhook_deliveries
+		     SET attempts = $2, last_status = $3, last_error = $4,
+		         next_attempt_at = now() + make_interval(secs => $5)
+		   WHERE id = $1`
+		if _, err := db.ExecContext(ctx, q, d.id, attempts, lastStatus, sendErr.Error(),
+			cfg.Backoff(attempts).Seconds()); err != nil {
+			zap.L().Warn("webhook.record", zap.Int64("delivery", d.id), zap.Error(err))
+		}
+		return
+	}
+
+	zap.L().Warn("webhook.dead_letter",
+		zap.Int64("delivery", d.id), zap.String("url", d.url), zap.Error(sendErr))
+	if err := deadLetter(ctx, db, d.id, attempts, lastStatus, sendErr.Error()); err != nil {
+		zap.L().Warn("webhook.record", zap.Int64("delivery", d.id), zap.Error(err))
+	}
+}
+
+func deadLetter(ctx context.Context, db *sql.DB, id int64, attempts int, lastStatus *int, lastErr string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	const upd = `
+	  UPDATE webhook_deliveries
+	     SET status = 'dead', attempts = $2, last_status = $3, last_error = $4
+	   WHERE id = $1`
+	if _, err = tx.ExecContext(ctx, upd, id, attempts, lastStatus, lastErr); err != nil {
+		return err
+	}
+	const ins = `
+	  INSERT INTO webhook_dead_letters (delivery_id, subscription_id, event_type,
+	                                    payload, attempts, last_status, last_error)
+	       SELECT id, subscription_id, event_type, payload, attempts, last_status, last_error
+	         FROM webhook_deliveries
+	        WHERE id = $1`
+	if _, err = tx.ExecContext(ctx, ins, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
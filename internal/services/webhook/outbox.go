@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"auctionbidgo/internal/outbox"
+	"context"
+	"strconv"
+)
+
+// FinishedKey is the delivery key of an auction's auction.finished event.
+// Both the outbox relay and the pub/sub feed use it, so whichever sees the
+// stop first enqueues it and the other is a no‑op.
+func FinishedKey(auctionID string) string { return auctionID + ":finished" }
+
+// RegisterOutboxHandlers makes the relay enqueue auction.finished,
+// auction.paid, auction.unpaid and auction.resettled webhooks from the outbox – the
+// at‑least‑once path, independent of pub/sub.
+func RegisterOutboxHandlers(r *outbox.Relay, svc IWebhookService) {
+	r.Handle(outbox.KindAuctionFinished, func(ctx context.Context, m outbox.Message) error {
+		return svc.Enqueue(ctx, Event{
+			Key:        FinishedKey(m.AggregateID),
+			Type:       EventAuctionFinished,
+			AuctionID:  m.AggregateID,
+			OccurredAt: m.CreatedAt.UTC(),
+			Data:       m.Payload, // events.Stop
+		})
+	})
+	for kind, typ := range map[string]string{
+		outbox.KindAuctionPaid:      EventAuctionPaid,
+		outbox.KindAuctionUnpaid:    EventAuctionUnpaid,
+		outbox.KindAuctionResettled: EventAuctionResettled,
+	} {
+		r.Handle(kind, func(ctx context.Context, m outbox.Message) error {
+			return svc.Enqueue(ctx, Event{
+				Key:        "outbox:" + strconv.FormatInt(m.ID, 10),
+				Type:       typ,
+				AuctionID:  m.AggregateID,
+				OccurredAt: m.CreatedAt.UTC(),
+				Data:       m.Payload, // payment.PaidPayload, secondchance.Resettled
+			})
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// Event types a subscription can filter on.
+const (
+	EventAuctionStarted   = "auction.started"
+	EventAuctionBid       = "auction.bid"
+	EventAuctionOutbid    = "auction.outbid"
+	EventAuctionExtended  = "auction.extended"
+	EventAuctionFinished  = "auction.finished"
+	EventAuctionPaid      = "auction.paid"
+	EventAuctionUnpaid    = "auction.unpaid"    // payment deadline missed
+	EventAuctionResettled = "auction.resettled" // sold to a runner‑up instead
+
+	// EventUserNotification carries a per‑user notification (internal/notify).
+	EventUserNotification = "user.notification"
+)
+
+// EventTypes lists every type, in lifecycle order.
+var EventTypes = []string{
+	EventAuctionStarted,
+	EventAuctionBid,
+	EventAuctionOutbid,
+	EventAuctionExtended,
+	EventAuctionFinished,
+	EventAuctionPaid,
+	EventAuctionUnpaid,
+	EventAuctionResettled,
+	EventUserNotification,
+}
+
+var (
+	ErrNotFound         = errors.New("webhook not found")
+	ErrInvalidURL       = errors.New("webhook url must be absolute http(s)")
+	ErrUnknownEventType = errors.New("unknown webhook event type")
+)
+
+// Subscription is one registered endpoint.
+type Subscription struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"` // empty = every type
+	Secret     string    `json:"secret,omitempty"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// DeadLetter is a delivery that ran out of attempts.
+type DeadLetter struct {
+	ID             int64           `json:"id"`
+	DeliveryID     int64           `json:"delivery_id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Attempts       int             `json:"attempts"`
+	LastStatus     *int            `json:"last_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	FailedAt       time.Time       `json:"failed_at"`
+}
+
+// Event is what gets POSTed, as JSON, to matching subscriptions.
+type Event struct {
+	Key        string    `json:"id"` // "<auction_id>:<seq>", stable across retries
+	Type       string    `json:"type"`
+	AuctionID  string    `json:"auction_id"`
+	Seq        int64     `json:"seq"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"` // the typed events.* body
+}
+
+// SubscriptionPatch holds the fields an update may change; nil = keep.
+type SubscriptionPatch struct {
+	URL        *string
+	EventTypes *[]string
+	Secret     *string
+	Active     *bool
+}
+
+type IWebhookService interface {
+	Create(ctx context.Context, rawURL string, eventTypes []string, secret string) (*Subscription, error)
+	Get(ctx context.Context, id int64) (*Subscription, error)
+	List(ctx context.Context) ([]Subscription, error)
+	Update(ctx context.Context, id int64, p SubscriptionPatch) (*Subscription, error)
+	Delete(ctx context.Context, id int64) error
+
+	// Enqueue queues ev for every active subscription that wants its type.
+	// Enqueuing the same ev.Key twice is a no‑op.
+	Enqueue(ctx context.Context, ev Event) error
+
+	ListDeadLetters(ctx context.Context, limit, offset int) ([]DeadLetter, error)
+	// Redeliver moves a dead letter back onto the delivery queue.
+	Redeliver(ctx context.Context, deadLetterID int64) error
+}
+
+type webhookService struct {
+	db *sql.DB
+}
+
+var _ IWebhookService = (*webhookService)(nil)
+
+func NewWebhookService(db *sql.DB) IWebhookService {
+	return &webhookService{db: db}
+}
+
+func validate(rawURL string, eventTypes []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	for _, t := range eventTypes {
+		if !slices.Contains(EventTypes, t) {
+			return ErrUnknownEventType
+		}
+	}
+	return nil
+}
+
+// newSecret returns 32 random bytes, hex encoded.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Create registers an endpoint. An empty secret is generated; the secret is
+// only returned here, never by Get/List.
+func (svc *webhookService) Create(ctx context.Context, rawURL string, eventTypes []string, secret string) (*Subscription, error) {
+	if err := validate(rawURL, eventTypes); err != nil {
+		return nil, err
+	}
+	if secret == "" {
+		var err error
+		if secret, err = newSecret(); err != nil {
+			return nil, err
+		}
+	}
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	types, _ := json.Marshal(eventTypes)
+
+	s := &Subscription{URL: rawURL, EventTypes: eventTypes, Secret: secret, Active: true}
+	const q = `
+	  INSERT INTO webhook_subscriptions (url, event_types, secret)
+	       VALUES ($1, $2, $3)
+	    RETURNING id, created_at, updated_at`
+	if err := svc.db.QueryRowContext(ctx, q, rawURL, string(types), secret).
+		Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+const selectSubscription = `
+	SELECT id, url, event_types, active, created_at, updated_at
+	  FROM webhook_subscriptions`
+
+func scanSubscription(row interface{ Scan(...any) error }) (*Subscription, error) {
+	var s Subscription
+	var types []byte
+	if err := row.Scan(&s.ID, &s.URL, &types, &s.Active, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(types, &s.EventTypes); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (svc *webhookService) Get(ctx context.Context, id int64) (*Subscription, error) {
+	s, err := scanSubscription(svc.db.QueryRowContext(ctx, selectSubscription+` WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return s, err
+}
+
+func (svc *webhookService) List(ctx context.Context) ([]Subscription, error) {
+	rows, err := svc.db.QueryContext(ctx, selectSubscription+` ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Subscription{}
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *s)
+	}
+	return out, rows.Err()
+}
+
+func (svc *webhookService) Update(ctx context.Context, id int64, p SubscriptionPatch) (*Subscription, error) {
+	cur, err := svc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.URL != nil {
+		cur.URL = *p.URL
+	}
+	if p.EventTypes != nil {
+		cur.EventTypes = *p.EventTypes
+		if cur.EventTypes == nil {
+			cur.EventTypes = []string{}
+		}
+	}
+	if p.Active != nil {
+		cur.Active = *p.Active
+	}
+	if err := validate(cur.URL, cur.EventTypes); err != nil {
+		return nil, err
+	}
+	types, _ := json.Marshal(cur.EventTypes)
+
+	const q = `
+	  UPDATE webhook_subscriptions
+	     SET url = $2, event_types = $3, active = $4,
+	         secret = COALESCE($5, secret), updated_at = now()
+	   WHERE id = $1
+	RETURNING updated_at`
+	if err := svc.db.QueryRowContext(ctx, q, id, cur.URL, string(types), cur.Active, p.Secret).
+		Scan(&cur.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return cur, nil
+}
+
+func (svc *webhookService) Delete(ctx context.Context, id int64) error {
+	res, err := svc.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (svc *webhookService) Enqueue(ctx context.Context, ev Event) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	// One row per matching subscription; the unique (subscription, key)
+	// constraint makes concurrent enqueues from several instances harmless.
+	const q = `
+	  INSERT INTO webhook_deliveries (subscription_id, event_key, event_type, payload)
+	       SELECT id, $1, $2, $3
+	         FROM webhook_subscriptions
+	        WHERE active
+	          AND (event_types = '[]'::jsonb OR event_types ? $2)
+	  ON CONFLICT (subscription_id, event_key) DO NOTHING`
+	_, err = svc.db.ExecContext(ctx, q, ev.Key, ev.Type, string(payload))
+	return err
+}
+
+func (svc *webhookService) ListDeadLetters(ctx context.Context, limit, offset int) ([]DeadLetter, error) {
+	const q = `
+	  SELECT id, delivery_id, subscription_id, event_type, payload,
+	         attempts, last_status, COALESCE(last_error, ''), failed_at
+	    FROM webhook_dead_letters
+	ORDER BY id DESC
+	   LIMIT $1 OFFSET $2`
+	rows, err := svc.db.QueryContext(ctx, q, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []DeadLetter{}
+	for rows.Next() {
+		var d DeadLetter
+		var payload []byte
+		if err := rows.Scan(&d.ID, &d.DeliveryID, &d.SubscriptionID, &d.EventType, &payload,
+			&d.Attempts, &d.LastStatus, &d.LastError, &d.FailedAt); err != nil {
+			return nil, err
+		}
+		d.Payload = payload
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (svc *webhookService) Redeliver(ctx context.Context, deadLetterID int64) error {
+	tx, err := svc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var deliveryID int64
+	err = tx.QueryRowContext(ctx,
+		`DELETE FROM webhook_dead_letters WHERE id = $1 RETURNING delivery_id`,
+		deadLetterID).Scan(&deliveryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	// A fresh retry budget; the worker picks it up on its next poll.
+	const q = `
+	  UPDATE webhook_deliveries
+	     SET status = 'pending', attempts = 0, next_attempt_at = now()
+	   WHERE id = $1`
+	if _, err = tx.ExecContext(ctx, q, deliveryID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -12,7 +12,9 @@ import (
 
 const stream = "bids_stream"
 
-// Run tails the Redis stream and persists every bid.
+// Run tails the Redis stream and persists every bid. Entries with undo set
+// retract a bid of a live auction (auction_live): the latest matching row
+// is deleted.
 func Run(ctx context.Context, rdc *redis.Client, db *sql.DB) {
 	go func() {
 		lastID := "0-0"
@@ -51,9 +53,13 @@ func persist(ctx context.Context, db *sql.DB, msgs []redis.XMessage) error {
 	if err != nil {
 		return err
 	}
-	const ins = `INSERT INTO bids (auction_id, bidder_id, amount, placed_at)
-	             VALUES ($1, $2, $3, to_timestamp($4))
+	const ins = `INSERT INTO bids (auction_id, bidder_id, amount, placed_at, source, entered_by)
+	             VALUES ($1, $2, $3, to_timestamp($4), coalesce(nullif($5, ''), 'online'), nullif($6, ''))
 	             ON CONFLICT DO NOTHING`
+	const del = `DELETE FROM bids
+	              WHERE id = (SELECT id FROM bids
+	                           WHERE auction_id = $1 AND bidder_id = $2 AND amount = $3
+	                        ORDER BY placed_at DESC, id DESC LIMIT 1)`
 	for _, m := range msgs {
 		aid := m.Values["aid"].(string)
 		bidder := m.Values["bidder"].(string)
@@ -67,7 +73,16 @@ func persist(ctx context.Context, db *sql.DB, msgs []redis.XMessage) error {
 		// )
 		amount, _ := strconv.ParseFloat(amt, 64)
 		ts, _ := strconv.ParseInt(at, 10, 64)
-		if _, err := tx.ExecContext(ctx, ins, aid, bidder, amount, ts); err != nil {
+		if _, undo := m.Values["undo"]; undo {
+			if _, err := tx.ExecContext(ctx, del, aid, bidder, amount); err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+			continue
+		}
+		src, _ := m.Values["src"].(string)
+		by, _ := m.Values["by"].(string)
+		if _, err := tx.ExecContext(ctx, ins, aid, bidder, amount, ts, src, by); err != nil {
 			_ = tx.Rollback()
 			return err
 		}
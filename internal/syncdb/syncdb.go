@@ -15,7 +15,8 @@ const (
 	pipeTimeout = 1500 * time.Millisecond
 )
 
-// Every 10 s, mirror "active" auctions' high bid -> Postgres.
+// Every 10 s, mirror "active" auctions' high bid (and end, which soft close
+// may move) -> Postgres.
 func Run(ctx context.Context, rdc *redis.Client, db *sql.DB) {
 	tk := time.NewTicker(10 * time.Second)
 	go func() {
@@ -56,7 +57,8 @@ func syncOnce(ctx context.Context, rdc *redis.Client, db *sql.DB) {
 	     VALUES ($1,$2,'',to_timestamp($3),to_timestamp($4),
 	             'RUNNING',$5,$6)
 	ON CONFLICT (id) DO UPDATE
-	       SET high_bid=EXCLUDED.high_bid,
+	       SET ends_at=EXCLUDED.ends_at,
+	           high_bid=EXCLUDED.high_bid,
 	           high_bidder=EXCLUDED.high_bidder`
 
 	tx, err := db.BeginTx(ctx, nil)
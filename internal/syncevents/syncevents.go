@@ -0,0 +1,156 @@
+package syncevents
+
+import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/services/history"
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	stream = "auc_events" // global copy of every sequenced auction event
+	group  = "history"
+
+	trimEvery = 10 * time.Second // how often acknowledged entries are dropped
+	claimIdle = time.Minute      // pending that long, the consumer is presumed dead
+)
+
+// Run consumes the global event stream through a consumer group – shared by
+// all instances, resumed after restarts – and appends the lifecycle and bid
+// events to the auction_events history. Entries are acknowledged only once
+// persisted, so a crash replays them (the insert is idempotent). The
+// stream has no MAXLEN; it is trimmed here, behind the group (trim).
+func Run(ctx context.Context, rdc *redis.Client, svc history.IHistoryService) {
+	err := rdc.XGroupCreateMkStream(ctx, stream, group, "0").Err()
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		zap.L().Error("syncevents.group", zap.Error(err))
+		return
+	}
+	host, _ := os.Hostname()
+	consumer := host + "-" + uuid.NewString()[:8]
+
+	go func() {
+		start := "0" // first our own pending entries, then new ones
+		lastTrim := time.Now()
+		for {
+			if time.Since(lastTrim) >= trimEvery {
+				if claimStale(ctx, rdc, consumer) {
+					start = "0"
+				}
+				trim(ctx, rdc)
+				lastTrim = time.Now()
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			res, err := rdc.XReadGroup(ctx, &redis.XReadGroupArgs{
+				Group:    group,
+				Consumer: consumer,
+				Streams:  []string{stream, start},
+				Count:    100,
+				Block:    2000 * time.Millisecond,
+			}).Result()
+			if err != nil && err != redis.Nil {
+				zap.L().Warn("syncevents.xreadgroup", zap.Error(err))
+				time.Sleep(time.Second)
+				continue
+			}
+			if len(res) == 0 || len(res[0].Messages) == 0 {
+				start = ">"
+				continue
+			}
+
+			msgs := res[0].Messages
+			if err := svc.Record(ctx, toEntries(msgs)); err != nil {
+				zap.L().Warn("syncevents.record", zap.Error(err))
+				time.Sleep(time.Second)
+				start = "0" // retry from our pending list
+				continue
+			}
+			ids := make([]string, len(msgs))
+			for i, m := range msgs {
+				ids[i] = m.ID
+			}
+			_ = rdc.XAck(ctx, stream, group, ids...).Err()
+		}
+	}()
+}
+
+// claimStale takes over entries a dead consumer (consumer names are per
+// process) left unacknowledged, so they get persisted and stop holding back
+// the trim. It reports whether anything was claimed.
+func claimStale(ctx context.Context, rdc *redis.Client, consumer string) bool {
+	msgs, _, err := rdc.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  claimIdle,
+		Start:    "0-0",
+		Count:    1000,
+	}).Result()
+	if err != nil {
+		zap.L().Warn("syncevents.xautoclaim", zap.Error(err))
+		return false
+	}
+	return len(msgs) > 0
+}
+
+// trim drops the entries the group is done with: everything before the
+// oldest unacknowledged one, or before the last delivered one when nothing
+// is pending. Entries not yet read by the group are never trimmed.
+func trim(ctx context.Context, rdc *redis.Client) {
+	pending, err := rdc.XPending(ctx, stream, group).Result()
+	if err != nil {
+		zap.L().Warn("syncevents.xpending", zap.Error(err))
+		return
+	}
+	minID := pending.Lower
+	if pending.Count == 0 {
+		groups, err := rdc.XInfoGroups(ctx, stream).Result()
+		if err != nil {
+			zap.L().Warn("syncevents.xinfo", zap.Error(err))
+			return
+		}
+		for _, g := range groups {
+			if g.Name == group {
+				minID = g.LastDeliveredID
+			}
+		}
+	}
+	if minID == "" || minID == "0-0" {
+		return
+	}
+	// approximate (~) trimming only removes whole macro nodes, so it never
+	// goes past minID
+	if err := rdc.XTrimMinIDApprox(ctx, stream, minID, 0).Err(); err != nil {
+		zap.L().Warn("syncevents.xtrim", zap.Error(err))
+	}
+}
+
+func toEntries(msgs []redis.XMessage) []history.Entry {
+	out := make([]history.Entry, 0, len(msgs))
+	for _, m := range msgs {
+		aid, _ := m.Values["aid"].(string)
+		p, _ := m.Values["p"].(string)
+		tsStr, _ := m.Values["ts"].(string)
+
+		ev, err := events.Decode([]byte(p))
+		if err != nil || !history.Recorded(ev.EventName()) {
+			continue
+		}
+		ms, _ := strconv.ParseInt(tsStr, 10, 64)
+		out = append(out, history.EntryFromEvent(aid, ev, []byte(p), time.UnixMilli(ms).UTC()))
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package webhookfeed
+
+import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/services/webhook"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const channelPattern = "auc:*:events"
+
+// Run tails every auction's event channel and enqueues the lifecycle events
+// as webhook deliveries. Every instance may run it: deliveries are keyed by
+// "<auction_id>:<seq>", so the copies collapse into one row.
+func Run(ctx context.Context, rdc *redis.Client, svc webhook.IWebhookService) {
+	go func() {
+		ps := rdc.PSubscribe(ctx, channelPattern)
+		defer ps.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case m, ok := <-ps.Channel():
+				if !ok {
+					return
+				}
+				auctionID := strings.TrimSuffix(strings.TrimPrefix(m.Channel, "auc:"), ":events")
+				for _, ev := range translate(auctionID, m.Payload) {
+					if err := svc.Enqueue(ctx, ev); err != nil {
+						zap.L().Warn("webhookfeed.enqueue",
+							zap.String("auction", auctionID), zap.String("type", ev.Type), zap.Error(err))
+					}
+				}
+			}
+		}
+	}()
+}
+
+// translate maps one published auction event to its webhook events. A bid
+// that replaces someone else's high bid yields both auction.bid and
+// auction.outbid. Ephemeral events (presence, chat) are ignored.
+func translate(auctionID, payload string) []webhook.Event {
+	ev, err := events.Decode([]byte(payload))
+	if err != nil || ev.Sequence() == 0 {
+		return nil
+	}
+
+	key := auctionID + ":" + strconv.FormatInt(ev.Sequence(), 10)
+	mk := func(typ, suffix string, at time.Time) webhook.Event {
+		return webhook.Event{
+			Key:        key + suffix,
+			Type:       typ,
+			AuctionID:  auctionID,
+			Seq:        ev.Sequence(),
+			OccurredAt: at,
+			Data:       ev,
+		}
+	}
+
+	now := time.Now().UTC()
+	switch e := ev.(type) {
+	case events.Start:
+		return []webhook.Event{mk(webhook.EventAuctionStarted, "", time.Unix(e.StartsAt, 0).UTC())}
+	case events.Bid:
+		at := time.Unix(e.At, 0).UTC()
+		out := []webhook.Event{mk(webhook.EventAuctionBid, "", at)}
+		if e.PreviousBidder != "" && e.PreviousBidder != e.Bidder {
+			out = append(out, mk(webhook.EventAuctionOutbid, ":outbid", at))
+		}
+		return out
+	case events.Extended:
+		return []webhook.Event{mk(webhook.EventAuctionExtended, "", now)}
+	case events.Stop:
+		fin := mk(webhook.EventAuctionFinished, "", now)
+		fin.Key = webhook.FinishedKey(auctionID) // shared with the outbox relay
+		return []webhook.Event{fin}
+	}
+	return nil
+}
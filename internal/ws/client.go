@@ -2,38 +2,191 @@ package ws
 
 import (
 	"context"
+	"errors"
 	"sync"
 
 	"github.com/coder/websocket"
-	"github.com/coder/websocket/wsjson"
+	"go.uber.org/zap"
 )
 
+var errConnClosed = errors.New("connection closed")
+
+// snapshotFunc renders a snapshot frame, as userID may see it, and the event
+// sequence it reflects.
+type snapshotFunc func(auctionID, userID string) (seq int64, f *frame, err error)
+
+// transport is the wire a clientConn writes envelope frames to.
+type transport interface {
+	writeFrame(ctx context.Context, f *frame) error
+	close(code websocket.StatusCode, reason string) error
+}
+
+// wsTransport writes frames as websocket messages in the negotiated codec.
+type wsTransport struct {
+	conn  *websocket.Conn
+	codec codec
+}
+
+func (t wsTransport) writeFrame(ctx context.Context, f *frame) error {
+	data, err := f.encoded(t.codec)
+	if err != nil {
+		return err
+	}
+	return t.conn.Write(ctx, t.codec.messageType(), data)
+}
+
+func (t wsTransport) close(code websocket.StatusCode, reason string) error {
+	return t.conn.Close(code, reason)
+}
+
+// clientConn is one subscriber (a websocket or an SSE stream). Every
+// outbound frame goes through its bounded send queue and is written by a
+// single writer goroutine, so a stalled client never blocks room broadcasts.
 type clientConn struct {
-	rawConn *websocket.Conn
-	mu      sync.Mutex
+	rawConn  *websocket.Conn // nil for non‑websocket transports
+	userID   string
+	staffID  string // set when ?staff_token= verified
+	codec    codec  // inbound decoding (websocket only)
+	wire     transport
+	queue    *sendQueue
+	snapshot snapshotFunc
+	once     sync.Once
+
+	// While a connection catches up on an auction (replay or snapshot) the
+	// live frames for that auction are parked here and flushed afterwards.
+	gateMu sync.Mutex
+	held   map[string][]*frame // auctionID -> parked frames
 }
 
-func (c *clientConn) write(mt websocket.MessageType, data []byte) error {
-	ctx, cancel := context.WithTimeout(context.Background(), writeWait)
-	defer cancel()
+func newClientConn(rawConn *websocket.Conn, opts SendQueueOptions, snapshot snapshotFunc) *clientConn {
+	cd := codecFor(rawConn.Subprotocol())
+	c := newTransportConn(wsTransport{conn: rawConn, codec: cd}, rawConn, opts, snapshot)
+	c.codec = cd
+	return c
+}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.rawConn.Write(ctx, mt, data)
+func newTransportConn(wire transport, rawConn *websocket.Conn, opts SendQueueOptions, snapshot snapshotFunc) *clientConn {
+	c := &clientConn{
+		rawConn:  rawConn,
+		wire:     wire,
+		queue:    newSendQueue(opts),
+		snapshot: snapshot,
+	}
+	go c.writer()
+	return c
 }
 
-func (c *clientConn) writeJSON(v any) error {
-	ctx, cancel := context.WithTimeout(context.Background(), writeWait)
-	defer cancel()
+// send queues a direct reply (ack, error, snapshot, replayed event).
+func (c *clientConn) send(f *frame) error {
+	if !c.queue.push(outFrame{auctionID: f.auctionID(), f: f}) {
+		c.closeSlow()
+		return errConnClosed
+	}
+	return nil
+}
+
+// deliver queues a live room frame, or parks it while the auction is held.
+func (c *clientConn) deliver(auctionID string, f *frame) {
+	c.gateMu.Lock()
+	if q, ok := c.held[auctionID]; ok {
+		c.held[auctionID] = append(q, f)
+		c.gateMu.Unlock()
+		return
+	}
+	c.gateMu.Unlock()
+	c.enqueueLive(auctionID, f)
+}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return wsjson.Write(ctx, c.rawConn, v)
+func (c *clientConn) enqueueLive(auctionID string, f *frame) {
+	if !c.queue.push(outFrame{auctionID: auctionID, f: f, live: true}) {
+		c.closeSlow()
+	}
+}
+
+// hold starts parking live frames for the auction.
+func (c *clientConn) hold(auctionID string) {
+	c.gateMu.Lock()
+	defer c.gateMu.Unlock()
+	if c.held == nil {
+		c.held = make(map[string][]*frame)
+	}
+	c.held[auctionID] = nil
+}
+
+// release queues the parked frames whose sequence is greater than afterSeq
+// (older ones were already covered by the replay/snapshot) and switches the
+// auction back to direct delivery.
+func (c *clientConn) release(auctionID string, afterSeq int64) {
+	c.gateMu.Lock()
+	defer c.gateMu.Unlock()
+
+	for _, f := range c.held[auctionID] {
+		if f.seq != 0 && f.seq <= afterSeq {
+			continue
+		}
+		c.enqueueLive(auctionID, f)
+	}
+	delete(c.held, auctionID)
+}
+
+// writer drains the send queue until the connection goes away.
+func (c *clientConn) writer() {
+	for {
+		f, ok := c.queue.pop()
+		if !ok {
+			return
+		}
+
+		out := f.f
+		if f.resync {
+			seq, snap, err := c.snapshot(f.auctionID, c.userID)
+			if err != nil {
+				// the frames the marker stands for are gone: rather than a
+				// silent gap, make the client reconnect (with last_seq)
+				metricResyncFailures.Add(1)
+				zap.L().Warn("ws.resync", zap.String("auction", f.auctionID),
+					zap.String("user", c.userID), zap.Error(err))
+				c.close(websocket.StatusTryAgainLater, "resync failed")
+				return
+			}
+			c.queue.skipUpTo(f.auctionID, seq)
+			out = snap
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), writeWait)
+		err := c.wire.writeFrame(ctx, out)
+		cancel()
+		if err != nil {
+			c.close(websocket.StatusGoingAway, "write failed")
+			return
+		}
+	}
+}
+
+// closeSlow disconnects a consumer that can't keep up (PolicyDisconnect).
+func (c *clientConn) closeSlow() {
+	c.once.Do(func() {
+		metricSlowDisconnects.Add(1)
+		c.queue.close()
+		// Close waits for the peer's close frame; never block the caller
+		// (usually a room broadcast) on it.
+		go func() { _ = c.wire.close(websocket.StatusPolicyViolation, "slow consumer") }()
+	})
+}
+
+func (c *clientConn) close(code websocket.StatusCode, reason string) {
+	c.once.Do(func() {
+		c.queue.close()
+		_ = c.wire.close(code, reason)
+	})
 }
 
 // ConnContext gives handlers access to per‑connection data.
 type ConnContext struct {
-	AuctionID string
+	AuctionID string // bound auction; empty on multiplexed connections
 	UserID    string
+	StaffID   string // verified ?staff_token= holder (== UserID); empty otherwise
 	Server    *WsServer
+
+	conn *clientConn
 }
@@ -0,0 +1,57 @@
+package ws
+
+import (
+	"auctionbidgo/internal/events"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// serverTime reads the authoritative clock. Redis TIME is what the Lua
+// functions use for bid timestamps and close checks, so clients that sync
+// against it count down to the same instant the auction actually closes.
+func (s *WsServer) serverTime(ctx context.Context) (int64, error) {
+	t, err := s.rdc.Time(ctx).Result()
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixMilli(), nil
+}
+
+// timeEvent builds an "auctions/time" body; clientTime is echoed back.
+func timeEvent(serverTime, clientTime int64) events.Time {
+	return events.Time{
+		Header:     events.Header{Version: events.Version},
+		ServerTime: serverTime,
+		ClientTime: clientTime,
+	}
+}
+
+// syncClocks pushes the server time to every connection that joined a room,
+// once per interval, until ctx is done. One Redis call and one encoding per
+// tick are shared by all connections.
+func (s *WsServer) syncClocks(ctx context.Context, every time.Duration) {
+	if every <= 0 {
+		return
+	}
+	t := time.NewTicker(every)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			now, err := s.serverTime(ctx)
+			if err != nil {
+				zap.L().Warn("ws.time_sync", zap.Error(err))
+				continue
+			}
+			f := newFrame("auctions/"+events.NameTime, "", 0, timeEvent(now, 0))
+			for _, c := range s.hub.Conns() {
+				_ = c.send(f)
+			}
+		}
+	}
+}
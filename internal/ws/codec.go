@@ -0,0 +1,148 @@
+package ws
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"github.com/coder/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Negotiated via Sec-WebSocket-Protocol. A client that offers none gets JSON.
+const (
+	SubprotocolJSON    = "auctionbid.json"
+	SubprotocolMsgpack = "auctionbid.msgpack"
+)
+
+// codec is a wire encoding. Struct tags are shared: MessagePack uses the
+// same `json:"…"` names, so Router.Register handlers work with either.
+type codec int
+
+const (
+	codecJSON codec = iota
+	codecMsgpack
+	codecSSE // not a WS subprotocol: the full "id/event/data" SSE chunk
+	numCodecs
+)
+
+func codecFor(subprotocol string) codec {
+	if subprotocol == SubprotocolMsgpack {
+		return codecMsgpack
+	}
+	return codecJSON
+}
+
+func (cd codec) messageType() websocket.MessageType {
+	if cd == codecMsgpack {
+		return websocket.MessageBinary
+	}
+	return websocket.MessageText
+}
+
+func (cd codec) marshal(v any) ([]byte, error) {
+	if cd != codecMsgpack {
+		return json.Marshal(v)
+	}
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (cd codec) unmarshal(data []byte, v any) error {
+	if cd != codecMsgpack {
+		return json.Unmarshal(data, v)
+	}
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// decodeEnvelope splits an inbound frame into event name and still‑encoded
+// body (decoded later into the handler's request type).
+func (cd codec) decodeEnvelope(data []byte) (event string, body []byte, err error) {
+	if cd != codecMsgpack {
+		var env Envelope
+		err = json.Unmarshal(data, &env)
+		return env.Event, env.Body, err
+	}
+	var env struct {
+		Event string             `json:"event"`
+		Body  msgpack.RawMessage `json:"body,omitempty"`
+	}
+	err = cd.unmarshal(data, &env)
+	return env.Event, env.Body, err
+}
+
+// ───────────────────────────── outbound frames ──────────────────────────────
+
+// outEnvelope is the encoder‑neutral form of Envelope.
+type outEnvelope struct {
+	Event     string `json:"event"`
+	AuctionID string `json:"auction_id,omitempty"`
+	Body      any    `json:"body,omitempty"`
+}
+
+// frame is one outbound envelope. Each codec's encoding is produced at most
+// once and shared by every connection the frame is delivered to, so a room
+// broadcast costs one encode per codec in use rather than one per socket.
+type frame struct {
+	env outEnvelope
+	seq int64 // body.seq for auction events, 0 otherwise
+
+	once [numCodecs]sync.Once
+	data [numCodecs][]byte
+	errs [numCodecs]error
+}
+
+func newFrame(event, auctionID string, seq int64, body any) *frame {
+	return &frame{
+		env: outEnvelope{Event: event, AuctionID: auctionID, Body: body},
+		seq: seq,
+	}
+}
+
+func (f *frame) auctionID() string { return f.env.AuctionID }
+
+func (f *frame) encoded(cd codec) ([]byte, error) {
+	f.once[cd].Do(func() {
+		if cd == codecSSE {
+			f.data[cd], f.errs[cd] = f.sseChunk()
+			return
+		}
+		f.data[cd], f.errs[cd] = cd.marshal(f.env)
+	})
+	return f.data[cd], f.errs[cd]
+}
+
+// sseChunk renders the frame as one SSE event:
+//
+//	id: <seq>
+//	event: auctions/bid
+//	data: {"version":1,"bidder":"u1",…}
+func (f *frame) sseChunk() ([]byte, error) {
+	body := []byte("{}")
+	if f.env.Body != nil {
+		b, err := json.Marshal(f.env.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	var buf bytes.Buffer
+	if f.seq > 0 {
+		buf.WriteString("id: " + strconv.FormatInt(f.seq, 10) + "\n")
+	}
+	buf.WriteString("event: " + f.env.Event + "\n")
+	buf.WriteString("data: ")
+	buf.Write(body)
+	buf.WriteString("\n\n")
+	return buf.Bytes(), nil
+}
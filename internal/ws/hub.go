@@ -1,29 +1,161 @@
 package ws
 
 import (
+	"sort"
 	"sync"
 )
 
-// Hub keeps client sets per auctionID.
+// Hub keeps client sets per auctionID and, for every connection, the set of
+// auctions it has joined (a connection may watch many auctions at once).
 type Hub struct {
 	rooms sync.Map // auctionID -> *room
+
+	mu      sync.Mutex
+	members map[*clientConn]map[string]struct{} // conn -> joined auctionIDs
+	users   map[string]map[*clientConn]struct{} // userID -> open conns
 }
 
-func NewHub() *Hub { return &Hub{} }
+func NewHub() *Hub {
+	return &Hub{
+		members: make(map[*clientConn]map[string]struct{}),
+		users:   make(map[string]map[*clientConn]struct{}),
+	}
+}
 
 // Broadcast is called by the Redis subscriber.
-func (h *Hub) Broadcast(auctionID string, msg []byte) {
+func (h *Hub) Broadcast(auctionID string, f *frame) {
+	h.BroadcastTo(auctionID, f, nil)
+}
+
+// BroadcastTo sends f to the room's connections keep accepts (all when keep
+// is nil).
+func (h *Hub) BroadcastTo(auctionID string, f *frame, keep func(*clientConn) bool) {
 	if v, ok := h.rooms.Load(auctionID); ok {
-		v.(*room).broadcast(msg)
+		v.(*room).broadcast(f, keep)
 	}
 }
-func (h *Hub) Join(auctionID string, c *clientConn) {
-	r, _ := h.rooms.LoadOrStore(auctionID, newRoom())
+
+// Join adds the connection to the auction room. It reports false when the
+// connection is already a member of that room.
+func (h *Hub) Join(auctionID string, c *clientConn) bool {
+	h.mu.Lock()
+	set, ok := h.members[c]
+	if !ok {
+		set = make(map[string]struct{})
+		h.members[c] = set
+	}
+	if _, dup := set[auctionID]; dup {
+		h.mu.Unlock()
+		return false
+	}
+	set[auctionID] = struct{}{}
+	h.mu.Unlock()
+
+	r, _ := h.rooms.LoadOrStore(auctionID, newRoom(auctionID))
 	r.(*room).add(c)
+	return true
 }
 
-func (h *Hub) Leave(auctionID string, c *clientConn) {
+// Leave removes the connection from a single auction room. It reports false
+// when the connection was not a member.
+func (h *Hub) Leave(auctionID string, c *clientConn) bool {
+	h.mu.Lock()
+	set := h.members[c]
+	if _, ok := set[auctionID]; !ok {
+		h.mu.Unlock()
+		return false
+	}
+	delete(set, auctionID)
+	if len(set) == 0 {
+		delete(h.members, c)
+	}
+	h.mu.Unlock()
+
 	if v, ok := h.rooms.Load(auctionID); ok {
 		v.(*room).remove(c)
 	}
+	return true
+}
+
+// LeaveAll removes the connection from every room it joined and returns the
+// auction IDs it was a member of.
+func (h *Hub) LeaveAll(c *clientConn) []string {
+	h.mu.Lock()
+	set := h.members[c]
+	delete(h.members, c)
+	h.mu.Unlock()
+
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		if v, ok := h.rooms.Load(id); ok {
+			v.(*room).remove(c)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// IsMember reports whether the connection joined the auction room.
+func (h *Hub) IsMember(auctionID string, c *clientConn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, ok := h.members[c][auctionID]
+	return ok
+}
+
+// Conns returns every connection that joined at least one room.
+func (h *Hub) Conns() []*clientConn {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	conns := make([]*clientConn, 0, len(h.members))
+	for c := range h.members {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+// Memberships returns the (sorted) auction IDs the connection currently watches.
+func (h *Hub) Memberships(c *clientConn) []string {
+	h.mu.Lock()
+	ids := make([]string, 0, len(h.members[c]))
+	for id := range h.members[c] {
+		ids = append(ids, id)
+	}
+	h.mu.Unlock()
+
+	sort.Strings(ids)
+	return ids
+}
+
+// AddUser indexes an open connection by its user, for per‑user pushes.
+func (h *Hub) AddUser(c *clientConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	set, ok := h.users[c.userID]
+	if !ok {
+		set = make(map[*clientConn]struct{})
+		h.users[c.userID] = set
+	}
+	set[c] = struct{}{}
+}
+
+// RemoveUser drops a closing connection from the user index.
+func (h *Hub) RemoveUser(c *clientConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.users[c.userID], c)
+	if len(h.users[c.userID]) == 0 {
+		delete(h.users, c.userID)
+	}
+}
+
+// UserConns returns the user's open connections on this instance.
+func (h *Hub) UserConns(userID string) []*clientConn {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	conns := make([]*clientConn, 0, len(h.users[userID]))
+	for c := range h.users[userID] {
+		conns = append(conns, c)
+	}
+	return conns
 }
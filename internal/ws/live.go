@@ -0,0 +1,77 @@
+package ws
+
+import (
+	"auctionbidgo/internal/events"
+	"context"
+	"errors"
+)
+
+// registerLiveHandlers wires the live auction console: auctioneers call the
+// lot ("live/open" … "live/pass"), clerks enter floor / phone bids
+// ("live/bid") and take back the last bid ("live/undo"). The live service
+// checks the roles against the connection's verified staff ID (StaffID,
+// from ?staff_token=), never the self‑asserted user_id; every action reaches
+// the room as "auctions/live".
+func (s *WsServer) registerLiveHandlers() {
+	// 🔹 live/<action> (auctioneers) -------------------------------------------
+	for _, action := range []string{
+		events.LiveOpen, events.LiveAsk, events.LiveGoingOnce,
+		events.LiveGoingTwice, events.LiveSold, events.LivePass,
+	} {
+		Register(
+			s.router,
+			"live/"+action,
+			func(ctx context.Context, cc *ConnContext, req LiveRequest) (AckBody, error) {
+				auctionID := req.AuctionID
+				if auctionID == "" {
+					auctionID = cc.AuctionID
+				}
+				if auctionID == "" {
+					return AckBody{}, errors.New("auction_id_required")
+				}
+				if action == events.LiveAsk && req.Ask <= 0 {
+					return AckBody{}, errors.New("invalid_ask")
+				}
+				return AckBody{}, s.liveSvc.Call(ctx, auctionID, cc.StaffID, action, req.Ask)
+			},
+		)
+	}
+
+	// 🔹 live/bid (clerks) -----------------------------------------------------
+	Register(
+		s.router,
+		"live/bid",
+		func(ctx context.Context, cc *ConnContext, req LiveBidRequest) (AckBody, error) {
+			auctionID := req.AuctionID
+			if auctionID == "" {
+				auctionID = cc.AuctionID
+			}
+			if auctionID == "" {
+				return AckBody{}, errors.New("auction_id_required")
+			}
+			if req.BidderID == "" {
+				return AckBody{}, errors.New("bidder_id_required")
+			}
+			if req.Amount <= 0 {
+				return AckBody{}, errors.New("invalid_amount")
+			}
+			return AckBody{}, s.liveSvc.Bid(ctx, auctionID, cc.StaffID, req.BidderID, req.Amount, req.Source)
+		},
+	)
+
+	// 🔹 live/undo (clerks) ----------------------------------------------------
+	Register(
+		s.router,
+		"live/undo",
+		func(ctx context.Context, cc *ConnContext, req LiveRequest) (AckBody, error) {
+			auctionID := req.AuctionID
+			if auctionID == "" {
+				auctionID = cc.AuctionID
+			}
+			if auctionID == "" {
+				return AckBody{}, errors.New("auction_id_required")
+			}
+			return AckBody{}, s.liveSvc.Undo(ctx, auctionID, cc.StaffID)
+		},
+	)
+}
@@ -1,18 +1,92 @@
 package ws
 
-import "encoding/json"
+import (
+	"auctionbidgo/internal/services/sale"
+	"encoding/json"
+)
 
 // Envelope wraps every WS frame.
 type Envelope struct {
-	Event string          `json:"event"`          // e.g. "auctions/bid"
-	Body  json.RawMessage `json:"body,omitempty"` // arbitrary JSON object
+	Event     string          `json:"event"`                // e.g. "auctions/bid"
+	AuctionID string          `json:"auction_id,omitempty"` // auction the frame refers to
+	Body      json.RawMessage `json:"body,omitempty"`       // arbitrary JSON object
 }
 
 // ──────────────────────────── Request / Response DTOs ─────────────────────────
 
 // BidRequest is the body for "auctions/bid".
 type BidRequest struct {
-	Amount float64 `json:"amount" validate:"gt=0"`
+	// AuctionID is mandatory on multiplexed connections; connections bound
+	// to a single auction (?auction_id=…) may omit it.
+	AuctionID string  `json:"auction_id,omitempty"`
+	Amount    float64 `json:"amount" validate:"gt=0"`
+}
+
+// SubscriptionRequest is the body for "auctions/subscribe" and
+// "auctions/unsubscribe".
+type SubscriptionRequest struct {
+	AuctionID string `json:"auction_id" validate:"required"`
+	// LastSeq, when set on subscribe, asks the server to replay the events
+	// after that sequence instead of sending a fresh snapshot.
+	LastSeq *int64 `json:"last_seq,omitempty"`
+}
+
+// SubscriptionBody lists the auctions the connection currently watches.
+type SubscriptionBody struct {
+	AuctionIDs []string `json:"auction_ids"`
+}
+
+// SaleSubscriptionRequest is the body for "sales/subscribe" and
+// "sales/unsubscribe".
+type SaleSubscriptionRequest struct {
+	SaleID string `json:"sale_id" validate:"required"`
+}
+
+// SaleSnapshotBody is the "sales/snapshot" frame sent on subscribe: the
+// catalog with the live state of every lot.
+type SaleSnapshotBody struct {
+	sale.Catalog
+	ServerTime int64 `json:"server_time"` // unix ms
+}
+
+// LiveRequest is the body of the auctioneer actions "live/open",
+// "live/ask", "live/going_once", "live/going_twice", "live/sold",
+// "live/pass" and of the clerk action "live/undo".
+type LiveRequest struct {
+	AuctionID string  `json:"auction_id,omitempty"` // as for BidRequest
+	Ask       float64 `json:"ask,omitempty"`        // live/ask; optional on live/open
+}
+
+// LiveBidRequest is the body for "live/bid": a clerk enters a floor or
+// phone bid on the bidder's behalf.
+type LiveBidRequest struct {
+	AuctionID string  `json:"auction_id,omitempty"`
+	BidderID  string  `json:"bidder_id" validate:"required"`
+	Amount    float64 `json:"amount" validate:"gt=0"`
+	Source    string  `json:"source" validate:"required"` // floor | phone
+}
+
+// TimeRequest is the body for "auctions/time". ClientTime (unix ms) is
+// echoed in the reply so the client can measure the round trip.
+type TimeRequest struct {
+	ClientTime int64 `json:"client_time"`
+}
+
+// ChatRequest is the body for "auctions/chat".
+type ChatRequest struct {
+	AuctionID string `json:"auction_id,omitempty"` // as for BidRequest
+	Text      string `json:"text" validate:"required"`
+}
+
+// ChatAckBody returns the ID assigned to the posted message.
+type ChatAckBody struct {
+	ID int64 `json:"id"`
+}
+
+// ChatDeleteRequest is the body for the moderator action "chat/delete".
+type ChatDeleteRequest struct {
+	AuctionID string `json:"auction_id,omitempty"`
+	MessageID int64  `json:"message_id" validate:"required"`
 }
 
 // Empty ACK body (useful for many handlers).
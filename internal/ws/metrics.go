@@ -0,0 +1,11 @@
+package ws
+
+import "expvar"
+
+// Process‑wide WS counters, published on /debug/vars.
+var (
+	metricDropped         = expvar.NewInt("ws_dropped_messages")
+	metricCoalesced       = expvar.NewInt("ws_coalesced_snapshots")
+	metricSlowDisconnects = expvar.NewInt("ws_slow_consumer_disconnects")
+	metricResyncFailures  = expvar.NewInt("ws_resync_failures")
+)
@@ -0,0 +1,44 @@
+package ws
+
+import (
+	"auctionbidgo/internal/notify"
+	"context"
+	"encoding/json"
+
+	"go.uber.org/zap"
+)
+
+// eventNotification is the frame name of a per‑user push.
+const eventNotification = "notifications/new"
+
+// pushNotifications forwards the notifications published on
+// notify.PushChannel to the addressed user's connections on this instance,
+// until ctx is done.
+func (s *WsServer) pushNotifications(ctx context.Context) {
+	ps := s.rdc.Subscribe(ctx, notify.PushChannel)
+	defer ps.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-ps.Channel():
+			if !ok {
+				return
+			}
+			var n notify.Notification
+			if err := json.Unmarshal([]byte(m.Payload), &n); err != nil {
+				zap.L().Warn("ws.notification", zap.Error(err))
+				continue
+			}
+			conns := s.hub.UserConns(n.UserID)
+			if len(conns) == 0 {
+				continue
+			}
+			f := newFrame(eventNotification, n.AuctionID, 0, n)
+			for _, c := range conns {
+				_ = c.send(f)
+			}
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package ws
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	presenceTTL       = 30 * time.Second // entry lifetime without heartbeat
+	presenceHeartbeat = 10 * time.Second // must be < presenceTTL
+	presenceThrottle  = 2 * time.Second  // min gap between recounts per room
+
+	redisPresenceKeyPrefix     = "auc_presence:"      // zset "<instance>|<user>" → expiry
+	redisBiddersKeyPrefix      = "auc_bidders:"       // set of distinct bidders
+	redisPresenceLastKeyPrefix = "auc_presence_last:" // last published counts
+)
+
+// presenceTracker mirrors this instance's room members into a per‑auction
+// Redis zset shared by all instances. Entries carry an expiry score that is
+// refreshed by a heartbeat, so a crashed instance's viewers age out on their
+// own. Recounts (and the resulting "auctions/presence" broadcasts) are
+// throttled and only published when the numbers change.
+type presenceTracker struct {
+	rdb        *redis.Client
+	instanceID string
+
+	mu    sync.Mutex
+	local map[string]map[string]int // auctionID ➜ userID ➜ local conn count
+	dirty map[string]struct{}       // auctions to recount on the next tick
+}
+
+func newPresenceTracker(rdb *redis.Client) *presenceTracker {
+	return &presenceTracker{
+		rdb:        rdb,
+		instanceID: uuid.NewString(),
+		local:      make(map[string]map[string]int),
+		dirty:      make(map[string]struct{}),
+	}
+}
+
+func (p *presenceTracker) member(userID string) string {
+	return p.instanceID + "|" + userID
+}
+
+// join records one more local connection of userID in the auction room.
+func (p *presenceTracker) join(auctionID, userID string) {
+	p.mu.Lock()
+	users, ok := p.local[auctionID]
+	if !ok {
+		users = make(map[string]int)
+		p.local[auctionID] = users
+	}
+	users[userID]++
+	first := users[userID] == 1
+	p.dirty[auctionID] = struct{}{}
+	p.mu.Unlock()
+
+	if first {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = p.rdb.ZAdd(ctx, redisPresenceKeyPrefix+auctionID, redis.Z{
+			Score:  float64(time.Now().Add(presenceTTL).Unix()),
+			Member: p.member(userID),
+		}).Err()
+	}
+}
+
+// leave drops one local connection of userID from the auction room.
+func (p *presenceTracker) leave(auctionID, userID string) {
+	p.mu.Lock()
+	users := p.local[auctionID]
+	if users == nil {
+		p.mu.Unlock()
+		return
+	}
+	users[userID]--
+	last := users[userID] <= 0
+	if last {
+		delete(users, userID)
+		if len(users) == 0 {
+			delete(p.local, auctionID)
+		}
+	}
+	p.dirty[auctionID] = struct{}{}
+	p.mu.Unlock()
+
+	if last {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = p.rdb.ZRem(ctx, redisPresenceKeyPrefix+auctionID, p.member(userID)).Err()
+	}
+}
+
+// touch schedules a recount, e.g. after a bid may have added a bidder.
+func (p *presenceTracker) touch(auctionID string) {
+	p.mu.Lock()
+	if _, ok := p.local[auctionID]; ok {
+		p.dirty[auctionID] = struct{}{}
+	}
+	p.mu.Unlock()
+}
+
+// run drives recounts and heartbeats until ctx is done, then withdraws this
+// instance's entries.
+func (p *presenceTracker) run(ctx context.Context) {
+	recount := time.NewTicker(presenceThrottle)
+	heartbeat := time.NewTicker(presenceHeartbeat)
+	defer recount.Stop()
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.withdraw()
+			return
+		case <-recount.C:
+			p.mu.Lock()
+			ids := make([]string, 0, len(p.dirty))
+			for id := range p.dirty {
+				ids = append(ids, id)
+			}
+			p.dirty = make(map[string]struct{})
+			p.mu.Unlock()
+			p.recount(ctx, ids)
+		case <-heartbeat.C:
+			// Refreshing also recounts every local room, which is how
+			// viewers of crashed instances eventually disappear.
+			p.recount(ctx, p.refresh(ctx))
+		}
+	}
+}
+
+// refresh re‑arms the expiry of every local entry and returns the rooms.
+func (p *presenceTracker) refresh(ctx context.Context) []string {
+	expiry := float64(time.Now().Add(presenceTTL).Unix())
+
+	p.mu.Lock()
+	ids := make([]string, 0, len(p.local))
+	pipe := p.rdb.Pipeline()
+	for id, users := range p.local {
+		ids = append(ids, id)
+		for u := range users {
+			pipe.ZAdd(ctx, redisPresenceKeyPrefix+id, redis.Z{Score: expiry, Member: p.member(u)})
+		}
+	}
+	p.mu.Unlock()
+
+	if len(ids) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			zap.L().Warn("ws.presence_heartbeat", zap.Error(err))
+		}
+	}
+	return ids
+}
+
+func (p *presenceTracker) recount(ctx context.Context, ids []string) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	for _, id := range ids {
+		err := p.rdb.FCall(ctx, "auction_presence",
+			[]string{
+				redisPresenceKeyPrefix + id,
+				redisBiddersKeyPrefix + id,
+				redisPresenceLastKeyPrefix + id,
+			},
+			now,
+		).Err()
+		if err != nil && ctx.Err() == nil {
+			zap.L().Warn("ws.presence_recount", zap.String("auction", id), zap.Error(err))
+		}
+	}
+}
+
+// withdraw removes this instance's entries on graceful shutdown.
+func (p *presenceTracker) withdraw() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	p.mu.Lock()
+	pipe := p.rdb.Pipeline()
+	for id, users := range p.local {
+		for u := range users {
+			pipe.ZRem(ctx, redisPresenceKeyPrefix+id, p.member(u))
+		}
+	}
+	p.mu.Unlock()
+	_, _ = pipe.Exec(ctx)
+}
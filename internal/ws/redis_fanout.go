@@ -27,7 +27,11 @@ func SubscribeRedisAuctionEvents(ctx context.Context, rdb *redis.Client, hub *Hu
 			if len(parts) != 3 {
 				continue
 			}
-			hub.Broadcast(parts[1], []byte(m.Payload)) // ← correct auction ID
+			f, err := wrapRedisEvent(parts[1], m.Payload)
+			if err != nil {
+				continue
+			}
+			hub.Broadcast(parts[1], f) // ← correct auction ID
 		}
 	}
 }
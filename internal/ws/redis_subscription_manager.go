@@ -1,9 +1,11 @@
 package ws
 
 import (
+	"auctionbidgo/internal/events"
 	"context"
-	"encoding/json"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -11,47 +13,85 @@ import (
 
 // subscriptionManager guarantees that we have **exactly one** Redis
 // subscription per "auc:<id>:events" channel ― no matter how many websocket
-// clients join the same auction room.
+// clients join the same auction room, and no matter how many auctions a
+// single (multiplexed) connection watches. Sale rooms ("sale:<id>") map to
+// the "sale:<id>:events" channel the same way.
 type subscriptionManager struct {
-	rdb  *redis.Client
-	hub  *Hub
-	mu   sync.Mutex
-	subs map[string]*subEntry // auctionID ➜ subscription data
+	rdb    *redis.Client
+	hub    *Hub
+	mu     sync.Mutex
+	subs   map[string]*subEntry                // auctionID ➜ subscription data
+	byConn map[*clientConn]map[string]struct{} // conn ➜ subscribed auctionIDs
+
+	// onEvent, when set, sees every frame after it was fanned out.
+	onEvent func(auctionID string, f *frame)
+	// audience, when set, picks the connections of the room that get the
+	// frame; it returns nil for all of them.
+	audience func(auctionID string, f *frame) func(*clientConn) bool
 }
 
 type subEntry struct {
-	refCnt int
+	conns  map[*clientConn]struct{} // connections interested in the channel
 	cancel context.CancelFunc
+	ready  chan struct{} // closed once Redis confirmed the SUBSCRIBE
 }
 
+// subscribeWait bounds how long Subscribe waits for Redis to confirm a new
+// channel subscription.
+const subscribeWait = 2 * time.Second
+
 func newSubscriptionManager(rdb *redis.Client, hub *Hub) *subscriptionManager {
 	return &subscriptionManager{
-		rdb:  rdb,
-		hub:  hub,
-		subs: make(map[string]*subEntry),
+		rdb:    rdb,
+		hub:    hub,
+		subs:   make(map[string]*subEntry),
+		byConn: make(map[*clientConn]map[string]struct{}),
 	}
 }
 
-// Subscribe ensures that the process is subscribed to the auction’s channel;
-// subsequent calls for the same auction only increment the ref‑counter.
-func (sm *subscriptionManager) Subscribe(auctionID string) {
+// Subscribe ensures that the process is subscribed to the auction’s channel
+// on behalf of conn; subsequent calls for the same auction only record the
+// additional connection. Calling it twice for the same pair is a no‑op.
+//
+// Subscribe returns once the channel is live, so anything published after it
+// returns is guaranteed to reach the hub.
+func (sm *subscriptionManager) Subscribe(auctionID string, conn *clientConn) {
 	sm.mu.Lock()
+	set, ok := sm.byConn[conn]
+	if !ok {
+		set = make(map[string]struct{})
+		sm.byConn[conn] = set
+	}
+	set[auctionID] = struct{}{}
+
 	if e, ok := sm.subs[auctionID]; ok {
-		e.refCnt++
+		e.conns[conn] = struct{}{}
 		sm.mu.Unlock()
+		waitReady(e.ready)
 		return
 	}
 
 	// First consumer → create Redis SUB and fan‑out loop.
 	ctx, cancel := context.WithCancel(context.Background())
-	ps := sm.rdb.Subscribe(ctx, "auc:"+auctionID+":events")
+	ps := sm.rdb.Subscribe(ctx, channelFor(auctionID))
 
-	sm.subs[auctionID] = &subEntry{refCnt: 1, cancel: cancel}
+	e := &subEntry{
+		conns:  map[*clientConn]struct{}{conn: {}},
+		cancel: cancel,
+		ready:  make(chan struct{}),
+	}
+	sm.subs[auctionID] = e
 	sm.mu.Unlock()
 
 	go func() {
 		defer ps.Close()
 
+		// Wait for the subscription confirmation before reading messages.
+		if _, err := ps.Receive(ctx); err != nil && ctx.Err() == nil {
+			zap.L().Warn("ws.subscribe_confirm", zap.String("auction", auctionID), zap.Error(err))
+		}
+		close(e.ready)
+
 		for {
 			select {
 			case <-ctx.Done():
@@ -64,63 +104,135 @@ func (sm *subscriptionManager) Subscribe(auctionID string) {
 				// Wrap the raw Redis payload into the public WS envelope so
 				// that **all** messages (server‑initiated *and* client‑initiated)
 				// respect the same router contract format.
-				wrapped, err := wrapRedisEvent(m.Payload)
+				var wrapped *frame
+				var err error
+				if isSaleRoom(auctionID) {
+					wrapped, err = wrapSaleEvent(m.Payload)
+				} else {
+					wrapped, err = wrapRedisEvent(auctionID, m.Payload)
+				}
 				if err != nil {
 					zap.L().Warn("ws.wrap_event_failed", zap.Error(err))
-					wrapped = []byte(m.Payload) // Fallback: forward as‑is.
+					// Fallback: forward the raw payload as the body.
+					wrapped = newFrame("auctions/unknown", auctionID, 0, m.Payload)
 				}
 
-				sm.hub.Broadcast(auctionID, wrapped)
+				var keep func(*clientConn) bool
+				if sm.audience != nil {
+					keep = sm.audience(auctionID, wrapped)
+				}
+				sm.hub.BroadcastTo(auctionID, wrapped, keep)
+				if sm.onEvent != nil {
+					sm.onEvent(auctionID, wrapped)
+				}
 			}
 		}
 	}()
+	waitReady(e.ready)
 }
 
-// Unsubscribe decrements the ref‑counter and tears the Redis SUB down when the
-// last websocket client leaves the room.
-func (sm *subscriptionManager) Unsubscribe(auctionID string) {
+func waitReady(ready chan struct{}) {
+	select {
+	case <-ready:
+	case <-time.After(subscribeWait):
+	}
+}
+
+// Unsubscribe drops conn's interest in the auction and tears the Redis SUB
+// down when the last websocket client leaves the room.
+func (sm *subscriptionManager) Unsubscribe(auctionID string, conn *clientConn) {
 	sm.mu.Lock()
+	cancel := sm.release(auctionID, conn)
+	sm.mu.Unlock()
+
+	// Outside the lock → stop the fan‑out goroutine.
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// UnsubscribeAll releases every subscription held by conn; used when the
+// websocket goes away.
+func (sm *subscriptionManager) UnsubscribeAll(conn *clientConn) {
+	sm.mu.Lock()
+	var cancels []context.CancelFunc
+	for auctionID := range sm.byConn[conn] {
+		if cancel := sm.release(auctionID, conn); cancel != nil {
+			cancels = append(cancels, cancel)
+		}
+	}
+	sm.mu.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
+
+// release must be called with sm.mu held. It returns the cancel func of the
+// fan‑out goroutine when conn was the last consumer of the channel.
+func (sm *subscriptionManager) release(auctionID string, conn *clientConn) context.CancelFunc {
+	if set, ok := sm.byConn[conn]; ok {
+		delete(set, auctionID)
+		if len(set) == 0 {
+			delete(sm.byConn, conn)
+		}
+	}
+
 	e, ok := sm.subs[auctionID]
 	if !ok {
-		sm.mu.Unlock()
-		return
+		return nil
 	}
-	e.refCnt--
-	if e.refCnt > 0 {
-		sm.mu.Unlock()
-		return
+	delete(e.conns, conn)
+	if len(e.conns) > 0 {
+		return nil
 	}
 	delete(sm.subs, auctionID)
-	sm.mu.Unlock()
-
-	// Outside the lock → stop the fan‑out goroutine.
-	e.cancel()
+	return e.cancel
 }
 
 // ─────────────────────────────── helpers ─────────────────────────────────────
 
-// wrapRedisEvent turns
+// wrapRedisEvent decodes a Lua payload
 //
-//	{"version":1,"event":"bid","bidder":"u1",…}
+//	{"version":2,"event":"bid","seq":7,"bidder":"u1",…}
 //
-// into
+// into its typed event (see package events) and wraps it as the frame for
 //
-//	{"event":"auctions/bid","body":{"version":1,"bidder":"u1",…}}
-func wrapRedisEvent(payload string) ([]byte, error) {
-	var raw map[string]interface{}
-	if err := json.Unmarshal([]byte(payload), &raw); err != nil {
+//	{"event":"auctions/bid","auction_id":"<id>","body":{"version":2,"seq":7,"bidder":"u1",…}}
+func wrapRedisEvent(auctionID, payload string) (*frame, error) {
+	ev, err := events.Decode([]byte(payload))
+	if err != nil {
 		return nil, err
 	}
+	return eventFrame(auctionID, ev), nil
+}
+
+// saleRoomPrefix marks hub rooms (and channels) of sales.
+const saleRoomPrefix = "sale:"
+
+func isSaleRoom(room string) bool { return strings.HasPrefix(room, saleRoomPrefix) }
 
-	evt, _ := raw["event"].(string)
-	if evt == "" {
-		evt = "unknown"
+func channelFor(room string) string {
+	if isSaleRoom(room) {
+		return room + ":events"
 	}
-	delete(raw, "event") // Avoid duplication inside “body”.
+	return "auc:" + room + ":events"
+}
 
-	env := map[string]interface{}{
-		"event": "auctions/" + evt,
-		"body":  raw,
+// wrapSaleEvent wraps a sale channel payload as "sales/<name>"; lot frames
+// carry the lot's auction as auction_id.
+func wrapSaleEvent(payload string) (*frame, error) {
+	ev, err := events.DecodeSale([]byte(payload))
+	if err != nil {
+		return nil, err
+	}
+	auctionID := ""
+	if lot, ok := ev.(events.Lot); ok {
+		auctionID = lot.AuctionID
 	}
-	return json.Marshal(env)
+	return newFrame("sales/"+ev.EventName(), auctionID, 0, ev), nil
+}
+
+func eventFrame(auctionID string, ev events.Event) *frame {
+	return newFrame("auctions/"+ev.EventName(), auctionID, ev.Sequence(), ev)
 }
@@ -2,16 +2,15 @@ package ws
 
 import (
 	"sync"
-
-	"github.com/coder/websocket"
 )
 
 type room struct {
+	id    string // auctionID
 	mu    sync.RWMutex
 	conns map[*clientConn]struct{}
 }
 
-func newRoom() *room { return &room{conns: map[*clientConn]struct{}{}} }
+func newRoom(id string) *room { return &room{id: id, conns: map[*clientConn]struct{}{}} }
 
 func (r *room) add(c *clientConn) {
 	r.mu.Lock()
@@ -23,24 +22,17 @@ func (r *room) remove(c *clientConn) {
 	r.mu.Lock()
 	delete(r.conns, c)
 	r.mu.Unlock()
-	_ = c.rawConn.Close(websocket.StatusNormalClosure, "")
 }
 
-func (r *room) broadcast(msg []byte) {
+// broadcast only enqueues: each connection's writer goroutine does the
+// actual (possibly slow) socket write. A non‑nil keep picks the recipients.
+func (r *room) broadcast(f *frame, keep func(*clientConn) bool) {
 	r.mu.RLock()
-	conns := make([]*clientConn, 0, len(r.conns))
+	defer r.mu.RUnlock()
 	for c := range r.conns {
-		conns = append(conns, c)
-	}
-	r.mu.RUnlock()
-
-	var wg sync.WaitGroup
-	for _, c := range conns {
-		wg.Add(1)
-		go func(cc *clientConn) {
-			defer wg.Done()
-			_ = cc.write(websocket.MessageText, msg)
-		}(c)
+		if keep != nil && !keep(c) {
+			continue
+		}
+		c.deliver(r.id, f)
 	}
-	wg.Wait() // ensures ordering; remove if "at‑least‑once" is sufficient
 }
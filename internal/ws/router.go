@@ -2,13 +2,12 @@ package ws
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"sync"
 )
 
-// internal (untyped) handler signature.
-type rawHandler func(ctx context.Context, c *ConnContext, body json.RawMessage) (any, error)
+// internal (untyped) handler signature; body is still encoded in cd.
+type rawHandler func(ctx context.Context, c *ConnContext, body []byte, cd codec) (any, error)
 
 // Router keeps a map[event]handler, à‑la gin.Engine.
 type Router struct {
@@ -18,7 +17,9 @@ type Router struct {
 
 func NewRouter() *Router { return &Router{handlers: make(map[string]rawHandler)} }
 
-// Register binds an event to a strongly‑typed handler.
+// Register binds an event to a strongly‑typed handler. The request is
+// decoded with the connection's codec (JSON or MessagePack), both driven by
+// the same `json:"…"` struct tags.
 func Register[Req any, Res any](
 	r *Router,
 	event string,
@@ -31,10 +32,10 @@ func Register[Req any, Res any](
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.handlers[event] = func(ctx context.Context, c *ConnContext, body json.RawMessage) (any, error) {
+	r.handlers[event] = func(ctx context.Context, c *ConnContext, body []byte, cd codec) (any, error) {
 		var req Req
 		if len(body) > 0 {
-			if err := json.Unmarshal(body, &req); err != nil {
+			if err := cd.unmarshal(body, &req); err != nil {
 				return nil, err
 			}
 		}
@@ -43,12 +44,12 @@ func Register[Req any, Res any](
 }
 
 // dispatch is called by the server’s reader loop.
-func (r *Router) dispatch(ctx context.Context, c *ConnContext, env Envelope) (any, error) {
+func (r *Router) dispatch(ctx context.Context, c *ConnContext, event string, body []byte, cd codec) (any, error) {
 	r.mu.RLock()
-	h, ok := r.handlers[env.Event]
+	h, ok := r.handlers[event]
 	r.mu.RUnlock()
 	if !ok {
 		return nil, errors.New("unknown_event")
 	}
-	return h(ctx, c, env.Body)
+	return h(ctx, c, body, cd)
 }
@@ -0,0 +1,223 @@
+package ws
+
+import (
+	"sync"
+)
+
+// SlowConsumerPolicy decides what happens to a live room event when the
+// connection's send queue is already full.
+type SlowConsumerPolicy string
+
+const (
+	// PolicyDropOldest discards the oldest queued unsequenced live event
+	// (presence, chat, …). A sequenced auction event is never dropped on
+	// its own: its auction is coalesced into a snapshot instead, so the
+	// client's sequence stays gap‑free.
+	PolicyDropOldest SlowConsumerPolicy = "drop_oldest"
+	// PolicyCoalesce replaces the auction's queued events with a single
+	// fresh snapshot, rendered when the writer gets to it.
+	PolicyCoalesce SlowConsumerPolicy = "coalesce"
+	// PolicyDisconnect closes the connection with a "slow consumer" reason.
+	PolicyDisconnect SlowConsumerPolicy = "disconnect"
+)
+
+const defaultSendQueueSize = 64
+
+// SendQueueOptions configures the bounded per‑connection outbound queue.
+type SendQueueOptions struct {
+	Size   int                // max queued frames; <= 0 means the default (64)
+	Policy SlowConsumerPolicy // empty means PolicyDropOldest
+}
+
+func (o SendQueueOptions) withDefaults() SendQueueOptions {
+	if o.Size <= 0 {
+		o.Size = defaultSendQueueSize
+	}
+	if o.Policy == "" {
+		o.Policy = PolicyDropOldest
+	}
+	return o
+}
+
+type outFrame struct {
+	auctionID string
+	f         *frame
+	live      bool // room broadcast → subject to the slow‑consumer policy
+	resync    bool // placeholder: render a fresh snapshot when dequeued
+}
+
+// sendQueue is drained by exactly one writer goroutine per connection.
+// Direct replies (acks, errors, snapshots, replays) are never dropped; only
+// live room events are subject to the policy.
+type sendQueue struct {
+	opts   SendQueueOptions
+	mu     sync.Mutex
+	frames []outFrame
+	wake   chan struct{}
+	done   chan struct{}
+	closed bool
+}
+
+func newSendQueue(opts SendQueueOptions) *sendQueue {
+	return &sendQueue{
+		opts: opts.withDefaults(),
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+}
+
+// push enqueues f. It returns false when the policy demands that the
+// connection be dropped.
+func (q *sendQueue) push(f outFrame) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return true
+	}
+
+	if f.live && len(q.frames) >= q.opts.Size {
+		switch q.opts.Policy {
+		case PolicyDisconnect:
+			metricDropped.Add(1)
+			return false
+		case PolicyCoalesce:
+			if q.coalesceLocked(f.auctionID) {
+				metricDropped.Add(1) // the incoming event, covered by the snapshot
+				q.signal()
+				return true
+			}
+		}
+		if !q.dropOldestLocked() {
+			// nothing droppable queued → drop the newcomer; if it is
+			// sequenced, a snapshot fills the gap it leaves
+			metricDropped.Add(1)
+			if f.f.seq != 0 && !q.hasMarkerLocked(f.auctionID) {
+				q.frames = append(q.frames, outFrame{auctionID: f.auctionID, resync: true})
+				metricCoalesced.Add(1)
+				q.signal()
+			}
+			return true
+		}
+	}
+
+	q.frames = append(q.frames, f)
+	q.signal()
+	return true
+}
+
+// coalesceLocked swaps the auction's queued live events for one resync
+// marker. It reports false when there was nothing of that auction to
+// coalesce, so the caller falls back to dropping the oldest frame.
+func (q *sendQueue) coalesceLocked(auctionID string) bool {
+	kept := q.frames[:0]
+	removed, marker := 0, false
+	for _, f := range q.frames {
+		switch {
+		case f.auctionID == auctionID && f.resync:
+			marker = true
+		case f.auctionID == auctionID && f.live:
+			removed++
+			continue
+		}
+		kept = append(kept, f)
+	}
+	q.frames = kept
+
+	if !marker && removed == 0 {
+		return false
+	}
+	metricDropped.Add(int64(removed))
+	if !marker {
+		q.frames = append(q.frames, outFrame{auctionID: auctionID, resync: true})
+		metricCoalesced.Add(1)
+	}
+	return true
+}
+
+// dropOldestLocked makes room for one frame: it drops the oldest
+// unsequenced live frame, or else coalesces the auction of the oldest
+// sequenced one. It reports false when no live frame is queued.
+func (q *sendQueue) dropOldestLocked() bool {
+	for i, f := range q.frames {
+		if f.live && f.f.seq == 0 {
+			q.frames = append(q.frames[:i], q.frames[i+1:]...)
+			metricDropped.Add(1)
+			return true
+		}
+	}
+	for _, f := range q.frames {
+		if f.live {
+			return q.coalesceLocked(f.auctionID)
+		}
+	}
+	return false
+}
+
+func (q *sendQueue) hasMarkerLocked(auctionID string) bool {
+	for _, f := range q.frames {
+		if f.resync && f.auctionID == auctionID {
+			return true
+		}
+	}
+	return false
+}
+
+// skipUpTo discards queued live frames of the auction already covered by a
+// snapshot taken at seq.
+func (q *sendQueue) skipUpTo(auctionID string, seq int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	kept := q.frames[:0]
+	for _, f := range q.frames {
+		if f.live && f.auctionID == auctionID {
+			if s := f.f.seq; s != 0 && s <= seq {
+				continue
+			}
+		}
+		kept = append(kept, f)
+	}
+	q.frames = kept
+}
+
+// pop blocks until a frame is available; ok is false once the queue closed.
+func (q *sendQueue) pop() (outFrame, bool) {
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return outFrame{}, false
+		}
+		if len(q.frames) > 0 {
+			f := q.frames[0]
+			q.frames[0] = outFrame{}
+			q.frames = q.frames[1:]
+			q.mu.Unlock()
+			return f, true
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-q.wake:
+		case <-q.done:
+		}
+	}
+}
+
+func (q *sendQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.frames = nil
+	close(q.done)
+}
+
+func (q *sendQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,122 @@
+package ws
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// qf is a queued frame in test notation: "a:3" a live event of auction a
+// with seq 3 (0 = unsequenced), "a:3!" the same as a direct reply, and
+// "a:~" a resync marker.
+func qf(s string) outFrame {
+	var id string
+	var seq int64
+	direct := false
+	if s[len(s)-1] == '~' {
+		return outFrame{auctionID: s[:len(s)-2], resync: true}
+	}
+	if s[len(s)-1] == '!' {
+		direct, s = true, s[:len(s)-1]
+	}
+	if _, err := fmt.Sscanf(s, "%1s:%d", &id, &seq); err != nil {
+		panic(s)
+	}
+	return outFrame{auctionID: id, f: newFrame("auctions/test", id, seq, nil), live: !direct}
+}
+
+func (q *sendQueue) notation() []string {
+	out := make([]string, 0, len(q.frames))
+	for _, f := range q.frames {
+		switch {
+		case f.resync:
+			out = append(out, f.auctionID+":~")
+		case f.live:
+			out = append(out, fmt.Sprintf("%s:%d", f.auctionID, f.f.seq))
+		default:
+			out = append(out, fmt.Sprintf("%s:%d!", f.auctionID, f.f.seq))
+		}
+	}
+	return out
+}
+
+func TestSendQueuePolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy SlowConsumerPolicy
+		size   int // 0 = full with the queued frames
+		queued []string
+		push   []string
+		want   []string
+		ok     bool // last push's result
+	}{
+		{"room left", PolicyDisconnect, 2, []string{"a:1"}, []string{"a:2"}, []string{"a:1", "a:2"}, true},
+		{"disconnect when full", PolicyDisconnect, 0, []string{"a:1", "a:2"}, []string{"a:3"}, []string{"a:1", "a:2"}, false},
+		{"direct replies never refused", PolicyDisconnect, 0, []string{"a:1", "a:2"}, []string{"a:5!"}, []string{"a:1", "a:2", "a:5!"}, true},
+
+		{"drop oldest unsequenced", PolicyDropOldest, 0, []string{"a:1", "a:0", "b:0"}, []string{"a:2"},
+			[]string{"a:1", "b:0", "a:2"}, true},
+		{"sequenced oldest coalesced", PolicyDropOldest, 0, []string{"a:1", "b:1", "a:2"}, []string{"b:2"},
+			[]string{"b:1", "a:~", "b:2"}, true},
+		{"nothing droppable: newcomer becomes a marker", PolicyDropOldest, 0, []string{"a:1!", "a:2!"}, []string{"a:3", "a:4"},
+			[]string{"a:1!", "a:2!", "a:~"}, true},
+		{"nothing droppable: unsequenced newcomer dropped", PolicyDropOldest, 0, []string{"a:1!", "a:2!"}, []string{"a:0"},
+			[]string{"a:1!", "a:2!"}, true},
+
+		{"coalesce the auction", PolicyCoalesce, 0, []string{"a:1", "b:1", "a:2"}, []string{"a:3"},
+			[]string{"b:1", "a:~"}, true},
+		{"coalesce onto an existing marker", PolicyCoalesce, 0, []string{"b:1", "a:~"}, []string{"a:4"},
+			[]string{"b:1", "a:~"}, true},
+		{"coalesce falls back to drop oldest", PolicyCoalesce, 0, []string{"b:1", "b:2"}, []string{"a:1"},
+			[]string{"b:~", "a:1"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size := tt.size
+			if size == 0 {
+				size = len(tt.queued)
+			}
+			q := newSendQueue(SendQueueOptions{Size: size, Policy: tt.policy})
+			for _, s := range tt.queued {
+				q.frames = append(q.frames, qf(s))
+			}
+			ok := true
+			for _, s := range tt.push {
+				ok = q.push(qf(s))
+			}
+			if ok != tt.ok {
+				t.Fatalf("push = %v, want %v", ok, tt.ok)
+			}
+			if got := q.notation(); !slices.Equal(got, tt.want) {
+				t.Fatalf("queue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendQueueSkipUpTo(t *testing.T) {
+	tests := []struct {
+		name   string
+		queued []string
+		seq    int64
+		want   []string
+	}{
+		{"covered events dropped", []string{"a:1", "a:2", "a:3"}, 2, []string{"a:3"}},
+		{"unsequenced kept", []string{"a:0", "a:1"}, 5, []string{"a:0"}},
+		{"other auctions kept", []string{"b:1", "a:1", "b:2"}, 9, []string{"b:1", "b:2"}},
+		{"direct replies and markers kept", []string{"a:1!", "a:~", "a:2"}, 9, []string{"a:1!", "a:~"}},
+		{"nothing covered", []string{"a:4", "a:5"}, 3, []string{"a:4", "a:5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newSendQueue(SendQueueOptions{})
+			for _, s := range tt.queued {
+				q.frames = append(q.frames, qf(s))
+			}
+			q.skipUpTo("a", tt.seq)
+			if got := q.notation(); !slices.Equal(got, tt.want) {
+				t.Fatalf("queue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
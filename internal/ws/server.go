@@ -1,16 +1,22 @@
 package ws
 
 import (
+	"auctionbidgo/internal/events"
+	"auctionbidgo/internal/http/staffauth"
 	"auctionbidgo/internal/services/auction"
+	"auctionbidgo/internal/services/chat"
+	"auctionbidgo/internal/services/live"
+	"auctionbidgo/internal/services/registration"
+	"auctionbidgo/internal/services/sale"
 	"context"
 	"errors"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/coder/websocket"
-	"github.com/coder/websocket/wsjson"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -20,6 +26,13 @@ const (
 	writeWait  = 10 * time.Second
 	pongWait   = 12 * time.Second
 	pingPeriod = 3 * time.Second // must be < pongWait
+
+	maxSubscriptionsPerConn = 100
+	maxReplayEvents         = 500 // larger gaps fall back to a snapshot
+
+	redisSeqKeyPrefix    = "auc_seq:"    // per‑auction event counter
+	redisStreamKeyPrefix = "auc_stream:" // per‑auction event log, IDs "<seq>-0"
+	redisChatKeyPrefix   = "auc_chat:"   // recent chat, sent with the snapshot
 )
 
 type WsServer struct {
@@ -28,52 +41,157 @@ type WsServer struct {
 	router     *Router
 	rdc        *redis.Client
 	auctionSvc auction.IAuctionService
+	chatSvc    chat.IChatService
+	saleSvc    sale.ISaleService
+	liveSvc    live.ILiveService
+	regSvc     registration.IRegistrationService
+	staff      *staffauth.Signer // verifies ?staff_token=
+	sendOpts   SendQueueOptions
+	presence   *presenceTracker
+	timeSync   time.Duration // "auctions/time" push interval; 0 disables
 }
 
-func NewWsServer(h *Hub, rdc *redis.Client, auctionSvc auction.IAuctionService) *WsServer {
+// Deps are the services and settings the WS endpoints use.
+type Deps struct {
+	Auctions     auction.IAuctionService
+	Chat         chat.IChatService
+	Sales        sale.ISaleService
+	Live         live.ILiveService
+	Registration registration.IRegistrationService
+	Staff        *staffauth.Signer // verifies ?staff_token=
+	SendOpts     SendQueueOptions
+	TimeSync     time.Duration // "auctions/time" push interval; 0 disables
+}
+
+func NewWsServer(h *Hub, rdc *redis.Client, deps Deps) *WsServer {
 	router := NewRouter()
 	srv := &WsServer{
 		hub:        h,
 		subMgr:     newSubscriptionManager(rdc, h),
 		router:     router,
 		rdc:        rdc,
-		auctionSvc: auctionSvc,
+		auctionSvc: deps.Auctions,
+		chatSvc:    deps.Chat,
+		saleSvc:    deps.Sales,
+		liveSvc:    deps.Live,
+		regSvc:     deps.Registration,
+		staff:      deps.Staff,
+		sendOpts:   deps.SendOpts,
+		presence:   newPresenceTracker(rdc),
+		timeSync:   deps.TimeSync,
 	}
+	srv.subMgr.onEvent = srv.observe
+	srv.subMgr.audience = srv.lotAudience
 	srv.registerHandlers() // ← all WS endpoints configured here
 	return srv
 }
 
+// Run drives the server's background work (presence heartbeats and
+// recounts, clock sync pushes, user notifications) until ctx is cancelled.
+// Start it once at boot.
+func (s *WsServer) Run(ctx context.Context) {
+	go s.syncClocks(ctx, s.timeSync)
+	go s.pushNotifications(ctx)
+	s.presence.run(ctx)
+}
+
+// observe is called for every event fanned out to a local room.
+func (s *WsServer) observe(auctionID string, f *frame) {
+	if f.env.Event == "auctions/"+events.NameBid {
+		s.presence.touch(auctionID) // the bidder count may have changed
+	}
+}
+
+// lotAudience keeps the lot frames of a private lot on the sale channel to
+// its seller and approved bidders, like the catalog. A failed lookup keeps
+// everyone out.
+func (s *WsServer) lotAudience(room string, f *frame) func(*clientConn) bool {
+	if !isSaleRoom(room) || f.env.AuctionID == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	all, users, err := s.regSvc.Audience(ctx, f.env.AuctionID)
+	if err != nil {
+		zap.L().Warn("ws.lot_audience", zap.String("auction", f.env.AuctionID), zap.Error(err))
+		return func(*clientConn) bool { return false }
+	}
+	if all {
+		return nil
+	}
+	return func(c *clientConn) bool { return slices.Contains(users, c.userID) }
+}
+
 // ---------------------------------------------------------------------------
 //  Public: Gin entry‑point
 // ---------------------------------------------------------------------------
 
+// Handle upgrades the request to a websocket. With ?auction_id=… the
+// connection is bound to that auction; without it the connection is
+// multiplexed and the client picks auctions via "auctions/subscribe".
+// A reconnecting client may pass ?last_seq=… to get the missed events
+// replayed instead of a snapshot. Private auctions only admit their seller
+// and approved bidders. Staff pass ?staff_token=… (see staffauth) issued for
+// the same user_id; only then do chat/delete and live/* act for them.
 func (s *WsServer) Handle(ginCtx *gin.Context) {
 	auctionID := ginCtx.Query("auction_id")
 	userID := ginCtx.Query("user_id")
-	if auctionID == "" || userID == "" {
-		ginCtx.JSON(http.StatusBadRequest, gin.H{"error": "auction_id and user_id are required"})
+	if userID == "" {
+		ginCtx.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+
+	var lastSeq *int64
+	if v := ginCtx.Query("last_seq"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			ginCtx.JSON(http.StatusBadRequest, gin.H{"error": "last_seq must be a non-negative integer"})
+			return
+		}
+		lastSeq = &n
+	}
+	var staffID string
+	if tok := ginCtx.Query("staff_token"); tok != "" {
+		id, err := s.staff.Verify(tok)
+		if err == nil && id != userID {
+			err = staffauth.ErrInvalid
+		}
+		if err != nil {
+			ginCtx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		staffID = id
+	}
+	if auctionID != "" && !s.canView(ginCtx.Request.Context(), auctionID, userID) {
+		ginCtx.JSON(http.StatusForbidden, gin.H{"error": "auction is private"})
 		return
 	}
 
 	rawConn, err := websocket.Accept(
 		ginCtx.Writer, ginCtx.Request,
-		&websocket.AcceptOptions{InsecureSkipVerify: true}, // dev‑only
+		&websocket.AcceptOptions{
+			InsecureSkipVerify: true, // dev‑only
+			// Server preference order; no offer at all means JSON.
+			Subprotocols: []string{SubprotocolJSON, SubprotocolMsgpack},
+		},
 	)
 	if err != nil {
 		zap.L().Warn("ws.accept", zap.Error(err))
 		return
 	}
-	rawConn.SetReadLimit(512)
+	rawConn.SetReadLimit(4096) // room for a max‑length chat message
 
 	// ─────────────────── Client joined ────────────────────────
-	wsConn := &clientConn{rawConn: rawConn}
-	s.hub.Join(auctionID, wsConn)
-	s.subMgr.Subscribe(auctionID) // may be a no‑op (already subscribed)
-
-	// Initial snapshot.
-	if err := s.pushInitialSnapshot(ginCtx.Request.Context(), auctionID, wsConn); err != nil &&
-		!strings.Contains(err.Error(), "not found") {
-		zap.L().Warn("ws.snapshot", zap.Error(err))
+	wsConn := newClientConn(rawConn, s.sendOpts, func(id, userID string) (int64, *frame, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+		defer cancel()
+		return s.snapshotFrame(ctx, id, userID)
+	})
+	wsConn.userID = userID
+	wsConn.staffID = staffID
+	s.hub.AddUser(wsConn)
+	if auctionID != "" {
+		s.join(ginCtx.Request.Context(), auctionID, wsConn, lastSeq)
 	}
 
 	go s.reader(auctionID, userID, wsConn)
@@ -93,75 +211,392 @@ func (s *WsServer) registerHandlers() {
 			if req.Amount <= 0 {
 				return AckBody{}, errors.New("invalid_amount")
 			}
-			err := s.auctionSvc.PlaceBid(ctx, cc.AuctionID, cc.UserID, req.Amount)
+			auctionID := req.AuctionID
+			if auctionID == "" {
+				auctionID = cc.AuctionID
+			}
+			if auctionID == "" {
+				return AckBody{}, errors.New("auction_id_required")
+			}
+			err := s.auctionSvc.PlaceBid(ctx, auctionID, cc.UserID, req.Amount)
 			return AckBody{}, err
 		},
 	)
+
+	// 🔹 auctions/chat --------------------------------------------------------
+	Register(
+		s.router,
+		"auctions/chat",
+		func(ctx context.Context, cc *ConnContext, req ChatRequest) (ChatAckBody, error) {
+			auctionID := req.AuctionID
+			if auctionID == "" {
+				auctionID = cc.AuctionID
+			}
+			if auctionID == "" {
+				return ChatAckBody{}, errors.New("auction_id_required")
+			}
+			if !s.hub.IsMember(auctionID, cc.conn) {
+				return ChatAckBody{}, errors.New("not_subscribed")
+			}
+			id, err := s.chatSvc.Post(ctx, auctionID, cc.UserID, req.Text)
+			return ChatAckBody{ID: id}, err
+		},
+	)
+
+	// 🔹 chat/delete (moderators) ---------------------------------------------
+	Register(
+		s.router,
+		"chat/delete",
+		func(ctx context.Context, cc *ConnContext, req ChatDeleteRequest) (AckBody, error) {
+			auctionID := req.AuctionID
+			if auctionID == "" {
+				auctionID = cc.AuctionID
+			}
+			if auctionID == "" {
+				return AckBody{}, errors.New("auction_id_required")
+			}
+			if req.MessageID <= 0 {
+				return AckBody{}, errors.New("message_id_required")
+			}
+			return AckBody{}, s.chatSvc.Delete(ctx, auctionID, req.MessageID, cc.StaffID)
+		},
+	)
+
+	// 🔹 auctions/time (clock sync) --------------------------------------------
+	Register(
+		s.router,
+		"auctions/time",
+		func(ctx context.Context, cc *ConnContext, req TimeRequest) (events.Time, error) {
+			now, err := s.serverTime(ctx)
+			if err != nil {
+				return events.Time{}, errors.New("time_unavailable")
+			}
+			return timeEvent(now, req.ClientTime), nil
+		},
+	)
+
+	// 🔹 auctions/subscribe ---------------------------------------------------
+	Register(
+		s.router,
+		"auctions/subscribe",
+		func(ctx context.Context, cc *ConnContext, req SubscriptionRequest) (SubscriptionBody, error) {
+			if req.AuctionID == "" {
+				return SubscriptionBody{}, errors.New("auction_id_required")
+			}
+			if isSaleRoom(req.AuctionID) {
+				return SubscriptionBody{}, errors.New("invalid_auction_id")
+			}
+			if len(s.hub.Memberships(cc.conn)) >= maxSubscriptionsPerConn {
+				return SubscriptionBody{}, errors.New("too_many_subscriptions")
+			}
+			if !s.canView(ctx, req.AuctionID, cc.UserID) {
+				return SubscriptionBody{}, errors.New("auction_private")
+			}
+			s.join(ctx, req.AuctionID, cc.conn, req.LastSeq)
+			return SubscriptionBody{AuctionIDs: s.hub.Memberships(cc.conn)}, nil
+		},
+	)
+
+	// 🔹 auctions/unsubscribe -------------------------------------------------
+	Register(
+		s.router,
+		"auctions/unsubscribe",
+		func(ctx context.Context, cc *ConnContext, req SubscriptionRequest) (SubscriptionBody, error) {
+			if req.AuctionID == "" {
+				return SubscriptionBody{}, errors.New("auction_id_required")
+			}
+			if !s.hub.Leave(req.AuctionID, cc.conn) {
+				return SubscriptionBody{}, errors.New("not_subscribed")
+			}
+			s.subMgr.Unsubscribe(req.AuctionID, cc.conn)
+			s.presence.leave(req.AuctionID, cc.conn.userID)
+			return SubscriptionBody{AuctionIDs: s.hub.Memberships(cc.conn)}, nil
+		},
+	)
+
+	s.registerLiveHandlers()
+
+	// 🔹 sales/subscribe ------------------------------------------------------
+	Register(
+		s.router,
+		"sales/subscribe",
+		func(ctx context.Context, cc *ConnContext, req SaleSubscriptionRequest) (SubscriptionBody, error) {
+			if req.SaleID == "" {
+				return SubscriptionBody{}, errors.New("sale_id_required")
+			}
+			if len(s.hub.Memberships(cc.conn)) >= maxSubscriptionsPerConn {
+				return SubscriptionBody{}, errors.New("too_many_subscriptions")
+			}
+			if err := s.joinSale(ctx, req.SaleID, cc.conn); err != nil {
+				return SubscriptionBody{}, err
+			}
+			return SubscriptionBody{AuctionIDs: s.hub.Memberships(cc.conn)}, nil
+		},
+	)
+
+	// 🔹 sales/unsubscribe ----------------------------------------------------
+	Register(
+		s.router,
+		"sales/unsubscribe",
+		func(ctx context.Context, cc *ConnContext, req SaleSubscriptionRequest) (SubscriptionBody, error) {
+			if req.SaleID == "" {
+				return SubscriptionBody{}, errors.New("sale_id_required")
+			}
+			room := saleRoomPrefix + req.SaleID
+			if !s.hub.Leave(room, cc.conn) {
+				return SubscriptionBody{}, errors.New("not_subscribed")
+			}
+			s.subMgr.Unsubscribe(room, cc.conn)
+			return SubscriptionBody{AuctionIDs: s.hub.Memberships(cc.conn)}, nil
+		},
+	)
 }
 
-func (s *WsServer) pushInitialSnapshot(ctx context.Context, id string, conn *clientConn) error {
+// joinSale adds conn to the sale's room and sends the catalog snapshot.
+// Sale events are not sequenced: the ones published while the snapshot is
+// read are delivered after it and may repeat what it already shows.
+func (s *WsServer) joinSale(ctx context.Context, saleID string, conn *clientConn) error {
+	room := saleRoomPrefix + saleID
+	conn.hold(room)
+	defer conn.release(room, 0)
+
+	if !s.hub.Join(room, conn) {
+		return nil
+	}
+	s.subMgr.Subscribe(room, conn)
+
 	ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
 	defer cancel()
+	_, f, err := s.snapshotFrame(ctx, room, conn.userID)
+	switch {
+	case errors.Is(err, sale.ErrNotFound):
+		s.hub.Leave(room, conn)
+		s.subMgr.Unsubscribe(room, conn)
+		return errors.New("sale_not_found")
+	case err != nil:
+		zap.L().Warn("ws.sale_snapshot", zap.String("sale", saleID), zap.Error(err))
+		return nil
+	}
+	return conn.send(f)
+}
+
+// canView applies the auction's visibility (internal/services/registration).
+// A failed lookup keeps the user out.
+func (s *WsServer) canView(ctx context.Context, auctionID, userID string) bool {
+	ok, err := s.regSvc.CanView(ctx, auctionID, userID)
+	if err != nil {
+		zap.L().Warn("ws.can_view", zap.String("auction", auctionID), zap.Error(err))
+	}
+	return ok
+}
 
-	if snap, _ := s.rdc.HGetAll(ctx, "auc:"+id).Result(); len(snap) != 0 {
-		return conn.writeJSON(gin.H{
-			"event": "auctions/snapshot",
-			"body":  snap,
-		})
+// join adds conn to the auction room, makes sure the Redis channel is
+// subscribed and brings the client up to date: by replaying the events after
+// lastSeq when they are still retained, otherwise with a fresh snapshot.
+// Live events arriving meanwhile are parked and flushed afterwards, so the
+// client observes a gap‑free sequence. Re‑joining is a no‑op.
+func (s *WsServer) join(ctx context.Context, auctionID string, conn *clientConn, lastSeq *int64) {
+	conn.hold(auctionID)
+	if !s.hub.Join(auctionID, conn) {
+		conn.release(auctionID, 0)
+		return
 	}
+	s.subMgr.Subscribe(auctionID, conn) // may be a no‑op (already subscribed)
+	s.presence.join(auctionID, conn.userID)
 
-	dto, err := s.auctionSvc.GetAuction(ctx, id)
+	var upTo int64
+	replayed := false
+	if lastSeq != nil {
+		var err error
+		upTo, replayed, err = s.replay(ctx, auctionID, conn, *lastSeq)
+		if err != nil {
+			zap.L().Warn("ws.replay", zap.String("auction", auctionID), zap.Error(err))
+		}
+	}
+	if !replayed {
+		var err error
+		upTo, err = s.pushInitialSnapshot(ctx, auctionID, conn)
+		if err != nil && !strings.Contains(err.Error(), "not found") {
+			zap.L().Warn("ws.snapshot", zap.Error(err))
+		}
+	}
+	conn.release(auctionID, upTo)
+}
+
+// replay writes the retained events after lastSeq and returns the last
+// sequence sent. ok is false when the gap can't be filled (trimmed, too
+// large, or unknown auction) and the caller must send a snapshot instead.
+func (s *WsServer) replay(ctx context.Context, id string, conn *clientConn, lastSeq int64) (upTo int64, ok bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
+	defer cancel()
+
+	cur, err := s.rdc.Get(ctx, redisSeqKeyPrefix+id).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	switch {
+	case lastSeq == cur:
+		return cur, true, nil // nothing missed
+	case lastSeq > cur, cur-lastSeq > maxReplayEvents:
+		return 0, false, nil
+	}
+
+	first := strconv.FormatInt(lastSeq+1, 10) + "-0"
+	entries, err := s.rdc.XRangeN(ctx, redisStreamKeyPrefix+id, first, "+", maxReplayEvents).Result()
+	if err != nil {
+		return 0, false, err
+	}
+	if len(entries) == 0 || entries[0].ID != first {
+		return 0, false, nil // trimmed (or expired) past lastSeq
+	}
+
+	for _, e := range entries {
+		payload, _ := e.Values["p"].(string)
+		wrapped, err := wrapRedisEvent(id, payload)
+		if err != nil {
+			return upTo, true, err
+		}
+		if err := conn.send(wrapped); err != nil {
+			return upTo, true, err
+		}
+		upTo, _ = strconv.ParseInt(strings.TrimSuffix(e.ID, "-0"), 10, 64)
+	}
+	return upTo, true, nil
+}
+
+// pushInitialSnapshot queues the current auction state and returns the
+// event sequence it reflects.
+func (s *WsServer) pushInitialSnapshot(ctx context.Context, id string, conn *clientConn) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
+	defer cancel()
+
+	seq, f, err := s.snapshotFrame(ctx, id, conn.userID)
 	if err != nil {
-		return err
-	}
-	dbSnap := gin.H{
-		"sid":  dto.SellerID,
-		"sa":   dto.StartsAt.Unix(),
-		"ea":   dto.EndsAt.Unix(),
-		"st":   dto.Status,
-		"hb":   strconv.FormatFloat(dto.HighBid, 'f', -1, 64),
-		"hbid": dto.HighBidder,
-	}
-	return conn.writeJSON(gin.H{
-		"event": "auctions/snapshot",
-		"body":  dbSnap,
+		return seq, err
+	}
+	return seq, conn.send(f)
+}
+
+// snapshotFrame builds an "auctions/snapshot" frame, or a "sales/snapshot"
+// one for sale rooms (with the lots userID may see).
+func (s *WsServer) snapshotFrame(ctx context.Context, id, userID string) (int64, *frame, error) {
+	if isSaleRoom(id) {
+		return s.saleSnapshotFrame(ctx, strings.TrimPrefix(id, saleRoomPrefix), userID)
+	}
+
+	// Hash, chat history and counter are read atomically (Lua functions
+	// update them in one step), so the snapshot matches exactly one point of
+	// the sequence.
+	var snapCmd *redis.MapStringStringCmd
+	var chatCmd *redis.StringSliceCmd
+	var seqCmd *redis.StringCmd
+	var timeCmd *redis.TimeCmd
+	_, _ = s.rdc.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		snapCmd = p.HGetAll(ctx, "auc:"+id)
+		chatCmd = p.LRange(ctx, redisChatKeyPrefix+id, 0, -1)
+		seqCmd = p.Get(ctx, redisSeqKeyPrefix+id)
+		timeCmd = p.Time(ctx)
+		return nil
 	})
+	seq, _ := seqCmd.Int64()
+	history := chat.DecodeHistory(chatCmd.Val())
+	now := timeCmd.Val().UnixMilli()
+
+	if snap, _ := snapCmd.Result(); len(snap) != 0 {
+		ev := events.SnapshotFromHash(seq, snap)
+		ev.Chat = history
+		ev.ServerTime = now
+		return seq, eventFrame(id, ev), nil
+	}
+
+	dto, err := s.auctionSvc.GetAuction(ctx, id)
+	if err != nil {
+		return seq, nil, err
+	}
+	return seq, eventFrame(id, events.Snapshot{
+		Header:     events.Header{Version: events.Version, Seq: seq},
+		SellerID:   dto.SellerID,
+		Status:     dto.Status,
+		StartsAt:   dto.StartsAt.Unix(),
+		EndsAt:     dto.EndsAt.Unix(),
+		HighBid:    dto.HighBid,
+		HighBidder: dto.HighBidder,
+		Chat:       history,
+		ServerTime: now,
+	}), nil
+}
+
+func (s *WsServer) saleSnapshotFrame(ctx context.Context, saleID, userID string) (int64, *frame, error) {
+	cat, err := s.saleSvc.Get(ctx, saleID, userID)
+	if err != nil {
+		return 0, nil, err
+	}
+	now, err := s.serverTime(ctx)
+	if err != nil {
+		now = time.Now().UnixMilli()
+	}
+	return 0, newFrame("sales/snapshot", "", 0, SaleSnapshotBody{Catalog: *cat, ServerTime: now}), nil
+}
+
+// leaveAll detaches a departing connection from every room it joined.
+func (s *WsServer) leaveAll(conn *clientConn) {
+	s.hub.RemoveUser(conn)
+	for _, id := range s.hub.LeaveAll(conn) {
+		s.presence.leave(id, conn.userID)
+	}
+	s.subMgr.UnsubscribeAll(conn)
 }
 
 func (s *WsServer) reader(auctionID, userID string, conn *clientConn) {
 	defer func() {
-		s.hub.Leave(auctionID, conn)
-		s.subMgr.Unsubscribe(auctionID)
+		s.leaveAll(conn)
+		conn.close(websocket.StatusNormalClosure, "")
 	}()
 
-	cc := &ConnContext{AuctionID: auctionID, UserID: userID, Server: s}
+	cc := &ConnContext{AuctionID: auctionID, UserID: userID, StaffID: conn.staffID, Server: s, conn: conn}
 
 	for {
-		var env Envelope
-		if err := wsjson.Read(context.Background(), conn.rawConn, &env); err != nil {
+		_, data, err := conn.rawConn.Read(context.Background())
+		if err != nil {
 			return // client closed or errored
 		}
 
+		event, body, err := conn.codec.decodeEnvelope(data)
+		if err != nil {
+			_ = conn.send(newFrame("error", cc.AuctionID, 0, ErrorBody{Error: "malformed_envelope"}))
+			continue
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), 1900*time.Millisecond)
-		res, err := s.router.dispatch(ctx, cc, env)
+		res, err := s.router.dispatch(ctx, cc, event, body, conn.codec)
 		cancel()
 
-		// ---- error -> {"event":"error", "body":{...}} ---------------
+		target := replyAuctionID(cc, body, conn.codec)
+
+		// ---- error -> {"event":"error", "auction_id":…, "body":{...}} ------
 		if err != nil {
-			_ = conn.writeJSON(map[string]any{
-				"event": "error",
-				"body":  ErrorBody{Error: err.Error()},
-			})
+			_ = conn.send(newFrame("error", target, 0, ErrorBody{Error: err.Error()}))
 			continue
 		}
 
-		// ---- success -> {"event":"<evt>-ack", "body":{...}} --------
-		reply := map[string]any{"event": env.Event + "-ack"}
-		if res != nil {
-			reply["body"] = res
-		}
-		_ = conn.writeJSON(reply)
+		// ---- success -> {"event":"<evt>-ack", "auction_id":…, "body":{...}}
+		_ = conn.send(newFrame(event+"-ack", target, 0, res))
+	}
+}
+
+// replyAuctionID picks the auction an ack/error refers to: the request
+// body's auction_id, falling back to the auction the connection is bound to.
+func replyAuctionID(cc *ConnContext, body []byte, cd codec) string {
+	var scoped struct {
+		AuctionID string `json:"auction_id"`
+	}
+	if len(body) > 0 && cd.unmarshal(body, &scoped) == nil && scoped.AuctionID != "" {
+		return scoped.AuctionID
 	}
+	return cc.AuctionID
 }
 
 func (s *WsServer) pinger(conn *clientConn) {
@@ -173,7 +608,7 @@ func (s *WsServer) pinger(conn *clientConn) {
 		err := conn.rawConn.Ping(ctx)
 		cancel()
 		if err != nil {
-			_ = conn.rawConn.Close(websocket.StatusNormalClosure, "ping timeout")
+			conn.close(websocket.StatusNormalClosure, "ping timeout")
 			return
 		}
 	}
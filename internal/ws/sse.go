@@ -0,0 +1,159 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const sseHeartbeatPeriod = 15 * time.Second
+
+// sseTransport writes frames as Server‑Sent Events (see frame.sseChunk).
+type sseTransport struct {
+	w    http.ResponseWriter
+	rc   *http.ResponseController
+	mu   sync.Mutex
+	done chan struct{}
+	once sync.Once
+}
+
+func newSSETransport(w http.ResponseWriter) *sseTransport {
+	return &sseTransport{
+		w:    w,
+		rc:   http.NewResponseController(w),
+		done: make(chan struct{}),
+	}
+}
+
+func (t *sseTransport) writeFrame(_ context.Context, f *frame) error {
+	chunk, err := f.encoded(codecSSE)
+	if err != nil {
+		return err
+	}
+	return t.write(chunk)
+}
+
+// heartbeat writes an SSE comment so idle proxies keep the stream open.
+func (t *sseTransport) heartbeat() error {
+	return t.write([]byte(": ping\n\n"))
+}
+
+func (t *sseTransport) write(p []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	select {
+	case <-t.done:
+		return errConnClosed
+	default:
+	}
+	_ = t.rc.SetWriteDeadline(time.Now().Add(writeWait))
+	if _, err := t.w.Write(p); err != nil {
+		return err
+	}
+	return t.rc.Flush()
+}
+
+// close ends the stream; the handler returns as soon as done is closed.
+// Taking mu guarantees no write is in flight once close returns, so the
+// ResponseWriter is never touched after the handler is gone.
+func (t *sseTransport) close(websocket.StatusCode, string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.once.Do(func() { close(t.done) })
+	return nil
+}
+
+// HandleSSE streams one auction's events as Server‑Sent Events. It shares
+// the Hub/subscriptionManager fan‑out with the websocket path and uses the
+// same "auctions/*" event names and bodies. The first event is a snapshot
+// unless the client resumes with Last-Event-ID (or ?last_event_id=…) and the
+// missed events are still retained, in which case they are replayed.
+// Bidding stays on REST (POST /auctions/{id}/bid).
+//
+//	@Summary		Auction event stream (SSE)
+//	@Description	Server‑Sent Events alternative to the WebSocket. Emits the same
+//
+//	`auctions/*` events (first an `auctions/snapshot`), each with `id: <seq>`;
+//	reconnects with `Last-Event-ID` replay what was missed.
+//
+//	@Tags			Auctions
+//	@Produce		text/event-stream
+//	@Param			id				path		string	true	"Auction ID"	default(auc123)
+//	@Param			Last-Event-ID	header		string	false	"Last sequence received"
+//	@Param			last_event_id	query		string	false	"Same as Last-Event-ID (for EventSource first connects)"
+//	@Param			user_id			query		string	false	"Viewer ID for presence counts (anonymous if omitted)"
+//	@Success		200				{string}	string	"event stream"
+//	@Failure		400				{object}	map[string]string
+//	@Failure		403				{object}	map[string]string	"Private auction"
+//	@Router			/auctions/{id}/events [get]
+func (s *WsServer) HandleSSE(ginCtx *gin.Context) {
+	auctionID := ginCtx.Param("id")
+
+	lastID := ginCtx.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = ginCtx.Query("last_event_id")
+	}
+	var lastSeq *int64
+	if lastID != "" {
+		n, err := strconv.ParseInt(lastID, 10, 64)
+		if err != nil || n < 0 {
+			ginCtx.JSON(http.StatusBadRequest, gin.H{"error": "Last-Event-ID must be a non-negative integer"})
+			return
+		}
+		lastSeq = &n
+	}
+	if !s.canView(ginCtx.Request.Context(), auctionID, ginCtx.Query("user_id")) {
+		ginCtx.JSON(http.StatusForbidden, gin.H{"error": "auction is private"})
+		return
+	}
+
+	h := ginCtx.Writer.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no") // nginx: don't buffer the stream
+	ginCtx.Status(http.StatusOK)
+	ginCtx.Writer.Flush()
+
+	wire := newSSETransport(ginCtx.Writer)
+	conn := newTransportConn(wire, nil, s.sendOpts, func(id, userID string) (int64, *frame, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+		defer cancel()
+		return s.snapshotFrame(ctx, id, userID)
+	})
+	conn.userID = ginCtx.Query("user_id")
+	if conn.userID == "" {
+		conn.userID = "anon:" + uuid.NewString() // still counts as a viewer
+	}
+	s.hub.AddUser(conn)
+	defer func() {
+		s.leaveAll(conn)
+		conn.close(websocket.StatusNormalClosure, "")
+		_ = wire.close(websocket.StatusNormalClosure, "") // no‑op unless a slow‑consumer close is still pending
+	}()
+
+	reqCtx := ginCtx.Request.Context()
+	s.join(reqCtx, auctionID, conn, lastSeq)
+
+	ticker := time.NewTicker(sseHeartbeatPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-reqCtx.Done():
+			return
+		case <-wire.done:
+			return
+		case <-ticker.C:
+			if err := wire.heartbeat(); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -4,17 +4,42 @@ import (
 	"auctionbidgo/internal/config"
 	"auctionbidgo/internal/database/db_client"
 	"auctionbidgo/internal/http/http_server"
+	"auctionbidgo/internal/http/staffauth"
+	"auctionbidgo/internal/mailer"
+	"auctionbidgo/internal/notify"
+	"auctionbidgo/internal/outbox"
+	"auctionbidgo/internal/payments/fakepay"
 	"auctionbidgo/internal/redis/redis_client"
 	"auctionbidgo/internal/redis/redis_functions"
 	"auctionbidgo/internal/redis/watcher/auctionwatcher"
+	"auctionbidgo/internal/services/absentee"
 	"auctionbidgo/internal/services/auction"
+	"auctionbidgo/internal/services/bidhistory"
+	"auctionbidgo/internal/services/chat"
+	"auctionbidgo/internal/services/credit"
+	"auctionbidgo/internal/services/history"
+	"auctionbidgo/internal/services/live"
+	"auctionbidgo/internal/services/offer"
+	"auctionbidgo/internal/services/payment"
+	"auctionbidgo/internal/services/registration"
+	"auctionbidgo/internal/services/relist"
+	"auctionbidgo/internal/services/sale"
+	"auctionbidgo/internal/services/secondchance"
+	"auctionbidgo/internal/services/settlement"
+	"auctionbidgo/internal/services/watchlist"
+	"auctionbidgo/internal/services/webhook"
 	"auctionbidgo/internal/syncbid"
 	"auctionbidgo/internal/syncdb"
+	"auctionbidgo/internal/syncevents"
+	"auctionbidgo/internal/webhookfeed"
 	"auctionbidgo/internal/ws"
 	"context"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -67,7 +92,98 @@ func main() {
 	defer pgDb.Close()
 
 	// 4. Initialize the services such as auctions, etc.
-	auctionService = auction.NewAuctionService(redisClient, pgDb, cfg.BidMinIncrement)
+	relay := outbox.NewRelay(pgDb)
+	absenteeService := absentee.NewAbsenteeService(pgDb)
+	auctionService = auction.NewAuctionService(redisClient, pgDb, cfg.BidMinIncrement, cfg.CreditDefaultLimit, relay,
+		absenteeService)
+	chatService := chat.NewChatService(redisClient, chat.Config{
+		MaxLen:      cfg.ChatMaxLen,
+		SlowMode:    cfg.ChatSlowMode,
+		HistorySize: cfg.ChatHistorySize,
+		Moderators:  cfg.ChatModeratorIDs,
+	}, chat.NewBlocklistFilter(cfg.ChatBlocklist, cfg.ChatBlocklistReject))
+	webhookService := webhook.NewWebhookService(pgDb)
+	historyService := history.NewHistoryService(pgDb)
+	bidsService := bidhistory.NewBidHistoryService(redisClient, pgDb)
+	watchlistService := watchlist.NewWatchlistService(pgDb)
+	creditService := credit.NewCreditService(redisClient, pgDb, cfg.CreditDefaultLimit)
+	if err := creditService.Warm(ctx); err != nil {
+		Log.Fatal("credit-warm", zap.Error(err))
+	}
+	settlementService := settlement.NewSettlementService(pgDb, settlement.Rates{
+		BuyersPremiumPct:    cfg.BuyersPremiumPct,
+		SellerCommissionPct: cfg.SellerCommissionPct,
+		TaxPct:              cfg.TaxPct,
+		TaxOnHammer:         cfg.TaxOnHammer,
+		NumberPrefix:        cfg.InvoiceNumberPrefix,
+		PaymentTerms:        cfg.PaymentDeadline,
+	})
+	// PAYMENT_PROVIDER=fake is the only provider so far
+	if cfg.FakepaySimulator {
+		sim := fakepay.NewSimulator(cfg.FakepayURL, cfg.FakepayAPIKey,
+			strings.TrimSuffix(cfg.PublicBaseURL, "/")+"/payments/webhook", cfg.FakepayWebhookSecret)
+		go func() {
+			if err := sim.ListenAndServe(ctx, cfg.FakepayAddr); err != nil {
+				Log.Error("fakepay-simulator", zap.Error(err))
+			}
+		}()
+	}
+	paymentService := payment.NewPaymentService(pgDb,
+		fakepay.NewProvider(cfg.FakepayURL, cfg.FakepayAPIKey, cfg.FakepayWebhookSecret),
+		cfg.PaymentCurrency, relay)
+	mail, err := mailer.New(pgDb, mailer.Config{
+		Addr:          cfg.SmtpAddr,
+		Username:      cfg.SmtpUsername,
+		Password:      cfg.SmtpPassword,
+		From:          cfg.SmtpFrom,
+		AddressFormat: cfg.NotifyEmailAddress,
+		BaseURL:       cfg.PublicBaseURL,
+		MaxAttempts:   cfg.MailMaxAttempts,
+		BackoffBase:   cfg.MailBackoffBase,
+		BackoffMax:    cfg.MailBackoffMax,
+	})
+	if err != nil {
+		Log.Fatal("mail-templates", zap.Error(err))
+	}
+	emailEnabled := slices.Contains(cfg.NotifyChannels, "email")
+
+	// Per‑user notification channels (watchlists, outbid, second chance, offers)
+	var channels []notify.Channel
+	for _, name := range cfg.NotifyChannels {
+		switch name {
+		case "ws":
+			channels = append(channels, notify.NewWSChannel(redisClient))
+		case "email":
+			channels = append(channels, notify.NewEmailChannel(mail))
+		case "webhook":
+			channels = append(channels, notify.NewWebhookChannel(webhookService))
+		}
+	}
+	notifier := notify.NewDispatcher(redisClient, channels...)
+	secondChanceService := secondchance.NewSecondChanceService(pgDb, settlementService, notifier, relay,
+		secondchance.Config{TTL: cfg.SecondChanceTTL, Auto: cfg.SecondChanceAuto})
+	offerService := offer.NewOfferService(pgDb, notifier, relay, cfg.OfferTTL)
+	relistService := relist.NewRelistService(pgDb, auctionService, relay)
+	saleService := sale.NewSaleService(pgDb, redisClient, auctionService)
+	registrationService := registration.NewRegistrationService(pgDb, redisClient, notifier)
+	liveService := live.NewLiveService(redisClient, auctionService, live.Config{
+		Auctioneers: cfg.LiveAuctioneerIDs,
+		Clerks:      cfg.LiveClerkIDs,
+	})
+
+	// Outbox relay: lifecycle side effects committed with their state change
+	auction.RegisterOutboxHandlers(relay, redisClient)
+	webhook.RegisterOutboxHandlers(relay, webhookService)
+	history.RegisterOutboxHandlers(relay, historyService)
+	settlement.RegisterOutboxHandlers(relay, settlementService) // invoices
+	credit.RegisterOutboxHandlers(relay, creditService)         // win ➜ charge, paid ➜ settled
+	secondchance.RegisterOutboxHandlers(relay, secondChanceService)
+	relist.RegisterOutboxHandlers(relay, relistService) // unsold ➜ successor auction
+	sale.RegisterOutboxHandlers(relay, saleService)     // last lot ended ➜ sale CLOSED
+	if emailEnabled {
+		mailer.RegisterOutboxHandlers(relay, mail) // winner / seller result mails
+	}
+	go relay.Run(ctx)
 
 	// 5. Background: key‑expiry watcher ➜ finalise in DB
 	go auctionwatcher.Run(ctx, redisClient, auctionService)
@@ -75,15 +191,78 @@ func main() {
 	// 6. Background: 10 s high‑bid synchroniser
 	syncdb.Run(ctx, redisClient, pgDb)
 	syncbid.Run(ctx, redisClient, pgDb)
+	syncevents.Run(ctx, redisClient, historyService) // auction_events history
+
+	// 6b. Background: lifecycle events ➜ signed webhook deliveries
+	webhookfeed.Run(ctx, redisClient, webhookService)
+	go webhook.RunDeliveries(ctx, pgDb, nil, webhook.DeliveryConfig{
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		BackoffBase: cfg.WebhookBackoffBase,
+		BackoffMax:  cfg.WebhookBackoffMax,
+		Timeout:     cfg.WebhookTimeout,
+	})
+
+	// 6c. Background: watchlist / outbid notifications
+	go notify.NewEngine(redisClient, watchlistService, notifier, notify.Config{
+		EndingSoon:   cfg.NotifyEndingSoon,
+		ScanInterval: cfg.NotifyScanInterval,
+	}).Run(ctx)
+	if emailEnabled {
+		go mail.Run(ctx) // SMTP sender with retries
+	}
+
+	// 6d. Background: overdue buyer invoices ➜ auction UNPAID
+	go paymentService.RunDeadlines(ctx, time.Minute)
+	go secondChanceService.RunExpiry(ctx, time.Minute)
+	go offerService.RunExpiry(ctx, time.Minute)
 
 	// 7. WebSockets hub + Redis fan‑out
 	hub := ws.NewHub()
 
 	// 8. Initialize the WS server
-	wsSrv := ws.NewWsServer(hub, redisClient, auctionService)
+	staffSigner := staffauth.New(cfg.StaffTokenSecret)
+	if staffSigner == nil {
+		Log.Warn("STAFF_TOKEN_SECRET is empty – chat moderation and live auctions are disabled")
+	}
+	wsSrv := ws.NewWsServer(hub, redisClient, ws.Deps{
+		Auctions:     auctionService,
+		Chat:         chatService,
+		Sales:        saleService,
+		Live:         liveService,
+		Registration: registrationService,
+		Staff:        staffSigner,
+		SendOpts: ws.SendQueueOptions{
+			Size:   cfg.WsSendQueueSize,
+			Policy: ws.SlowConsumerPolicy(cfg.WsSlowConsumerPolicy),
+		},
+		TimeSync: cfg.WsTimeSyncInterval,
+	})
+	go wsSrv.Run(ctx) // presence heartbeats, clock sync
 
 	// 9. HTTP + WS server
-	httpServer := http_server.NewHttpServer(ctx, cfg.HttpServerPort, wsSrv, auctionService) // Pass the auctionsService when implemented
+	if cfg.AdminAPIToken == "" {
+		Log.Warn("ADMIN_API_TOKEN is empty – admin routes are disabled")
+	}
+	httpServer := http_server.NewHttpServer(ctx, cfg.HttpServerPort, http_server.Deps{
+		WsServer:     wsSrv,
+		Auctions:     auctionService,
+		Webhooks:     webhookService,
+		History:      historyService,
+		Bids:         bidsService,
+		Watchlist:    watchlistService,
+		Settlement:   settlementService,
+		Payments:     paymentService,
+		Credit:       creditService,
+		SecondChance: secondChanceService,
+		Offers:       offerService,
+		Relist:       relistService,
+		Sales:        saleService,
+		Absentee:     absenteeService,
+		Registration: registrationService,
+		MaskBidders:  cfg.BidHistoryMaskBidders,
+		AdminToken:   cfg.AdminAPIToken,
+		Staff:        staffSigner,
+	})
 
 	go func() {
 		if err := httpServer.Start(); err != nil {
@@ -13,10 +13,13 @@
 
   const amountInput = $('amountInput');
   const bidBtn = $('bidBtn');
+  const chatInput = $('chatInput');
+  const chatBtn = $('chatBtn');
 
   const statusSec = $('status-section');
   const bidSec = $('bid-section');
   const eventsSec = $('events-section');
+  const chatSec = $('chat-section');
   const manageSec = $('manage-section');
 
   const endsAtEl = $('endsAt');
@@ -24,6 +27,8 @@
   const highBidderEl = $('highBidder');
   const stateEl = $('state');
   const timeLeftEl = $('timeLeft');
+  const viewersEl = $('viewers');
+  const biddersEl = $('bidders');
   const eventsEl = $('events');
   const errorEl = $('error');
   const connStatusEl = $('connStatus');
@@ -44,6 +49,9 @@
   let endsAtUnix = 0;
   let countdownId = null;
   let retryDelay = 3_000;                // ms (exponential back‑off)
+  let lastSeq = null;                    // last event sequence seen (for replay)
+  let clockOffset = 0;                   // server time − local time, ms
+  let bestRtt = Infinity;                // RTT of the sample clockOffset came from
 
   const WS_STATE = Object.freeze({ INIT: 0, OPEN: 1, CLOSING: 2, CLOSED: 3 });
   let wsState = WS_STATE.INIT;
@@ -53,6 +61,7 @@
    * ------------------------------------------------------------ */
   connectBtn.addEventListener('click', connect);
   bidBtn.addEventListener('click', placeBid);
+  chatBtn.addEventListener('click', sendChat);
   refreshBtn.addEventListener('click', debounce(refreshList, 250));
   startBtn.addEventListener('click', startAuction);
   stopBtn.addEventListener('click', stopAuction);
@@ -69,9 +78,11 @@
   function connect() {
     if (wsState === WS_STATE.OPEN) return;   // already connected
 
-    auctionId = auctionIdInput.value.trim();
+    const nextAuctionId = auctionIdInput.value.trim();
     userId = userIdInput.value.trim();
-    if (!auctionId || !userId) return alert('Please enter both auction ID and user ID.');
+    if (!nextAuctionId || !userId) return alert('Please enter both auction ID and user ID.');
+    if (nextAuctionId !== auctionId) lastSeq = null;   // replay only makes sense for the same auction
+    auctionId = nextAuctionId;
 
     disable(connectBtn);
     updateConnStatus('connecting…');
@@ -79,7 +90,8 @@
     const scheme = location.protocol.startsWith('https') ? 'wss' : 'ws';
     const url = `${scheme}://${location.host}/ws` +
       `?auction_id=${encodeURIComponent(auctionId)}` +
-      `&user_id=${encodeURIComponent(userId)}`;
+      `&user_id=${encodeURIComponent(userId)}` +
+      (lastSeq !== null ? `&last_seq=${lastSeq}` : '');
 
     ws = new WebSocket(url);
     wsState = WS_STATE.INIT;
@@ -95,8 +107,10 @@
     retryDelay = 3_000;                      // reset back‑off
     log('📡 connected');
     updateConnStatus('connected');
+    bestRtt = Infinity;
+    sendWS('auctions/time', { client_time: Date.now() });   // clock sync
 
-    statusSec.hidden = bidSec.hidden = eventsSec.hidden = manageSec.hidden = false;
+    statusSec.hidden = bidSec.hidden = chatSec.hidden = eventsSec.hidden = manageSec.hidden = false;
   }
 
   function handleClose(ev) {
@@ -109,6 +123,7 @@
       log('🏁 auction ended – no reconnection');
       return;
     }
+    if (ev.code === 4000) return connect();   // gap resync: reconnect right away
     const delay = retryDelay;
     retryDelay = Math.min(retryDelay + 3_000, 30_000);  // exponential back‑off
 
@@ -122,36 +137,55 @@
    *  WS EVENT HANDLING  (server → client)
    * ------------------------------------------------------------ */
   function handleEvent(msg) {
+    const seq = +(msg.body?.seq ?? 0);
+    if (wsState !== WS_STATE.OPEN) return;   // resyncing, see below
+    if (seq && lastSeq !== null && seq > lastSeq + 1 && msg.event !== 'auctions/snapshot') {
+      // missed events: reconnect with last_seq to get them replayed
+      log(`⚠️ events ${lastSeq + 1}–${seq - 1} missing – resyncing`);
+      wsState = WS_STATE.CLOSING;
+      ws.close(4000, 'resync');
+      return;
+    }
+    if (seq && (lastSeq === null || seq > lastSeq || msg.event === 'auctions/snapshot')) lastSeq = seq;
+
     switch (msg.event) {
       case 'auctions/snapshot': applySnapshot(msg.body); break;
       case 'auctions/start': onStart(msg.body); break;
       case 'auctions/bid': onBid(msg.body); break;
       case 'auctions/bid-ack': onBidAck(); break;
       case 'auctions/stop': onStop(); break;
+      case 'auctions/presence': onPresence(msg.body); break;
+      case 'auctions/chat': onChat(msg.body); break;
+      case 'auctions/chat_retracted': log(`🗑️ chat #${msg.body.id} removed`); break;
+      case 'auctions/chat-ack': break;
+      case 'auctions/time':
+      case 'auctions/time-ack': onTime(msg.body); break;
+      case 'notifications/new': log(`🔔 ${msg.body.message}`); break;
       case 'error': onError(msg.body?.error); break;
       default: log(`ℹ️ ${JSON.stringify(msg)}`);
     }
   }
 
   function applySnapshot(snap) {
-    // Redis hash uses "ea" (ends‑at); DB snapshots use camel‑case. Handle both.
-    const ea = snap.ea ?? snap.endsAt ?? 0;
-    if (ea) {
-      endsAtUnix = +ea;
+    // Typed snapshot (schema: /schemas/events/snapshot)
+    if (snap.ends_at) {
+      endsAtUnix = +snap.ends_at;
       endsAtEl.textContent = tsToLocale(endsAtUnix);
       startCountdown();
     }
-    highBidEl.textContent = snap.hb ?? snap.highBid ?? '0';
-    highBidderEl.textContent = snap.hbid ?? snap.highBidder ?? '—';
-    stateEl.textContent = snap.st ?? snap.status ?? '—';
+    highBidEl.textContent = snap.high_bid ?? '0';
+    highBidderEl.textContent = snap.high_bidder || '—';
+    stateEl.textContent = snap.status ?? '—';
     log('📷 snapshot received');
+    if (snap.server_time) onTime({ server_time: snap.server_time });
+    (snap.chat ?? []).forEach(onChat);         // recent history, oldest first
 
     if (stateEl.textContent === 'FINISHED') onStop(); // straight to finished state
   }
 
   function onStart(body) {
     stateEl.textContent = 'RUNNING';
-    endsAtUnix = +body.endsAt;
+    endsAtUnix = +body.ends_at;
     endsAtEl.textContent = tsToLocale(endsAtUnix);
     startCountdown();
     log('🚀 auction started');
@@ -163,6 +197,31 @@
     log(`💰 ${amount} bid by user ${bidder}`);
   }
 
+  function onPresence({ viewers, bidders }) {
+    viewersEl.textContent = viewers;
+    biddersEl.textContent = bidders;
+  }
+
+  // Offset of the local clock against the server's (Redis TIME). Replies to
+  // our own request carry client_time, so the one‑way delay can be removed;
+  // the sample with the smallest round trip wins. Pushed samples (no
+  // client_time) only seed the offset until a measured one arrives.
+  function onTime({ server_time, client_time }) {
+    const now = Date.now();
+    if (client_time) {
+      const rtt = now - client_time;
+      if (rtt > bestRtt) return;
+      bestRtt = rtt;
+      clockOffset = server_time + rtt / 2 - now;
+    } else if (bestRtt === Infinity) {
+      clockOffset = server_time - now;
+    }
+  }
+
+  function onChat({ id, user_id, text }) {
+    log(`💬 #${id} ${user_id}: ${text}`);
+  }
+
   function onBidAck() {
     enable(bidBtn);
     errorEl.textContent = '';
@@ -201,6 +260,15 @@
     amountInput.value = '';                 // clear field (ack/error will re‑enable)
   }
 
+  function sendChat() {
+    if (wsState !== WS_STATE.OPEN) return alert('WebSocket not connected.');
+
+    const text = chatInput.value.trim();
+    if (!text) return;
+    sendWS('auctions/chat', { text });
+    chatInput.value = '';
+  }
+
   /* ---------- Manage (start / stop) ------------------------------------ */
   async function startAuction() {
     // 5 minute default duration from now
@@ -244,7 +312,7 @@
   function startCountdown() {
     stopCountdown();                        // ensure only one timer running
     const tick = () => {
-      const msLeft = endsAtUnix * 1_000 - Date.now();
+      const msLeft = endsAtUnix * 1_000 - (Date.now() + clockOffset);
       if (msLeft <= 0) { stopCountdown(); return; }
 
       const secs = Math.floor(msLeft / 1_000);